@@ -0,0 +1,409 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/octaviocarpes/go-http-servers/internal/auth"
+	"github.com/octaviocarpes/go-http-servers/internal/database"
+	server "github.com/octaviocarpes/go-http-servers/server"
+)
+
+var errBlocked = errors.New("you cannot interact with this user")
+
+// audience describes whose chirps a viewer should not see. Blocks work in
+// both directions and hide chirps everywhere; mutes only keep the muted
+// account out of feeds and notifications. The zero value hides nothing, which
+// is what anonymous requests get. Moderators also see the bodies of chirps
+// that moderation has hidden.
+type audience struct {
+	viewerID  string
+	blocked   map[string]bool
+	muted     map[string]bool
+	moderator bool
+}
+
+func (config *apiConfig) audienceFor(ctx context.Context, viewerID string) (audience, error) {
+	viewer := audience{viewerID: viewerID, blocked: map[string]bool{}, muted: map[string]bool{}}
+
+	if len(viewerID) == 0 {
+		return viewer, nil
+	}
+
+	rows, listError := config.db.ListHiddenAuthors(ctx, viewerID)
+
+	if listError != nil {
+		return audience{}, listError
+	}
+
+	for _, row := range rows {
+		if row.Blocked {
+			viewer.blocked[row.UserID] = true
+		} else {
+			viewer.muted[row.UserID] = true
+		}
+	}
+
+	return viewer, nil
+}
+
+// requestAudience is audienceFor for the caller of req. Endpoints that are
+// public treat a missing or invalid token as an anonymous viewer.
+func (config *apiConfig) requestAudience(req *http.Request) (audience, error) {
+	token, getTokenErr := auth.GetBearerToken(req.Header)
+
+	if getTokenErr != nil {
+		return config.audienceFor(req.Context(), "")
+	}
+
+	userUUID, invalidTokenError := auth.ValidateJWT(token, config.secret)
+
+	if invalidTokenError != nil {
+		return config.audienceFor(req.Context(), "")
+	}
+
+	return config.audienceFor(req.Context(), userUUID.String())
+}
+
+// audienceUpdatedAt is when something other than a chirp last changed what
+// viewer is shown, such as a block or mute. Anonymous viewers are always
+// shown the same.
+func (config *apiConfig) audienceUpdatedAt(ctx context.Context, viewer audience) (time.Time, error) {
+	if len(viewer.viewerID) == 0 {
+		return time.Time{}, nil
+	}
+
+	updatedAt, getError := config.db.GetAudienceUpdatedAt(ctx, viewer.viewerID)
+
+	if errors.Is(getError, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+
+	return updatedAt, getError
+}
+
+func (viewer audience) canSee(authorID string) bool {
+	return !viewer.blocked[authorID]
+}
+
+// canSeeChirp is canSee plus the rule that a scheduled chirp is only visible
+// to its author until it is published.
+func (viewer audience) canSeeChirp(chirp database.Chirp) bool {
+	if chirp.PublishAt.Valid && chirp.UserID != viewer.viewerID {
+		return false
+	}
+
+	return viewer.canSee(chirp.UserID)
+}
+
+// showsContent reports whether response keeps its media and poll, which go
+// wherever its body goes.
+func (viewer audience) showsContent(response chirpResponse) bool {
+	return !response.Deleted && !response.Unavailable && (!response.Hidden || viewer.moderator)
+}
+
+// feedHidden lists the authors that feed queries must leave out.
+func (viewer audience) feedHidden() []string {
+	hidden := make([]string, 0, len(viewer.blocked)+len(viewer.muted))
+
+	for userID := range viewer.blocked {
+		hidden = append(hidden, userID)
+	}
+
+	for userID := range viewer.muted {
+		hidden = append(hidden, userID)
+	}
+
+	return hidden
+}
+
+// checkNotBlocked returns errBlocked when either user has blocked the other.
+func checkNotBlocked(ctx context.Context, queries *database.Queries, userID, otherID string) error {
+	blocked, blockedError := queries.IsBlockedBetween(ctx, database.IsBlockedBetweenParams{
+		UserID:  userID,
+		OtherID: otherID,
+	})
+
+	if blockedError != nil {
+		return blockedError
+	}
+
+	if blocked {
+		return errBlocked
+	}
+
+	return nil
+}
+
+func sendBlockedError(blockedError error, responseWriter http.ResponseWriter) {
+	if errors.Is(blockedError, errBlocked) {
+		server.SendError(blockedError.Error(), http.StatusForbidden, responseWriter)
+		return
+	}
+
+	server.SendInternalServerError(blockedError, responseWriter)
+}
+
+func (config *apiConfig) blockUser(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	blocked, getUserError := config.lookupUser(req.Context(), req.PathValue("user"))
+
+	if getUserError != nil {
+		server.SendError("user not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	if blocked.ID == userUUID.String() {
+		server.SendError("you cannot block yourself", http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	tx, beginError := config.conn.BeginTx(req.Context(), nil)
+
+	if beginError != nil {
+		server.SendInternalServerError(beginError, responseWriter)
+		return
+	}
+
+	defer tx.Rollback()
+
+	queries := config.db.WithTx(tx)
+
+	blockError := queries.BlockUser(req.Context(), database.BlockUserParams{
+		BlockerID: userUUID.String(),
+		BlockedID: blocked.ID,
+	})
+
+	if blockError != nil {
+		server.SendInternalServerError(blockError, responseWriter)
+		return
+	}
+
+	// A block severs the follow graph both ways, along with whatever each side
+	// already has in their timeline from the other.
+	unfollowError := queries.DeleteFollowsBetween(req.Context(), database.DeleteFollowsBetweenParams{
+		UserID:  userUUID.String(),
+		OtherID: blocked.ID,
+	})
+
+	if unfollowError != nil {
+		server.SendInternalServerError(unfollowError, responseWriter)
+		return
+	}
+
+	for _, edge := range []database.PruneTimelineParams{
+		{UserID: userUUID.String(), AuthorID: blocked.ID},
+		{UserID: blocked.ID, AuthorID: userUUID.String()},
+	} {
+		if pruneError := queries.PruneTimeline(req.Context(), edge); pruneError != nil {
+			server.SendInternalServerError(pruneError, responseWriter)
+			return
+		}
+	}
+
+	if commitError := tx.Commit(); commitError != nil {
+		server.SendInternalServerError(commitError, responseWriter)
+		return
+	}
+
+	responseWriter.WriteHeader(http.StatusNoContent)
+}
+
+func (config *apiConfig) unblockUser(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	blocked, getUserError := config.lookupUser(req.Context(), req.PathValue("user"))
+
+	if getUserError != nil {
+		server.SendError("user not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	unblockError := config.db.UnblockUser(req.Context(), database.UnblockUserParams{
+		BlockerID: userUUID.String(),
+		BlockedID: blocked.ID,
+	})
+
+	if unblockError != nil {
+		server.SendInternalServerError(unblockError, responseWriter)
+		return
+	}
+
+	responseWriter.WriteHeader(http.StatusNoContent)
+}
+
+func (config *apiConfig) muteUser(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	muted, getUserError := config.lookupUser(req.Context(), req.PathValue("user"))
+
+	if getUserError != nil {
+		server.SendError("user not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	if muted.ID == userUUID.String() {
+		server.SendError("you cannot mute yourself", http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	muteError := config.db.MuteUser(req.Context(), database.MuteUserParams{
+		MuterID: userUUID.String(),
+		MutedID: muted.ID,
+	})
+
+	if muteError != nil {
+		server.SendInternalServerError(muteError, responseWriter)
+		return
+	}
+
+	responseWriter.WriteHeader(http.StatusNoContent)
+}
+
+func (config *apiConfig) unmuteUser(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	muted, getUserError := config.lookupUser(req.Context(), req.PathValue("user"))
+
+	if getUserError != nil {
+		server.SendError("user not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	unmuteError := config.db.UnmuteUser(req.Context(), database.UnmuteUserParams{
+		MuterID: userUUID.String(),
+		MutedID: muted.ID,
+	})
+
+	if unmuteError != nil {
+		server.SendInternalServerError(unmuteError, responseWriter)
+		return
+	}
+
+	responseWriter.WriteHeader(http.StatusNoContent)
+}
+
+func (config *apiConfig) listBlocks(responseWriter http.ResponseWriter, req *http.Request) {
+	config.listRelationships(responseWriter, req, func(ctx context.Context, params database.ListBlocksParams) ([]database.ListBlocksRow, error) {
+		return config.db.ListBlocks(ctx, params)
+	})
+}
+
+func (config *apiConfig) listMutes(responseWriter http.ResponseWriter, req *http.Request) {
+	config.listRelationships(responseWriter, req, func(ctx context.Context, params database.ListBlocksParams) ([]database.ListBlocksRow, error) {
+		rows, listError := config.db.ListMutes(ctx, database.ListMutesParams(params))
+
+		if listError != nil {
+			return nil, listError
+		}
+
+		blocks := make([]database.ListBlocksRow, len(rows))
+
+		for i, row := range rows {
+			blocks[i] = database.ListBlocksRow(row)
+		}
+
+		return blocks, nil
+	})
+}
+
+// listRelationships pages through the caller's own blocks or mutes; list
+// decides which.
+func (config *apiConfig) listRelationships(
+	responseWriter http.ResponseWriter,
+	req *http.Request,
+	list func(context.Context, database.ListBlocksParams) ([]database.ListBlocksRow, error),
+) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	query := req.URL.Query()
+
+	limit, limitError := server.ParseLimit(query, 50, 200)
+
+	if limitError != nil {
+		server.SendError(limitError.Error(), http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	beforeCreatedAt, beforeUserID, cursorError := server.ParseTimeCursor(query)
+
+	if cursorError != nil {
+		server.SendError(cursorError.Error(), http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	rows, listError := list(req.Context(), database.ListBlocksParams{
+		UserID:          userUUID.String(),
+		BeforeCreatedAt: beforeCreatedAt,
+		BeforeUserID:    beforeUserID,
+		PageSize:        limit + 1,
+	})
+
+	if listError != nil {
+		server.SendInternalServerError(listError, responseWriter)
+		return
+	}
+
+	nextCursor := ""
+
+	if len(rows) > int(limit) {
+		rows = rows[:limit]
+		last := rows[len(rows)-1]
+		nextCursor = server.EncodeTimeCursor(last.CreatedAt, last.ID)
+	}
+
+	type relationshipResponse struct {
+		ID          string    `json:"id"`
+		Handle      *string   `json:"handle"`
+		DisplayName string    `json:"display_name"`
+		CreatedAt   time.Time `json:"created_at"`
+	}
+
+	type relationshipsResponse struct {
+		Users      []relationshipResponse `json:"users"`
+		NextCursor string                 `json:"next_cursor,omitempty"`
+	}
+
+	response := relationshipsResponse{
+		Users:      make([]relationshipResponse, len(rows)),
+		NextCursor: nextCursor,
+	}
+
+	for i, row := range rows {
+		response.Users[i] = relationshipResponse{
+			ID:          row.ID,
+			DisplayName: row.DisplayName,
+			CreatedAt:   row.CreatedAt,
+		}
+
+		if row.Handle.Valid {
+			response.Users[i].Handle = &row.Handle.String
+		}
+	}
+
+	server.ResponseWithJson(response, http.StatusOK, responseWriter)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/octaviocarpes/go-http-servers/internal/database"
+	server "github.com/octaviocarpes/go-http-servers/server"
+)
+
+func (config *apiConfig) addBookmark(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	type addBookmarkBody struct {
+		ChirpID string `json:"chirp_id"`
+	}
+
+	decodedPayload, decodeError := server.DecodeBody[addBookmarkBody](req.Body)
+
+	if decodeError != nil {
+		server.SendError("invalid request body", http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	chirp, shareError := config.shareableChirp(req.Context(), decodedPayload.ChirpID)
+
+	if shareError != nil {
+		sendShareableChirpError(shareError, responseWriter)
+		return
+	}
+
+	addBookmarkError := config.db.AddBookmark(req.Context(), database.AddBookmarkParams{
+		UserID:  userUUID.String(),
+		ChirpID: chirp.ID,
+	})
+
+	if addBookmarkError != nil {
+		server.SendInternalServerError(addBookmarkError, responseWriter)
+		return
+	}
+
+	responseWriter.WriteHeader(http.StatusNoContent)
+}
+
+func (config *apiConfig) removeBookmark(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	removeBookmarkError := config.db.RemoveBookmark(req.Context(), database.RemoveBookmarkParams{
+		UserID:  userUUID.String(),
+		ChirpID: req.PathValue("chirp_id"),
+	})
+
+	if removeBookmarkError != nil {
+		server.SendInternalServerError(removeBookmarkError, responseWriter)
+		return
+	}
+
+	responseWriter.WriteHeader(http.StatusNoContent)
+}
+
+func (config *apiConfig) listBookmarks(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	query := req.URL.Query()
+
+	limit, limitError := server.ParseLimit(query, 50, 200)
+
+	if limitError != nil {
+		server.SendError(limitError.Error(), http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	beforeCreatedAt, beforeChirpID, cursorError := server.ParseTimeCursor(query)
+
+	if cursorError != nil {
+		server.SendError(cursorError.Error(), http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	viewer, audienceError := config.audienceFor(req.Context(), userUUID.String())
+
+	if audienceError != nil {
+		server.SendInternalServerError(audienceError, responseWriter)
+		return
+	}
+
+	bookmarks, listBookmarksError := config.db.ListBookmarks(req.Context(), database.ListBookmarksParams{
+		UserID:          userUUID.String(),
+		BeforeCreatedAt: beforeCreatedAt,
+		BeforeChirpID:   beforeChirpID,
+		PageSize:        limit + 1,
+	})
+
+	if listBookmarksError != nil {
+		server.SendInternalServerError(listBookmarksError, responseWriter)
+		return
+	}
+
+	nextCursor := ""
+
+	if len(bookmarks) > int(limit) {
+		bookmarks = bookmarks[:limit]
+		last := bookmarks[len(bookmarks)-1]
+		nextCursor = server.EncodeTimeCursor(last.BookmarkedAt, last.Chirp.ID)
+	}
+
+	chirps := make([]database.Chirp, len(bookmarks))
+
+	for i, bookmark := range bookmarks {
+		chirps[i] = bookmark.Chirp
+	}
+
+	// Chirps deleted or hidden by a block after they were bookmarked come back
+	// as placeholders so the user can still see and remove the bookmark.
+	chirpResponses, buildResponseError := config.buildChirpResponses(req.Context(), viewer, chirps)
+
+	if buildResponseError != nil {
+		server.SendInternalServerError(buildResponseError, responseWriter)
+		return
+	}
+
+	type bookmarkResponse struct {
+		Chirp        chirpResponse `json:"chirp"`
+		BookmarkedAt time.Time     `json:"bookmarked_at"`
+	}
+
+	type bookmarksResponse struct {
+		Bookmarks  []bookmarkResponse `json:"bookmarks"`
+		NextCursor string             `json:"next_cursor,omitempty"`
+	}
+
+	response := bookmarksResponse{
+		Bookmarks:  make([]bookmarkResponse, len(bookmarks)),
+		NextCursor: nextCursor,
+	}
+
+	for i, bookmark := range bookmarks {
+		response.Bookmarks[i] = bookmarkResponse{
+			Chirp:        chirpResponses[i],
+			BookmarkedAt: bookmark.BookmarkedAt,
+		}
+	}
+
+	server.ResponseWithJson(response, http.StatusOK, responseWriter)
+}
@@ -0,0 +1,656 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/octaviocarpes/go-http-servers/internal/database"
+	"github.com/octaviocarpes/go-http-servers/internal/duplicates"
+	server "github.com/octaviocarpes/go-http-servers/server"
+	utils "github.com/octaviocarpes/go-http-servers/utils"
+)
+
+var (
+	errChirpTooLong    = errors.New("Chirp is too long")
+	errParentNotFound  = errors.New("parent chirp not found")
+	errParentDeleted   = errors.New("parent chirp was deleted")
+	errPublishAtInPast = errors.New("publish_at must be in the future")
+	errContentRejected = errors.New("chirp contains content that is not allowed")
+	errSuspended       = errors.New("account is suspended")
+	errDuplicateChirp  = errors.New("chirp duplicates a recent chirp")
+)
+
+// chirpLengthError reports a body over the author's limit together with how
+// far over it is, so clients can show the budget without counting themselves.
+type chirpLengthError struct {
+	Limit  int
+	Length int
+}
+
+func (lengthError chirpLengthError) Error() string {
+	return errChirpTooLong.Error()
+}
+
+func (lengthError chirpLengthError) Is(target error) bool {
+	return target == errChirpTooLong
+}
+
+// chirpLimitFor is the chirp length limit for user's tier.
+func (config *apiConfig) chirpLimitFor(user database.User) int {
+	if user.IsChirpyRed.Bool {
+		return config.chirpyRedChirpLimit
+	}
+
+	return config.chirpLimit
+}
+
+// chirpInput is what a client sends to create a chirp, either directly or by
+// publishing a draft.
+type chirpInput struct {
+	Body      string     `json:"body"`
+	InReplyTo *string    `json:"in_reply_to"`
+	QuoteOf   *string    `json:"quote_of"`
+	MediaIDs  []string   `json:"media_ids"`
+	PublishAt *time.Time `json:"publish_at"`
+	Poll      *pollInput `json:"poll"`
+}
+
+// preparedChirp is a chirpInput that passed prepareChirp and is ready for
+// insertChirp.
+type preparedChirp struct {
+	params       database.CreateChirpParams
+	mediaIDs     []string
+	poll         *pollInput
+	flaggedWords []string
+	flagReasons  []string
+	fingerprint  *duplicates.Fingerprint
+	// duplicateKinds and duplicateAction are what checkDuplicates found,
+	// for recordDuplicates.
+	duplicateKinds  []duplicates.Kind
+	duplicateAction duplicates.Action
+}
+
+type chirpResponse struct {
+	ID           string          `json:"id"`
+	Body         string          `json:"body"`
+	UserID       string          `json:"user_id"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+	InReplyTo    *string         `json:"in_reply_to"`
+	RechirpOf    *chirpResponse  `json:"rechirp_of,omitempty"`
+	QuoteOf      *chirpResponse  `json:"quote_of,omitempty"`
+	ReplyCount   int64           `json:"reply_count"`
+	RechirpCount int64           `json:"rechirp_count"`
+	QuoteCount   int64           `json:"quote_count"`
+	LikeCount    int32           `json:"like_count"`
+	Deleted      bool            `json:"deleted,omitempty"`
+	Unavailable  bool            `json:"unavailable,omitempty"`
+	Hidden       bool            `json:"hidden,omitempty"`
+	Media        []mediaResponse `json:"media,omitempty"`
+	PublishAt    *time.Time      `json:"publish_at,omitempty"`
+	Poll         *pollResponse   `json:"poll,omitempty"`
+}
+
+// newChirpResponse maps a chirp row to its API shape. Deleted chirps keep
+// their place in a thread but lose their body and author.
+func newChirpResponse(chirp database.Chirp) chirpResponse {
+	response := chirpResponse{
+		ID:        chirp.ID,
+		Body:      chirp.Body,
+		UserID:    chirp.UserID,
+		CreatedAt: chirp.CreatedAt,
+		UpdatedAt: chirp.UpdatedAt,
+		LikeCount: chirp.LikeCount,
+	}
+
+	if chirp.InReplyTo.Valid {
+		inReplyTo := chirp.InReplyTo.String
+		response.InReplyTo = &inReplyTo
+	}
+
+	if chirp.PublishAt.Valid {
+		publishAt := chirp.PublishAt.Time
+		response.PublishAt = &publishAt
+	}
+
+	if chirp.DeletedAt.Valid {
+		response.Body = ""
+		response.UserID = ""
+		response.Deleted = true
+	}
+
+	return response
+}
+
+// buildChirpResponses maps chirps to their API shape, embeds the chirps that
+// rechirps and quotes point at, and fills in the counters that live outside
+// the chirps table. Chirps by authors on the other side of a block from viewer
+// are masked the same way deleted chirps are.
+func (config *apiConfig) buildChirpResponses(ctx context.Context, viewer audience, chirps []database.Chirp) ([]chirpResponse, error) {
+	if len(chirps) == 0 {
+		return []chirpResponse{}, nil
+	}
+
+	loaded := make(map[string]database.Chirp, len(chirps))
+
+	for _, chirp := range chirps {
+		loaded[chirp.ID] = chirp
+	}
+
+	missing := []string{}
+
+	for _, chirp := range chirps {
+		for _, shared := range []sql.NullString{chirp.RechirpOf, chirp.QuoteOf} {
+			if _, ok := loaded[shared.String]; shared.Valid && !ok && !slices.Contains(missing, shared.String) {
+				missing = append(missing, shared.String)
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		originals, originalsError := config.db.ListChirpsByIDs(ctx, missing)
+
+		if originalsError != nil {
+			return nil, originalsError
+		}
+
+		for _, original := range originals {
+			loaded[original.ID] = original
+		}
+	}
+
+	ids := make([]string, 0, len(loaded))
+
+	for id := range loaded {
+		ids = append(ids, id)
+	}
+
+	replyCounts, replyCountsError := config.db.ListReplyCounts(ctx, ids)
+
+	if replyCountsError != nil {
+		return nil, replyCountsError
+	}
+
+	shareCounts, shareCountsError := config.db.ListShareCounts(ctx, ids)
+
+	if shareCountsError != nil {
+		return nil, shareCountsError
+	}
+
+	responses := make(map[string]chirpResponse, len(loaded))
+
+	for id, chirp := range loaded {
+		response := newChirpResponse(chirp)
+
+		if !viewer.canSeeChirp(chirp) {
+			response.Body = ""
+			response.UserID = ""
+			response.Unavailable = true
+		}
+
+		if chirp.HiddenAt.Valid {
+			response.Hidden = true
+
+			if !viewer.moderator {
+				response.Body = ""
+				response.UserID = ""
+			}
+		}
+
+		responses[id] = response
+	}
+
+	for _, count := range replyCounts {
+		response := responses[count.ChirpID]
+		response.ReplyCount = count.ReplyCount
+		responses[count.ChirpID] = response
+	}
+
+	for _, count := range shareCounts {
+		response := responses[count.ChirpID]
+		response.RechirpCount = count.RechirpCount
+		response.QuoteCount = count.QuoteCount
+		responses[count.ChirpID] = response
+	}
+
+	attachments, attachmentsError := config.db.ListChirpMedia(ctx, ids)
+
+	if attachmentsError != nil {
+		return nil, attachmentsError
+	}
+
+	for _, attachment := range attachments {
+		response := responses[attachment.ChirpID]
+
+		if viewer.showsContent(response) {
+			response.Media = append(response.Media, newMediaResponse(attachment.Medium))
+			responses[attachment.ChirpID] = response
+		}
+	}
+
+	polls, pollsError := config.loadPolls(ctx, viewer.viewerID, ids)
+
+	if pollsError != nil {
+		return nil, pollsError
+	}
+
+	for chirpID, poll := range polls {
+		response := responses[chirpID]
+
+		if viewer.showsContent(response) {
+			response.Poll = poll
+			responses[chirpID] = response
+		}
+	}
+
+	result := make([]chirpResponse, len(chirps))
+
+	for i, chirp := range chirps {
+		result[i] = responses[chirp.ID]
+
+		if original, ok := responses[chirp.RechirpOf.String]; chirp.RechirpOf.Valid && ok {
+			result[i].RechirpOf = &original
+		}
+
+		if original, ok := responses[chirp.QuoteOf.String]; chirp.QuoteOf.Valid && ok {
+			result[i].QuoteOf = &original
+		}
+	}
+
+	return result, nil
+}
+
+// prepareChirp validates input on behalf of userID and returns what to
+// insert, with profanity already masked. Every way of creating a chirp goes
+// through it so the rules cannot drift apart.
+func (config *apiConfig) prepareChirp(ctx context.Context, userID string, input chirpInput) (preparedChirp, error) {
+	author, getAuthorError := config.db.GetUserByID(ctx, userID)
+
+	if getAuthorError != nil {
+		return preparedChirp{}, getAuthorError
+	}
+
+	if suspended(author) {
+		return preparedChirp{}, errSuspended
+	}
+
+	limit := config.chirpLimitFor(author)
+
+	if length := utils.ChirpLength(input.Body); length > limit {
+		return preparedChirp{}, chirpLengthError{Limit: limit, Length: length}
+	}
+
+	filtered := config.contentFilter.Load().Apply(input.Body)
+
+	if filtered.Rejected {
+		return preparedChirp{}, errContentRejected
+	}
+
+	var inReplyTo sql.NullString
+
+	if input.InReplyTo != nil {
+		parent, getParentError := config.db.GetChirpByID(ctx, *input.InReplyTo)
+
+		if getParentError != nil || parent.PublishAt.Valid {
+			return preparedChirp{}, errParentNotFound
+		}
+
+		if parent.DeletedAt.Valid || parent.HiddenAt.Valid {
+			return preparedChirp{}, errParentDeleted
+		}
+
+		if blockedError := checkNotBlocked(ctx, config.db, userID, parent.UserID); blockedError != nil {
+			return preparedChirp{}, blockedError
+		}
+
+		inReplyTo = sql.NullString{String: parent.ID, Valid: true}
+	}
+
+	var quoteOf sql.NullString
+
+	if input.QuoteOf != nil {
+		quoted, shareError := config.shareableChirp(ctx, *input.QuoteOf)
+
+		if shareError != nil {
+			return preparedChirp{}, shareError
+		}
+
+		if blockedError := checkNotBlocked(ctx, config.db, userID, quoted.UserID); blockedError != nil {
+			return preparedChirp{}, blockedError
+		}
+
+		quoteOf = sql.NullString{String: quoted.ID, Valid: true}
+	}
+
+	if mediaError := config.checkChirpMedia(ctx, userID, input.MediaIDs); mediaError != nil {
+		return preparedChirp{}, mediaError
+	}
+
+	var publishAt sql.NullTime
+
+	if input.PublishAt != nil {
+		if !input.PublishAt.After(time.Now()) {
+			return preparedChirp{}, errPublishAtInPast
+		}
+
+		publishAt = sql.NullTime{Time: input.PublishAt.UTC(), Valid: true}
+	}
+
+	var poll *pollInput
+
+	if input.Poll != nil {
+		opensAt := time.Now()
+
+		if publishAt.Valid {
+			opensAt = publishAt.Time
+		}
+
+		validated, pollError := validatePoll(*input.Poll, opensAt)
+
+		if pollError != nil {
+			return preparedChirp{}, pollError
+		}
+
+		poll = &validated
+	}
+
+	flagReasons := []string{}
+
+	if filtered.Flagged {
+		flagReasons = append(flagReasons, flagReasonContentFilter)
+	}
+
+	fingerprint := duplicates.Compute(input.Body)
+	duplicateKinds, duplicateAction, checkError := config.checkDuplicates(ctx, userID, fingerprint)
+
+	if checkError != nil {
+		return preparedChirp{}, checkError
+	}
+
+	if duplicateAction == duplicates.ActionReject {
+		config.duplicateMetrics.Record(duplicateKinds, duplicateAction)
+		return preparedChirp{}, errDuplicateChirp
+	}
+
+	if duplicateAction == duplicates.ActionFlag {
+		for _, kind := range duplicateKinds {
+			flagReasons = append(flagReasons, string(kind))
+		}
+	}
+
+	var storedFingerprint *duplicates.Fingerprint
+
+	if fingerprint.Tokens >= config.duplicateDetector.MinTokens {
+		storedFingerprint = &fingerprint
+	}
+
+	return preparedChirp{
+		params: database.CreateChirpParams{
+			Body:      filtered.Body,
+			UserID:    userID,
+			InReplyTo: inReplyTo,
+			QuoteOf:   quoteOf,
+			PublishAt: publishAt,
+		},
+		mediaIDs:        input.MediaIDs,
+		poll:            poll,
+		flaggedWords:    flaggedWords(filtered),
+		flagReasons:     flagReasons,
+		fingerprint:     storedFingerprint,
+		duplicateKinds:  duplicateKinds,
+		duplicateAction: duplicateAction,
+	}, nil
+}
+
+func sendChirpInputError(inputError error, responseWriter http.ResponseWriter) {
+	var lengthError chirpLengthError
+
+	if errors.As(inputError, &lengthError) {
+		type chirpTooLongResponse struct {
+			Error     string `json:"error"`
+			Limit     int    `json:"limit"`
+			Length    int    `json:"length"`
+			Remaining int    `json:"remaining"`
+		}
+
+		server.ResponseWithJson(chirpTooLongResponse{
+			Error:     lengthError.Error(),
+			Limit:     lengthError.Limit,
+			Length:    lengthError.Length,
+			Remaining: lengthError.Limit - lengthError.Length,
+		}, http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	switch {
+	case errors.Is(inputError, errPublishAtInPast), errors.Is(inputError, errContentRejected),
+		errors.Is(inputError, errPollOptionCount), errors.Is(inputError, errPollOptionInvalid), errors.Is(inputError, errPollClosesAt):
+		server.SendError(inputError.Error(), http.StatusBadRequest, responseWriter)
+	case errors.Is(inputError, errDuplicateChirp):
+		server.SendError(inputError.Error(), http.StatusConflict, responseWriter)
+	case errors.Is(inputError, errSuspended):
+		server.SendError(inputError.Error(), http.StatusForbidden, responseWriter)
+	case errors.Is(inputError, errParentNotFound):
+		server.SendError(inputError.Error(), http.StatusNotFound, responseWriter)
+	case errors.Is(inputError, errParentDeleted):
+		server.SendError(inputError.Error(), http.StatusGone, responseWriter)
+	case errors.Is(inputError, errBlocked):
+		sendBlockedError(inputError, responseWriter)
+	case errors.Is(inputError, errChirpNotFound), errors.Is(inputError, errChirpDeleted):
+		sendShareableChirpError(inputError, responseWriter)
+	case errors.Is(inputError, errTooManyMedia), errors.Is(inputError, errDuplicateMedia), errors.Is(inputError, errMediaNotFound),
+		errors.Is(inputError, errMediaNotOwned), errors.Is(inputError, errMediaUnavailable):
+		sendChirpMediaError(inputError, responseWriter)
+	default:
+		server.SendInternalServerError(inputError, responseWriter)
+	}
+}
+
+// insertChirp saves a chirp together with the rows derived from it, so a
+// chirp is never visible without its hashtags, mentions and notifications.
+// Scheduled chirps only get their media now; the rest waits for
+// publishScheduledChirps.
+func (config *apiConfig) insertChirp(ctx context.Context, prepared preparedChirp) (database.Chirp, error) {
+	tx, beginError := config.conn.BeginTx(ctx, nil)
+
+	if beginError != nil {
+		return database.Chirp{}, beginError
+	}
+
+	defer tx.Rollback()
+
+	chirp, createChirpError := createChirpTx(ctx, config.db.WithTx(tx), prepared)
+
+	if createChirpError != nil {
+		return database.Chirp{}, createChirpError
+	}
+
+	return chirp, tx.Commit()
+}
+
+// createChirpTx is insertChirp for callers that already hold a transaction.
+func createChirpTx(ctx context.Context, queries *database.Queries, prepared preparedChirp) (database.Chirp, error) {
+	chirp, createChirpError := queries.CreateChirp(ctx, prepared.params)
+
+	if createChirpError != nil {
+		return database.Chirp{}, createChirpError
+	}
+
+	if mediaIDs := prepared.mediaIDs; len(mediaIDs) > 0 {
+		attached, attachError := queries.AttachChirpMedia(ctx, database.AttachChirpMediaParams{
+			ChirpID:  chirp.ID,
+			MediaIds: mediaIDs,
+			UserID:   chirp.UserID,
+		})
+
+		if attachError != nil {
+			return database.Chirp{}, attachError
+		}
+
+		// Another chirp claimed one of the media between the check and now.
+		if attached != int64(len(mediaIDs)) {
+			return database.Chirp{}, errMediaUnavailable
+		}
+	}
+
+	if len(prepared.flagReasons) > 0 {
+		flagError := queries.FlagChirp(ctx, database.FlagChirpParams{
+			ChirpID: chirp.ID,
+			Words:   prepared.flaggedWords,
+			Reasons: prepared.flagReasons,
+		})
+
+		if flagError != nil {
+			return database.Chirp{}, flagError
+		}
+	}
+
+	if prepared.fingerprint != nil {
+		fingerprintError := queries.CreateChirpFingerprint(ctx, database.CreateChirpFingerprintParams{
+			ChirpID: chirp.ID,
+			UserID:  chirp.UserID,
+			Hash:    prepared.fingerprint.Hash,
+			Simhash: int64(prepared.fingerprint.Simhash),
+		})
+
+		if fingerprintError != nil {
+			return database.Chirp{}, fingerprintError
+		}
+	}
+
+	if prepared.poll != nil {
+		createPollError := queries.CreatePoll(ctx, database.CreatePollParams{
+			ChirpID:  chirp.ID,
+			ClosesAt: prepared.poll.ClosesAt.UTC(),
+			Options:  prepared.poll.Options,
+		})
+
+		if createPollError != nil {
+			return database.Chirp{}, createPollError
+		}
+	}
+
+	if !chirp.PublishAt.Valid {
+		if effectsError := applyChirpEffects(ctx, queries, chirp); effectsError != nil {
+			return database.Chirp{}, effectsError
+		}
+	}
+
+	return chirp, nil
+}
+
+// applyChirpEffects runs everything that happens when a chirp goes public:
+// timeline fan-out, hashtags, mentions, the reply notification, the chirp
+// stream and delivery to remote followers.
+func applyChirpEffects(ctx context.Context, queries *database.Queries, chirp database.Chirp) error {
+	if fanOutError := queries.FanOutChirp(ctx, chirp.ID); fanOutError != nil {
+		return fanOutError
+	}
+
+	if tags := utils.ExtractHashtags(chirp.Body); len(tags) > 0 {
+		addHashtagsError := queries.AddChirpHashtags(ctx, database.AddChirpHashtagsParams{
+			ChirpID: chirp.ID,
+			Tags:    tags,
+		})
+
+		if addHashtagsError != nil {
+			return addHashtagsError
+		}
+	}
+
+	if mentions := utils.ExtractMentions(chirp.Body); len(mentions) > 0 {
+		mentionedIDs, resolveError := resolveMentions(ctx, queries, chirp.UserID, mentions)
+
+		if resolveError != nil {
+			return resolveError
+		}
+
+		addMentionsError := queries.AddChirpMentions(ctx, database.AddChirpMentionsParams{
+			ChirpID: chirp.ID,
+			UserIds: mentionedIDs,
+		})
+
+		if addMentionsError != nil {
+			return addMentionsError
+		}
+
+		for _, mentionedID := range mentionedIDs {
+			if notifyError := notify(ctx, queries, notificationMention, mentionedID, chirp.UserID, chirp.ID); notifyError != nil {
+				return notifyError
+			}
+		}
+	}
+
+	if chirp.InReplyTo.Valid {
+		parent, getParentError := queries.GetChirpByID(ctx, chirp.InReplyTo.String)
+
+		if getParentError != nil {
+			return getParentError
+		}
+
+		if notifyError := notify(ctx, queries, notificationReply, parent.UserID, chirp.UserID, chirp.ID); notifyError != nil {
+			return notifyError
+		}
+	}
+
+	if !chirp.RechirpOf.Valid {
+		if federateError := federateChirp(ctx, queries, activityCreate, chirp.UserID, chirp.ID); federateError != nil {
+			return federateError
+		}
+	}
+
+	// The chirp it replies to or quotes now counts one more.
+	if touchError := queries.TouchChirpReferences(ctx, chirp.ID); touchError != nil {
+		return touchError
+	}
+
+	return notifyChirpEvent(ctx, queries, chirpEventCreated, chirp.ID, chirp.UserID)
+}
+
+// chirpsLastModified is when anything shown in responses last changed: the
+// chirps, the chirps they embed and polls that have closed since.
+func chirpsLastModified(responses []chirpResponse) time.Time {
+	var lastModified time.Time
+
+	for _, response := range responses {
+		lastModified = latest(lastModified, response.UpdatedAt)
+
+		if response.Poll != nil && response.Poll.Closed {
+			lastModified = latest(lastModified, response.Poll.ClosesAt)
+		}
+
+		for _, embedded := range []*chirpResponse{response.RechirpOf, response.QuoteOf} {
+			if embedded != nil {
+				lastModified = latest(lastModified, chirpsLastModified([]chirpResponse{*embedded}))
+			}
+		}
+	}
+
+	return lastModified
+}
+
+func latest(times ...time.Time) time.Time {
+	var result time.Time
+
+	for _, t := range times {
+		if t.After(result) {
+			result = t
+		}
+	}
+
+	return result
+}
+
+func (config *apiConfig) buildChirpResponse(ctx context.Context, viewer audience, chirp database.Chirp) (chirpResponse, error) {
+	response, buildError := config.buildChirpResponses(ctx, viewer, []database.Chirp{chirp})
+
+	if buildError != nil {
+		return chirpResponse{}, buildError
+	}
+
+	return response[0], nil
+}
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/octaviocarpes/go-http-servers/internal/contentfilter"
+	"github.com/octaviocarpes/go-http-servers/internal/database"
+	server "github.com/octaviocarpes/go-http-servers/server"
+)
+
+const (
+	maxFilterPatternLength = 64
+
+	// flagReasonContentFilter is the moderation flag reason for chirps a
+	// flag rule matched.
+	flagReasonContentFilter = "content_filter"
+)
+
+type contentFilterRuleResponse struct {
+	ID        string    `json:"id"`
+	Pattern   string    `json:"pattern"`
+	Action    string    `json:"action"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func newContentFilterRuleResponse(rule database.ContentFilterRule) contentFilterRuleResponse {
+	return contentFilterRuleResponse{
+		ID:        rule.ID,
+		Pattern:   rule.Pattern,
+		Action:    rule.Action,
+		CreatedAt: rule.CreatedAt,
+		UpdatedAt: rule.UpdatedAt,
+	}
+}
+
+// reloadContentFilter rebuilds the filter from the rules table and swaps it
+// in. Requests already running keep the filter they started with.
+func (config *apiConfig) reloadContentFilter(ctx context.Context) error {
+	rows, listError := config.db.ListContentFilterRules(ctx)
+
+	if listError != nil {
+		return listError
+	}
+
+	rules := make([]contentfilter.Rule, len(rows))
+
+	for i, row := range rows {
+		rules[i] = contentfilter.Rule{ID: row.ID, Pattern: row.Pattern, Action: contentfilter.Action(row.Action)}
+	}
+
+	config.contentFilter.Store(contentfilter.New(rules))
+
+	return nil
+}
+
+// refreshContentFilter picks up rule changes made through other instances.
+// The instance that handled the admin request reloads right away.
+func (config *apiConfig) refreshContentFilter(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if reloadError := config.reloadContentFilter(ctx); reloadError != nil {
+			log.Printf("failed to reload content filter rules: %v\n", reloadError)
+		}
+	}
+}
+
+func flaggedWords(result contentfilter.Result) []string {
+	words := []string{}
+
+	for _, match := range result.Matches {
+		if match.Action == contentfilter.ActionFlag {
+			words = append(words, match.Word)
+		}
+	}
+
+	return words
+}
+
+type contentFilterRuleBody struct {
+	Pattern string `json:"pattern"`
+	Action  string `json:"action"`
+}
+
+// decodeContentFilterRule reads a rule from req. A pattern is a single word;
+// matching already handles case, accents and leetspeak, so variants need no
+// rules of their own.
+func decodeContentFilterRule(responseWriter http.ResponseWriter, req *http.Request) (contentFilterRuleBody, bool) {
+	decodedPayload, decodeError := server.DecodeBody[contentFilterRuleBody](req.Body)
+
+	if decodeError != nil {
+		server.SendError("invalid request body", http.StatusBadRequest, responseWriter)
+		return contentFilterRuleBody{}, false
+	}
+
+	pattern := strings.ToLower(strings.TrimSpace(decodedPayload.Pattern))
+	length := utf8.RuneCountInString(pattern)
+
+	if length == 0 || length > maxFilterPatternLength || strings.IndexFunc(pattern, unicode.IsSpace) >= 0 || len(contentfilter.Normalize(pattern)) == 0 {
+		server.SendError("pattern must be a single word of at most 64 characters", http.StatusBadRequest, responseWriter)
+		return contentFilterRuleBody{}, false
+	}
+
+	if !contentfilter.Action(decodedPayload.Action).Valid() {
+		server.SendError("action must be mask, reject or flag", http.StatusBadRequest, responseWriter)
+		return contentFilterRuleBody{}, false
+	}
+
+	return contentFilterRuleBody{Pattern: pattern, Action: decodedPayload.Action}, true
+}
+
+func (config *apiConfig) reloadAfterRuleChange(ctx context.Context) {
+	if reloadError := config.reloadContentFilter(ctx); reloadError != nil {
+		log.Printf("failed to reload content filter rules: %v\n", reloadError)
+	}
+}
+
+func (config *apiConfig) listContentFilterRules(responseWriter http.ResponseWriter, req *http.Request) {
+	if _, ok := config.authenticateAdmin(responseWriter, req); !ok {
+		return
+	}
+
+	rules, listError := config.db.ListContentFilterRules(req.Context())
+
+	if listError != nil {
+		server.SendInternalServerError(listError, responseWriter)
+		return
+	}
+
+	response := make([]contentFilterRuleResponse, len(rules))
+
+	for i, rule := range rules {
+		response[i] = newContentFilterRuleResponse(rule)
+	}
+
+	server.ResponseWithJson(response, http.StatusOK, responseWriter)
+}
+
+func (config *apiConfig) createContentFilterRule(responseWriter http.ResponseWriter, req *http.Request) {
+	if _, ok := config.authenticateAdmin(responseWriter, req); !ok {
+		return
+	}
+
+	body, ok := decodeContentFilterRule(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	rule, createError := config.db.CreateContentFilterRule(req.Context(), database.CreateContentFilterRuleParams{
+		Pattern: body.Pattern,
+		Action:  body.Action,
+	})
+
+	if isUniqueViolation(createError) {
+		server.SendError("a rule for this pattern already exists", http.StatusConflict, responseWriter)
+		return
+	}
+
+	if createError != nil {
+		server.SendInternalServerError(createError, responseWriter)
+		return
+	}
+
+	config.reloadAfterRuleChange(req.Context())
+
+	server.ResponseWithJson(newContentFilterRuleResponse(rule), http.StatusCreated, responseWriter)
+}
+
+func (config *apiConfig) updateContentFilterRule(responseWriter http.ResponseWriter, req *http.Request) {
+	if _, ok := config.authenticateAdmin(responseWriter, req); !ok {
+		return
+	}
+
+	body, ok := decodeContentFilterRule(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	rule, updateError := config.db.UpdateContentFilterRule(req.Context(), database.UpdateContentFilterRuleParams{
+		Pattern: body.Pattern,
+		Action:  body.Action,
+		ID:      req.PathValue("id"),
+	})
+
+	if errors.Is(updateError, sql.ErrNoRows) {
+		server.SendError("rule not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	if isUniqueViolation(updateError) {
+		server.SendError("a rule for this pattern already exists", http.StatusConflict, responseWriter)
+		return
+	}
+
+	if updateError != nil {
+		server.SendInternalServerError(updateError, responseWriter)
+		return
+	}
+
+	config.reloadAfterRuleChange(req.Context())
+
+	server.ResponseWithJson(newContentFilterRuleResponse(rule), http.StatusOK, responseWriter)
+}
+
+func (config *apiConfig) deleteContentFilterRule(responseWriter http.ResponseWriter, req *http.Request) {
+	if _, ok := config.authenticateAdmin(responseWriter, req); !ok {
+		return
+	}
+
+	deleted, deleteError := config.db.DeleteContentFilterRule(req.Context(), req.PathValue("id"))
+
+	if deleteError != nil {
+		server.SendInternalServerError(deleteError, responseWriter)
+		return
+	}
+
+	if deleted == 0 {
+		server.SendError("rule not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	config.reloadAfterRuleChange(req.Context())
+
+	responseWriter.WriteHeader(http.StatusNoContent)
+}
+
+func (config *apiConfig) listFlaggedChirps(responseWriter http.ResponseWriter, req *http.Request) {
+	if _, ok := config.authenticateAdmin(responseWriter, req); !ok {
+		return
+	}
+
+	query := req.URL.Query()
+
+	limit, limitError := server.ParseLimit(query, 50, 200)
+
+	if limitError != nil {
+		server.SendError(limitError.Error(), http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	beforeCreatedAt, beforeChirpID, cursorError := server.ParseTimeCursor(query)
+
+	if cursorError != nil {
+		server.SendError(cursorError.Error(), http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	flags, listError := config.db.ListFlaggedChirps(req.Context(), database.ListFlaggedChirpsParams{
+		BeforeCreatedAt: beforeCreatedAt,
+		BeforeChirpID:   beforeChirpID,
+		PageSize:        limit + 1,
+	})
+
+	if listError != nil {
+		server.SendInternalServerError(listError, responseWriter)
+		return
+	}
+
+	nextCursor := ""
+
+	if len(flags) > int(limit) {
+		flags = flags[:limit]
+		last := flags[len(flags)-1]
+		nextCursor = server.EncodeTimeCursor(last.FlaggedAt, last.Chirp.ID)
+	}
+
+	chirps := make([]database.Chirp, len(flags))
+
+	for i, flag := range flags {
+		chirps[i] = flag.Chirp
+	}
+
+	// Moderators see everything, so the chirps are built for an audience
+	// that hides nothing.
+	chirpResponses, buildResponseError := config.buildChirpResponses(req.Context(), audience{moderator: true}, chirps)
+
+	if buildResponseError != nil {
+		server.SendInternalServerError(buildResponseError, responseWriter)
+		return
+	}
+
+	type flagResponse struct {
+		Chirp     chirpResponse `json:"chirp"`
+		Words     []string      `json:"words"`
+		Reasons   []string      `json:"reasons"`
+		FlaggedAt time.Time     `json:"flagged_at"`
+	}
+
+	type flagsResponse struct {
+		Flags      []flagResponse `json:"flags"`
+		NextCursor string         `json:"next_cursor,omitempty"`
+	}
+
+	response := flagsResponse{
+		Flags:      make([]flagResponse, len(flags)),
+		NextCursor: nextCursor,
+	}
+
+	for i, flag := range flags {
+		response.Flags[i] = flagResponse{
+			Chirp:     chirpResponses[i],
+			Words:     flag.Words,
+			Reasons:   flag.Reasons,
+			FlaggedAt: flag.FlaggedAt,
+		}
+	}
+
+	server.ResponseWithJson(response, http.StatusOK, responseWriter)
+}
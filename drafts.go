@@ -0,0 +1,395 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/octaviocarpes/go-http-servers/internal/database"
+	server "github.com/octaviocarpes/go-http-servers/server"
+)
+
+// maxDraftBodyLength only keeps drafts from becoming free storage. The real
+// chirp limit is enforced when a draft is published.
+const maxDraftBodyLength = 4096
+
+type draftResponse struct {
+	ID        string     `json:"id"`
+	Body      string     `json:"body"`
+	InReplyTo *string    `json:"in_reply_to"`
+	QuoteOf   *string    `json:"quote_of"`
+	MediaIDs  []string   `json:"media_ids"`
+	PublishAt *time.Time `json:"publish_at,omitempty"`
+	Poll      *pollInput `json:"poll,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func newDraftResponse(draft database.Draft) draftResponse {
+	input := draftInput(draft)
+
+	return draftResponse{
+		ID:        draft.ID,
+		Body:      draft.Body,
+		InReplyTo: input.InReplyTo,
+		QuoteOf:   input.QuoteOf,
+		MediaIDs:  input.MediaIDs,
+		PublishAt: input.PublishAt,
+		Poll:      input.Poll,
+		CreatedAt: draft.CreatedAt,
+		UpdatedAt: draft.UpdatedAt,
+	}
+}
+
+// draftInput turns a stored draft back into the request createChirp would
+// have received.
+func draftInput(draft database.Draft) chirpInput {
+	input := chirpInput{
+		Body:     draft.Body,
+		MediaIDs: draft.MediaIds,
+	}
+
+	if input.MediaIDs == nil {
+		input.MediaIDs = []string{}
+	}
+
+	if draft.InReplyTo.Valid {
+		inReplyTo := draft.InReplyTo.String
+		input.InReplyTo = &inReplyTo
+	}
+
+	if draft.QuoteOf.Valid {
+		quoteOf := draft.QuoteOf.String
+		input.QuoteOf = &quoteOf
+	}
+
+	if draft.PublishAt.Valid {
+		publishAt := draft.PublishAt.Time
+		input.PublishAt = &publishAt
+	}
+
+	if draft.PollClosesAt.Valid {
+		input.Poll = &pollInput{Options: draft.PollOptions, ClosesAt: draft.PollClosesAt.Time}
+	}
+
+	return input
+}
+
+// decodeDraftBody reads a draft from req. Drafts may be half-written, so only
+// the limits that protect storage are checked here.
+func decodeDraftBody(responseWriter http.ResponseWriter, req *http.Request) (chirpInput, bool) {
+	decodedPayload, decodeError := server.DecodeBody[chirpInput](req.Body)
+
+	if decodeError != nil {
+		server.SendError("invalid request body", http.StatusBadRequest, responseWriter)
+		return chirpInput{}, false
+	}
+
+	if len(decodedPayload.Body) > maxDraftBodyLength {
+		server.SendError("draft is too long", http.StatusBadRequest, responseWriter)
+		return chirpInput{}, false
+	}
+
+	if len(decodedPayload.MediaIDs) > maxChirpMedia {
+		server.SendError(errTooManyMedia.Error(), http.StatusBadRequest, responseWriter)
+		return chirpInput{}, false
+	}
+
+	if decodedPayload.Poll != nil && len(decodedPayload.Poll.Options) > maxPollOptions {
+		server.SendError(errPollOptionCount.Error(), http.StatusBadRequest, responseWriter)
+		return chirpInput{}, false
+	}
+
+	if decodedPayload.MediaIDs == nil {
+		decodedPayload.MediaIDs = []string{}
+	}
+
+	return decodedPayload, true
+}
+
+// draftPoll splits a poll into the columns drafts store it in.
+func draftPoll(poll *pollInput) ([]string, sql.NullTime) {
+	if poll == nil {
+		return nil, sql.NullTime{}
+	}
+
+	return poll.Options, sql.NullTime{Time: poll.ClosesAt.UTC(), Valid: true}
+}
+
+func nullString(value *string) sql.NullString {
+	if value == nil {
+		return sql.NullString{}
+	}
+
+	return sql.NullString{String: *value, Valid: true}
+}
+
+func nullTime(value *time.Time) sql.NullTime {
+	if value == nil {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{Time: value.UTC(), Valid: true}
+}
+
+func (config *apiConfig) createDraft(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	input, ok := decodeDraftBody(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	pollOptions, pollClosesAt := draftPoll(input.Poll)
+
+	draft, createDraftError := config.db.CreateDraft(req.Context(), database.CreateDraftParams{
+		UserID:       userUUID.String(),
+		Body:         input.Body,
+		InReplyTo:    nullString(input.InReplyTo),
+		QuoteOf:      nullString(input.QuoteOf),
+		MediaIds:     input.MediaIDs,
+		PublishAt:    nullTime(input.PublishAt),
+		PollOptions:  pollOptions,
+		PollClosesAt: pollClosesAt,
+	})
+
+	if createDraftError != nil {
+		server.SendInternalServerError(createDraftError, responseWriter)
+		return
+	}
+
+	server.ResponseWithJson(newDraftResponse(draft), http.StatusCreated, responseWriter)
+}
+
+func (config *apiConfig) updateDraft(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	input, ok := decodeDraftBody(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	pollOptions, pollClosesAt := draftPoll(input.Poll)
+
+	draft, updateDraftError := config.db.UpdateDraft(req.Context(), database.UpdateDraftParams{
+		Body:         input.Body,
+		InReplyTo:    nullString(input.InReplyTo),
+		QuoteOf:      nullString(input.QuoteOf),
+		MediaIds:     input.MediaIDs,
+		PublishAt:    nullTime(input.PublishAt),
+		PollOptions:  pollOptions,
+		PollClosesAt: pollClosesAt,
+		ID:           req.PathValue("id"),
+		UserID:       userUUID.String(),
+	})
+
+	if errors.Is(updateDraftError, sql.ErrNoRows) {
+		server.SendError("draft not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	if updateDraftError != nil {
+		server.SendInternalServerError(updateDraftError, responseWriter)
+		return
+	}
+
+	server.ResponseWithJson(newDraftResponse(draft), http.StatusOK, responseWriter)
+}
+
+func (config *apiConfig) getDraft(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	draft, getDraftError := config.db.GetDraft(req.Context(), database.GetDraftParams{
+		ID:     req.PathValue("id"),
+		UserID: userUUID.String(),
+	})
+
+	if errors.Is(getDraftError, sql.ErrNoRows) {
+		server.SendError("draft not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	if getDraftError != nil {
+		server.SendInternalServerError(getDraftError, responseWriter)
+		return
+	}
+
+	server.ResponseWithJson(newDraftResponse(draft), http.StatusOK, responseWriter)
+}
+
+func (config *apiConfig) listDrafts(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	query := req.URL.Query()
+
+	limit, limitError := server.ParseLimit(query, 50, 200)
+
+	if limitError != nil {
+		server.SendError(limitError.Error(), http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	beforeUpdatedAt, beforeID, cursorError := server.ParseTimeCursor(query)
+
+	if cursorError != nil {
+		server.SendError(cursorError.Error(), http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	drafts, listDraftsError := config.db.ListDrafts(req.Context(), database.ListDraftsParams{
+		UserID:          userUUID.String(),
+		BeforeUpdatedAt: beforeUpdatedAt,
+		BeforeID:        beforeID,
+		PageSize:        limit + 1,
+	})
+
+	if listDraftsError != nil {
+		server.SendInternalServerError(listDraftsError, responseWriter)
+		return
+	}
+
+	nextCursor := ""
+
+	if len(drafts) > int(limit) {
+		drafts = drafts[:limit]
+		last := drafts[len(drafts)-1]
+		nextCursor = server.EncodeTimeCursor(last.UpdatedAt, last.ID)
+	}
+
+	type draftsResponse struct {
+		Drafts     []draftResponse `json:"drafts"`
+		NextCursor string          `json:"next_cursor,omitempty"`
+	}
+
+	response := draftsResponse{
+		Drafts:     make([]draftResponse, len(drafts)),
+		NextCursor: nextCursor,
+	}
+
+	for i, draft := range drafts {
+		response.Drafts[i] = newDraftResponse(draft)
+	}
+
+	server.ResponseWithJson(response, http.StatusOK, responseWriter)
+}
+
+func (config *apiConfig) deleteDraft(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	deleted, deleteDraftError := config.db.DeleteDraft(req.Context(), database.DeleteDraftParams{
+		ID:     req.PathValue("id"),
+		UserID: userUUID.String(),
+	})
+
+	if deleteDraftError != nil {
+		server.SendInternalServerError(deleteDraftError, responseWriter)
+		return
+	}
+
+	if deleted == 0 {
+		server.SendError("draft not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	responseWriter.WriteHeader(http.StatusNoContent)
+}
+
+// publishDraft turns a draft into a chirp. The draft is deleted in the same
+// transaction that creates the chirp: a failed validation leaves the draft in
+// place, and two concurrent publishes cannot both succeed because the second
+// one finds the row already gone.
+func (config *apiConfig) publishDraft(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	if !config.limitChirps(responseWriter, req, userUUID.String()) {
+		return
+	}
+
+	tx, beginError := config.conn.BeginTx(req.Context(), nil)
+
+	if beginError != nil {
+		server.SendInternalServerError(beginError, responseWriter)
+		return
+	}
+
+	defer tx.Rollback()
+
+	queries := config.db.WithTx(tx)
+
+	draft, takeDraftError := queries.TakeDraft(req.Context(), database.TakeDraftParams{
+		ID:     req.PathValue("id"),
+		UserID: userUUID.String(),
+	})
+
+	if errors.Is(takeDraftError, sql.ErrNoRows) {
+		server.SendError("draft not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	if takeDraftError != nil {
+		server.SendInternalServerError(takeDraftError, responseWriter)
+		return
+	}
+
+	prepared, prepareError := config.prepareChirp(req.Context(), userUUID.String(), draftInput(draft))
+
+	if prepareError != nil {
+		sendChirpInputError(prepareError, responseWriter)
+		return
+	}
+
+	chirp, createChirpError := createChirpTx(req.Context(), queries, prepared)
+
+	if errors.Is(createChirpError, errMediaUnavailable) {
+		sendChirpMediaError(createChirpError, responseWriter)
+		return
+	}
+
+	if createChirpError != nil {
+		server.SendInternalServerError(createChirpError, responseWriter)
+		return
+	}
+
+	if commitError := tx.Commit(); commitError != nil {
+		server.SendInternalServerError(commitError, responseWriter)
+		return
+	}
+
+	config.recordDuplicates(prepared)
+
+	response, buildResponseError := config.buildChirpResponse(req.Context(), audience{viewerID: userUUID.String()}, chirp)
+
+	if buildResponseError != nil {
+		server.SendInternalServerError(buildResponseError, responseWriter)
+		return
+	}
+
+	server.ResponseWithJson(response, http.StatusCreated, responseWriter)
+}
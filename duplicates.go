@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/octaviocarpes/go-http-servers/internal/database"
+	"github.com/octaviocarpes/go-http-servers/internal/duplicates"
+	server "github.com/octaviocarpes/go-http-servers/server"
+)
+
+const (
+	// Candidates are capped so a burst of chirps cannot make every new one
+	// compare against an unbounded amount of history.
+	authorFingerprintLimit = 200
+	recentFingerprintLimit = 1000
+)
+
+// checkDuplicates compares fingerprint against the author's recent chirps
+// and everyone else's, and returns the kinds it matched and what the policy
+// says to do about them. It is up to the caller to record the outcome once
+// it is final.
+func (config *apiConfig) checkDuplicates(ctx context.Context, userID string, fingerprint duplicates.Fingerprint) ([]duplicates.Kind, duplicates.Action, error) {
+	if fingerprint.Tokens < config.duplicateDetector.MinTokens {
+		return []duplicates.Kind{}, duplicates.ActionAllow, nil
+	}
+
+	now := time.Now()
+
+	authorRows, authorError := config.db.ListAuthorFingerprints(ctx, database.ListAuthorFingerprintsParams{
+		UserID:   userID,
+		Since:    now.Add(-config.duplicateAuthorWindow),
+		PageSize: authorFingerprintLimit,
+	})
+
+	if authorError != nil {
+		return nil, "", authorError
+	}
+
+	recentRows, recentError := config.db.ListRecentFingerprints(ctx, database.ListRecentFingerprintsParams{
+		UserID:   userID,
+		Since:    now.Add(-config.duplicateGlobalWindow),
+		PageSize: recentFingerprintLimit,
+	})
+
+	if recentError != nil {
+		return nil, "", recentError
+	}
+
+	candidates := make([]duplicates.Candidate, 0, len(authorRows)+len(recentRows))
+
+	for _, row := range authorRows {
+		candidates = append(candidates, duplicates.Candidate{
+			UserID:  row.UserID,
+			Hash:    row.Hash,
+			Simhash: uint64(row.Simhash),
+			Removed: row.Removed,
+		})
+	}
+
+	for _, row := range recentRows {
+		candidates = append(candidates, duplicates.Candidate{
+			UserID:  row.UserID,
+			Hash:    row.Hash,
+			Simhash: uint64(row.Simhash),
+			Removed: row.Removed,
+		})
+	}
+
+	kinds := config.duplicateDetector.Check(userID, fingerprint, candidates)
+	action := config.duplicatePolicy.Decide(kinds)
+
+	return kinds, action, nil
+}
+
+// recordDuplicates counts a prepared chirp in the duplicate metrics. Called
+// once the chirp has been stored, so chirps that failed later on are left
+// out.
+func (config *apiConfig) recordDuplicates(prepared preparedChirp) {
+	config.duplicateMetrics.Record(prepared.duplicateKinds, prepared.duplicateAction)
+}
+
+// duplicateMetricsHandler reports how often chirps matched each kind of duplicate
+// on this instance since it started. The counts live in memory, so each
+// instance only knows about the chirps it handled and starts over when it
+// restarts.
+func (config *apiConfig) duplicateMetricsHandler(responseWriter http.ResponseWriter, req *http.Request) {
+	if _, ok := config.authenticateAdmin(responseWriter, req); !ok {
+		return
+	}
+
+	server.ResponseWithJson(config.duplicateMetrics.Snapshot(), http.StatusOK, responseWriter)
+}
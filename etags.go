@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/octaviocarpes/go-http-servers/internal/database"
+	server "github.com/octaviocarpes/go-http-servers/server"
+)
+
+// chirpRepresentation is what GET /api/chirps/{id} returns for chirp to
+// viewerID: a tombstone once it is deleted, the chirp otherwise.
+func (config *apiConfig) chirpRepresentation(ctx context.Context, viewerID string, chirp database.Chirp) (any, error) {
+	if chirp.DeletedAt.Valid {
+		return config.newTombstoneResponse(chirp), nil
+	}
+
+	viewer, audienceError := config.audienceFor(ctx, viewerID)
+
+	if audienceError != nil {
+		return nil, audienceError
+	}
+
+	return config.buildChirpResponse(ctx, viewer, chirp)
+}
+
+// checkChirpIfMatch answers 412 Precondition Failed and returns false when
+// req has an If-Match header that does not match the ETag viewerID would get
+// for chirp from GET /api/chirps/{id}. Callers lock the chirp first, so it
+// cannot change between the check and their write.
+func (config *apiConfig) checkChirpIfMatch(responseWriter http.ResponseWriter, req *http.Request, viewerID string, chirp database.Chirp) bool {
+	if len(req.Header.Get("If-Match")) == 0 {
+		return true
+	}
+
+	representation, buildError := config.chirpRepresentation(req.Context(), viewerID, chirp)
+
+	if buildError != nil {
+		server.SendInternalServerError(buildError, responseWriter)
+		return false
+	}
+
+	etag, etagError := server.JsonETag(representation)
+
+	if etagError != nil {
+		server.SendInternalServerError(etagError, responseWriter)
+		return false
+	}
+
+	if !server.IfMatch(req, etag) {
+		responseWriter.Header().Set("ETag", etag)
+		server.SendError("chirp has changed since it was fetched", http.StatusPreconditionFailed, responseWriter)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,563 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"html"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/octaviocarpes/go-http-servers/internal/activitypub"
+	"github.com/octaviocarpes/go-http-servers/internal/database"
+	server "github.com/octaviocarpes/go-http-servers/server"
+)
+
+const (
+	activityCreate = "Create"
+	activityDelete = "Delete"
+	activityAccept = "Accept"
+
+	outboxSize    = 20
+	maxInboxBytes = 1 << 20
+
+	// A claimed delivery is left alone by other workers for this long,
+	// which must cover sending it.
+	activityDeliveryLease = 5 * time.Minute
+	activityDeliveryBatch = 20
+)
+
+func (config *apiConfig) actorURL(userID string) string {
+	return config.publicURL + "/ap/users/" + userID
+}
+
+func (config *apiConfig) noteURL(chirpID string) string {
+	return config.publicURL + "/ap/chirps/" + chirpID
+}
+
+// federates reports whether chirp is shown to the fediverse. Rechirps are
+// not; they would need Announce activities.
+func federates(chirp database.Chirp) bool {
+	return !chirp.PublishAt.Valid && !chirp.DeletedAt.Valid && !chirp.HiddenAt.Valid && !chirp.RechirpOf.Valid
+}
+
+// federateChirp queues activity about a chirp for every server following its
+// author. Called inside the transaction that changes the chirp, so nothing
+// is sent about a change that was rolled back.
+func federateChirp(ctx context.Context, queries *database.Queries, activity, userID, chirpID string) error {
+	return queries.EnqueueFollowerDeliveries(ctx, database.EnqueueFollowerDeliveriesParams{
+		UserID:   userID,
+		Activity: activity,
+		ObjectID: chirpID,
+	})
+}
+
+// actorKey is userID's signing key, made on first use.
+func (config *apiConfig) actorKey(ctx context.Context, userID string) (database.ActorKey, error) {
+	key, getKeyError := config.db.GetActorKey(ctx, userID)
+
+	if !errors.Is(getKeyError, sql.ErrNoRows) {
+		return key, getKeyError
+	}
+
+	privatePEM, publicPEM, generateError := activitypub.GenerateKey()
+
+	if generateError != nil {
+		return database.ActorKey{}, generateError
+	}
+
+	// Two requests may race to make the key; whichever lands first wins.
+	createError := config.db.CreateActorKey(ctx, database.CreateActorKeyParams{
+		UserID:        userID,
+		PublicKeyPem:  publicPEM,
+		PrivateKeyPem: privatePEM,
+	})
+
+	if createError != nil {
+		return database.ActorKey{}, createError
+	}
+
+	return config.db.GetActorKey(ctx, userID)
+}
+
+func sendActivityJson(payload any, contentType string, status int, responseWriter http.ResponseWriter) {
+	body, encodeError := json.Marshal(payload)
+
+	if encodeError != nil {
+		server.SendInternalServerError(encodeError, responseWriter)
+		return
+	}
+
+	responseWriter.Header().Set("Content-Type", contentType)
+	responseWriter.WriteHeader(status)
+	responseWriter.Write(body)
+}
+
+func (config *apiConfig) renderNote(chirp database.Chirp) activitypub.Note {
+	content := "<p>" + strings.ReplaceAll(html.EscapeString(chirp.Body), "\n", "<br>") + "</p>"
+
+	if chirp.QuoteOf.Valid {
+		quoted := config.noteURL(chirp.QuoteOf.String)
+		content += `<p>RE: <a href="` + quoted + `">` + quoted + `</a></p>`
+	}
+
+	note := activitypub.Note{
+		ID:           config.noteURL(chirp.ID),
+		Type:         "Note",
+		AttributedTo: config.actorURL(chirp.UserID),
+		Content:      content,
+		Published:    chirp.CreatedAt.UTC(),
+		URL:          config.chirpURL(chirp.ID),
+		To:           []string{activitypub.Public},
+		Cc:           []string{config.actorURL(chirp.UserID) + "/followers"},
+	}
+
+	if chirp.InReplyTo.Valid {
+		parent := config.noteURL(chirp.InReplyTo.String)
+		note.InReplyTo = &parent
+	}
+
+	return note
+}
+
+func (config *apiConfig) createActivity(chirp database.Chirp) activitypub.Activity {
+	note := config.renderNote(chirp)
+
+	return activitypub.Activity{
+		Context:   activitypub.ActivityStreamsContext,
+		ID:        note.ID + "/activity",
+		Type:      activityCreate,
+		Actor:     note.AttributedTo,
+		Object:    note,
+		Published: &note.Published,
+		To:        note.To,
+		Cc:        note.Cc,
+	}
+}
+
+func (config *apiConfig) deleteActivity(userID, chirpID string) activitypub.Activity {
+	return activitypub.Activity{
+		Context: activitypub.ActivityStreamsContext,
+		ID:      config.noteURL(chirpID) + "#delete",
+		Type:    activityDelete,
+		Actor:   config.actorURL(userID),
+		Object:  activitypub.Tombstone{ID: config.noteURL(chirpID), Type: "Tombstone"},
+		To:      []string{activitypub.Public},
+	}
+}
+
+func (config *apiConfig) acceptActivity(delivery database.ActivityDelivery) activitypub.Activity {
+	actorURL := config.actorURL(delivery.UserID)
+
+	return activitypub.Activity{
+		Context: activitypub.ActivityStreamsContext,
+		ID:      actorURL + "#accepts/" + delivery.ID,
+		Type:    activityAccept,
+		Actor:   actorURL,
+		Object: activitypub.Activity{
+			ID:     delivery.ObjectID,
+			Type:   "Follow",
+			Actor:  delivery.ObjectActor,
+			Object: actorURL,
+		},
+	}
+}
+
+// webFinger resolves acct:handle@host, host being this server's, to the
+// user's actor.
+func (config *apiConfig) webFinger(responseWriter http.ResponseWriter, req *http.Request) {
+	handle, host, ok := activitypub.ParseAccount(req.URL.Query().Get("resource"))
+
+	if !ok {
+		server.SendError("resource must be acct:handle@host", http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	publicURL, _ := url.Parse(config.publicURL)
+
+	if host != strings.ToLower(publicURL.Host) {
+		server.SendError("user not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	user, getUserError := config.db.GetUserByHandle(req.Context(), handle)
+
+	if getUserError != nil {
+		server.SendError("user not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	actorURL := config.actorURL(user.ID)
+
+	sendActivityJson(activitypub.JRD{
+		Subject: "acct:" + user.Handle.String + "@" + publicURL.Host,
+		Aliases: []string{actorURL},
+		Links:   []activitypub.Link{{Rel: "self", Type: activitypub.ContentType, Href: actorURL}},
+	}, activitypub.WebFingerContentType, http.StatusOK, responseWriter)
+}
+
+func (config *apiConfig) getActor(responseWriter http.ResponseWriter, req *http.Request) {
+	user, getUserError := config.db.GetUserByID(req.Context(), req.PathValue("id"))
+
+	if getUserError != nil {
+		server.SendError("user not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	key, keyError := config.actorKey(req.Context(), user.ID)
+
+	if keyError != nil {
+		server.SendInternalServerError(keyError, responseWriter)
+		return
+	}
+
+	actorURL := config.actorURL(user.ID)
+
+	actor := activitypub.Actor{
+		Context:           activitypub.Context,
+		ID:                actorURL,
+		Type:              "Person",
+		PreferredUsername: user.ID,
+		Name:              user.DisplayName,
+		Summary:           html.EscapeString(user.Bio),
+		URL:               config.publicURL + "/api/chirps?author_id=" + user.ID,
+		Inbox:             actorURL + "/inbox",
+		Outbox:            actorURL + "/outbox",
+		Followers:         actorURL + "/followers",
+		PublicKey: activitypub.PublicKey{
+			ID:           actorURL + "#main-key",
+			Owner:        actorURL,
+			PublicKeyPem: key.PublicKeyPem,
+		},
+	}
+
+	// Only users with a handle can be found through WebFinger, which
+	// checks that preferredUsername leads back to the same actor.
+	if user.Handle.Valid {
+		actor.PreferredUsername = user.Handle.String
+		actor.URL = config.publicURL + "/api/users/" + user.Handle.String
+	}
+
+	sendActivityJson(actor, activitypub.ContentType, http.StatusOK, responseWriter)
+}
+
+// getActorOutbox lists the user's latest chirps as Create activities.
+func (config *apiConfig) getActorOutbox(responseWriter http.ResponseWriter, req *http.Request) {
+	user, getUserError := config.db.GetUserByID(req.Context(), req.PathValue("id"))
+
+	if getUserError != nil {
+		server.SendError("user not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	chirps, listChirpsError := config.db.ListOutboxChirps(req.Context(), database.ListOutboxChirpsParams{
+		UserID: user.ID,
+		Limit:  outboxSize,
+	})
+
+	if listChirpsError != nil {
+		server.SendInternalServerError(listChirpsError, responseWriter)
+		return
+	}
+
+	count, countError := config.db.CountOutboxChirps(req.Context(), user.ID)
+
+	if countError != nil {
+		server.SendInternalServerError(countError, responseWriter)
+		return
+	}
+
+	outbox := activitypub.OrderedCollection{
+		Context:      activitypub.ActivityStreamsContext,
+		ID:           config.actorURL(user.ID) + "/outbox",
+		Type:         "OrderedCollection",
+		TotalItems:   int(count),
+		OrderedItems: []any{},
+	}
+
+	for _, chirp := range chirps {
+		outbox.OrderedItems = append(outbox.OrderedItems, config.createActivity(chirp))
+	}
+
+	sendActivityJson(outbox, activitypub.ContentType, http.StatusOK, responseWriter)
+}
+
+// getActorFollowers only counts the user's remote followers; who they are
+// is not published.
+func (config *apiConfig) getActorFollowers(responseWriter http.ResponseWriter, req *http.Request) {
+	user, getUserError := config.db.GetUserByID(req.Context(), req.PathValue("id"))
+
+	if getUserError != nil {
+		server.SendError("user not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	count, countError := config.db.CountRemoteFollowers(req.Context(), user.ID)
+
+	if countError != nil {
+		server.SendInternalServerError(countError, responseWriter)
+		return
+	}
+
+	sendActivityJson(activitypub.OrderedCollection{
+		Context:    activitypub.ActivityStreamsContext,
+		ID:         config.actorURL(user.ID) + "/followers",
+		Type:       "OrderedCollection",
+		TotalItems: int(count),
+	}, activitypub.ContentType, http.StatusOK, responseWriter)
+}
+
+// getNote serves a chirp as the Note its activities refer to. Chirps that
+// are gone answer 410 with a Tombstone, as remote servers expect.
+func (config *apiConfig) getNote(responseWriter http.ResponseWriter, req *http.Request) {
+	chirp, getChirpError := config.db.GetChirpByID(req.Context(), req.PathValue("id"))
+
+	if getChirpError != nil || chirp.PublishAt.Valid || chirp.RechirpOf.Valid {
+		server.SendError("chirp not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	if !federates(chirp) {
+		tombstone := activitypub.Tombstone{ID: config.noteURL(chirp.ID), Type: "Tombstone"}
+		sendActivityJson(tombstone, activitypub.ContentType, http.StatusGone, responseWriter)
+		return
+	}
+
+	note := config.renderNote(chirp)
+	note.Context = activitypub.ActivityStreamsContext
+
+	sendActivityJson(note, activitypub.ContentType, http.StatusOK, responseWriter)
+}
+
+// postInbox takes activities from other servers. Only Follow and Undo of a
+// Follow mean anything to Chirpy; everything else is acknowledged and
+// dropped.
+func (config *apiConfig) postInbox(responseWriter http.ResponseWriter, req *http.Request) {
+	user, getUserError := config.db.GetUserByID(req.Context(), req.PathValue("id"))
+
+	if getUserError != nil {
+		server.SendError("user not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	body, readError := io.ReadAll(http.MaxBytesReader(responseWriter, req.Body, maxInboxBytes))
+
+	if readError != nil {
+		server.SendError("request body is too large", http.StatusRequestEntityTooLarge, responseWriter)
+		return
+	}
+
+	signature, signatureError := activitypub.ParseSignature(req)
+
+	if signatureError != nil {
+		server.SendError("the request must carry an HTTP signature", http.StatusUnauthorized, responseWriter)
+		return
+	}
+
+	signer, parseError := url.Parse(signature.KeyID)
+
+	if parseError != nil || len(signer.Host) == 0 {
+		server.SendError("the signature key ID must be a URL", http.StatusUnauthorized, responseWriter)
+		return
+	}
+
+	if !config.limitInbox(responseWriter, req, strings.ToLower(signer.Host)) {
+		return
+	}
+
+	activity, actor, receiveError := config.federation.ReceiveActivity(req, body)
+
+	if receiveError != nil {
+		server.SendError("could not verify the request signature", http.StatusUnauthorized, responseWriter)
+		return
+	}
+
+	switch activity.Type {
+	case "Follow":
+		if activitypub.ObjectID(activity.Object) != config.actorURL(user.ID) {
+			server.SendError("an inbox only takes follows of its owner", http.StatusBadRequest, responseWriter)
+			return
+		}
+
+		if followError := config.acceptFollow(req.Context(), user.ID, activity, actor); followError != nil {
+			server.SendInternalServerError(followError, responseWriter)
+			return
+		}
+	case "Undo":
+		if activitypub.ObjectType(activity.Object) != "Follow" {
+			break
+		}
+
+		_, removeError := config.db.RemoveRemoteFollower(req.Context(), database.RemoveRemoteFollowerParams{
+			UserID:   user.ID,
+			ActorUrl: actor.ID,
+			FollowID: activitypub.ObjectID(activity.Object),
+		})
+
+		if removeError != nil {
+			server.SendInternalServerError(removeError, responseWriter)
+			return
+		}
+	}
+
+	responseWriter.WriteHeader(http.StatusAccepted)
+}
+
+// acceptFollow records actor as following userID and queues the Accept,
+// together.
+func (config *apiConfig) acceptFollow(ctx context.Context, userID string, follow activitypub.Activity, actor activitypub.Actor) error {
+	tx, beginError := config.conn.BeginTx(ctx, nil)
+
+	if beginError != nil {
+		return beginError
+	}
+
+	defer tx.Rollback()
+
+	queries := config.db.WithTx(tx)
+
+	addError := queries.AddRemoteFollower(ctx, database.AddRemoteFollowerParams{
+		UserID:         userID,
+		ActorUrl:       actor.ID,
+		InboxUrl:       actor.Inbox,
+		SharedInboxUrl: actor.SharedInbox(),
+		FollowID:       follow.ID,
+	})
+
+	if addError != nil {
+		return addError
+	}
+
+	enqueueError := queries.EnqueueActivityDelivery(ctx, database.EnqueueActivityDeliveryParams{
+		UserID:      userID,
+		InboxUrl:    actor.Inbox,
+		Activity:    activityAccept,
+		ObjectID:    follow.ID,
+		ObjectActor: actor.ID,
+	})
+
+	if enqueueError != nil {
+		return enqueueError
+	}
+
+	return tx.Commit()
+}
+
+// deliverActivities works through the delivery queue. Any number of
+// instances can run it; each claims its own batch.
+func (config *apiConfig) deliverActivities(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			deliveries, claimError := config.db.ClaimActivityDeliveries(ctx, database.ClaimActivityDeliveriesParams{
+				LeaseSeconds: activityDeliveryLease.Seconds(),
+				BatchSize:    activityDeliveryBatch,
+			})
+
+			if claimError != nil {
+				log.Printf("failed to claim activity deliveries: %v\n", claimError)
+				break
+			}
+
+			for _, delivery := range deliveries {
+				config.deliverActivity(ctx, delivery)
+			}
+
+			if len(deliveries) < activityDeliveryBatch {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliverActivity makes one attempt at delivery and either removes it from
+// the queue or schedules the next attempt, backing off each time. Deliveries
+// the remote server refuses outright, or that keep failing, are given up.
+func (config *apiConfig) deliverActivity(ctx context.Context, delivery database.ActivityDelivery) {
+	sendError := config.sendActivity(ctx, delivery)
+	attempts := int(delivery.Attempts) + 1
+
+	var rejected *activitypub.DeliveryError
+	permanent := errors.Is(sendError, activitypub.ErrInsecureURL) || (errors.As(sendError, &rejected) && rejected.Permanent())
+
+	if sendError != nil && !permanent && attempts < config.activityMaxAttempts {
+		retryError := config.db.RetryActivityDelivery(ctx, database.RetryActivityDeliveryParams{
+			Attempts:     int32(attempts),
+			DelaySeconds: activitypub.Backoff(attempts).Seconds(),
+			LastError:    sendError.Error(),
+			ID:           delivery.ID,
+		})
+
+		if retryError != nil {
+			log.Printf("failed to reschedule activity delivery: %v\n", retryError)
+		}
+
+		return
+	}
+
+	if sendError != nil {
+		log.Printf("giving up on delivering %s to %s after %d attempts: %v\n", delivery.Activity, delivery.InboxUrl, attempts, sendError)
+	}
+
+	if completeError := config.db.CompleteActivityDelivery(ctx, delivery.ID); completeError != nil {
+		log.Printf("failed to remove activity delivery: %v\n", completeError)
+	}
+}
+
+// sendActivity renders delivery and posts it. A Create for a chirp that is
+// no longer shown has nothing to send, since a Delete is on its way.
+func (config *apiConfig) sendActivity(ctx context.Context, delivery database.ActivityDelivery) error {
+	var activity activitypub.Activity
+
+	switch delivery.Activity {
+	case activityCreate:
+		chirp, getChirpError := config.db.GetChirpByID(ctx, delivery.ObjectID)
+
+		if errors.Is(getChirpError, sql.ErrNoRows) || (getChirpError == nil && !federates(chirp)) {
+			return nil
+		}
+
+		if getChirpError != nil {
+			return getChirpError
+		}
+
+		activity = config.createActivity(chirp)
+	case activityDelete:
+		activity = config.deleteActivity(delivery.UserID, delivery.ObjectID)
+	case activityAccept:
+		activity = config.acceptActivity(delivery)
+	}
+
+	body, encodeError := json.Marshal(activity)
+
+	if encodeError != nil {
+		return encodeError
+	}
+
+	key, keyError := config.actorKey(ctx, delivery.UserID)
+
+	if keyError != nil {
+		return keyError
+	}
+
+	privateKey, parseError := activitypub.ParsePrivateKey(key.PrivateKeyPem)
+
+	if parseError != nil {
+		return parseError
+	}
+
+	return config.federation.Deliver(ctx, delivery.InboxUrl, body, config.actorURL(delivery.UserID)+"#main-key", privateKey)
+}
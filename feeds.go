@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/octaviocarpes/go-http-servers/internal/database"
+	"github.com/octaviocarpes/go-http-servers/internal/feeds"
+	server "github.com/octaviocarpes/go-http-servers/server"
+)
+
+const (
+	feedSize           = 50
+	feedItemTitleRunes = 60
+)
+
+type feedFormat struct {
+	render      func(feeds.Feed) ([]byte, error)
+	contentType string
+}
+
+var feedFormats = map[string]feedFormat{
+	"rss":  {render: feeds.RSS, contentType: feeds.RSSContentType},
+	"atom": {render: feeds.Atom, contentType: feeds.AtomContentType},
+}
+
+// parseFeedName splits a feed path segment such as "<id>.rss" into its name
+// and format.
+func parseFeedName(segment string) (string, feedFormat, bool) {
+	dot := strings.LastIndexByte(segment, '.')
+
+	if dot < 0 {
+		return "", feedFormat{}, false
+	}
+
+	format, ok := feedFormats[segment[dot+1:]]
+	return segment[:dot], format, ok
+}
+
+func feedAuthorName(user database.User) string {
+	if user.Handle.Valid {
+		return "@" + user.Handle.String
+	}
+
+	if len(user.DisplayName) > 0 {
+		return user.DisplayName
+	}
+
+	return user.ID
+}
+
+// feedItemTitle is the start of a chirp's first line. Atom requires a title
+// and most readers show nothing useful without one.
+func feedItemTitle(body, fallback string) string {
+	title, _, _ := strings.Cut(strings.TrimSpace(body), "\n")
+
+	if len(title) == 0 {
+		return fallback
+	}
+
+	if utf8.RuneCountInString(title) > feedItemTitleRunes {
+		title = string([]rune(title)[:feedItemTitleRunes-1]) + "…"
+	}
+
+	return title
+}
+
+func (config *apiConfig) chirpURL(chirpID string) string {
+	return config.publicURL + "/api/chirps/" + chirpID
+}
+
+// buildFeed turns the newest chirps into a feed. It shows what GET
+// /api/chirps shows an anonymous viewer, one page of it.
+func (config *apiConfig) buildFeed(ctx context.Context, feed feeds.Feed, authorID string) (feeds.Feed, error) {
+	chirps, listChirpsError := config.db.ListLatestChirps(ctx, database.ListLatestChirpsParams{
+		AuthorID: sql.NullString{String: authorID, Valid: len(authorID) > 0},
+		Limit:    feedSize,
+	})
+
+	if listChirpsError != nil {
+		return feeds.Feed{}, listChirpsError
+	}
+
+	responses, buildResponseError := config.buildChirpResponses(ctx, audience{}, chirps)
+
+	if buildResponseError != nil {
+		return feeds.Feed{}, buildResponseError
+	}
+
+	authorIDs := make([]string, len(chirps))
+
+	for i, chirp := range chirps {
+		authorIDs[i] = chirp.UserID
+	}
+
+	users, listUsersError := config.db.ListUsersByIDs(ctx, authorIDs)
+
+	if listUsersError != nil {
+		return feeds.Feed{}, listUsersError
+	}
+
+	authorNames := map[string]string{}
+
+	for _, user := range users {
+		authorNames[user.ID] = feedAuthorName(user)
+	}
+
+	feed.Items = make([]feeds.Item, len(responses))
+
+	for i, response := range responses {
+		author := authorNames[response.UserID]
+		title := feedItemTitle(response.Body, "Chirp by "+author)
+		content := response.Body
+
+		if response.RechirpOf != nil {
+			title = "Rechirp: " + feedItemTitle(response.RechirpOf.Body, "a chirp")
+			content = response.RechirpOf.Body
+		}
+
+		if response.QuoteOf != nil {
+			content += "\n\nQuoting " + config.chirpURL(response.QuoteOf.ID)
+		}
+
+		feed.Items[i] = feeds.Item{
+			ID:        "urn:uuid:" + response.ID,
+			Title:     title,
+			Link:      config.chirpURL(response.ID),
+			Author:    author,
+			Content:   content,
+			Published: response.CreatedAt,
+			Updated:   response.UpdatedAt,
+		}
+
+		if response.UpdatedAt.After(feed.Updated) {
+			feed.Updated = response.UpdatedAt
+		}
+	}
+
+	return feed, nil
+}
+
+func (config *apiConfig) sendFeed(feed feeds.Feed, format feedFormat, req *http.Request, responseWriter http.ResponseWriter) {
+	body, renderError := format.render(feed)
+
+	if renderError != nil {
+		server.SendInternalServerError(renderError, responseWriter)
+		return
+	}
+
+	// feed.Updated misses chirps deleted since, which change the feed too,
+	// so it is validated by ETag alone.
+	server.ResponseWithConditional(body, format.contentType, time.Time{}, req, responseWriter)
+}
+
+// userFeed serves /feeds/users/{id}.rss and .atom.
+func (config *apiConfig) userFeed(responseWriter http.ResponseWriter, req *http.Request) {
+	userID, format, ok := parseFeedName(req.PathValue("feed"))
+
+	if !ok {
+		server.SendError("feed not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	user, getUserError := config.db.GetUserByID(req.Context(), userID)
+
+	if getUserError != nil {
+		server.SendError("user not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	author := feedAuthorName(user)
+
+	feed, buildFeedError := config.buildFeed(req.Context(), feeds.Feed{
+		ID:          "urn:uuid:" + user.ID,
+		Title:       "Chirps by " + author,
+		Description: "The latest chirps by " + author + " on Chirpy",
+		Link:        config.publicURL + "/api/chirps?author_id=" + user.ID,
+		Self:        config.publicURL + req.URL.Path,
+		Updated:     user.CreatedAt,
+	}, user.ID)
+
+	if buildFeedError != nil {
+		server.SendInternalServerError(buildFeedError, responseWriter)
+		return
+	}
+
+	config.sendFeed(feed, format, req, responseWriter)
+}
+
+// globalFeed serves /feeds/chirps.rss and .atom, the newest chirps from
+// everyone.
+func (config *apiConfig) globalFeed(responseWriter http.ResponseWriter, req *http.Request) {
+	name, format, ok := parseFeedName(req.PathValue("feed"))
+
+	if !ok || name != "chirps" {
+		server.SendError("feed not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	feed, buildFeedError := config.buildFeed(req.Context(), feeds.Feed{
+		ID:          config.publicURL + "/feeds/chirps",
+		Title:       "Chirpy",
+		Description: "The latest chirps on Chirpy",
+		Link:        config.publicURL + "/api/chirps",
+		Self:        config.publicURL + req.URL.Path,
+	}, "")
+
+	if buildFeedError != nil {
+		server.SendInternalServerError(buildFeedError, responseWriter)
+		return
+	}
+
+	config.sendFeed(feed, format, req, responseWriter)
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/octaviocarpes/go-http-servers/internal/database"
+	server "github.com/octaviocarpes/go-http-servers/server"
+)
+
+// timelineBackfillSize is how many of an account's recent chirps are copied
+// into a follower's timeline when they start following it.
+const timelineBackfillSize = 100
+
+// lookupUser resolves the {user} path segment, which may be a handle or a
+// user id.
+func (config *apiConfig) lookupUser(ctx context.Context, reference string) (database.User, error) {
+	if _, parseError := uuid.Parse(reference); parseError == nil {
+		return config.db.GetUserByID(ctx, reference)
+	}
+
+	return config.db.GetUserByHandle(ctx, reference)
+}
+
+func (config *apiConfig) followUser(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	followee, getUserError := config.lookupUser(req.Context(), req.PathValue("user"))
+
+	if getUserError != nil {
+		server.SendError("user not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	if followee.ID == userUUID.String() {
+		server.SendError("you cannot follow yourself", http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	if blockedError := checkNotBlocked(req.Context(), config.db, userUUID.String(), followee.ID); blockedError != nil {
+		sendBlockedError(blockedError, responseWriter)
+		return
+	}
+
+	tx, beginError := config.conn.BeginTx(req.Context(), nil)
+
+	if beginError != nil {
+		server.SendInternalServerError(beginError, responseWriter)
+		return
+	}
+
+	defer tx.Rollback()
+
+	queries := config.db.WithTx(tx)
+
+	followed, followError := queries.FollowUser(req.Context(), database.FollowUserParams{
+		FollowerID: userUUID.String(),
+		FolloweeID: followee.ID,
+	})
+
+	if followError != nil {
+		server.SendInternalServerError(followError, responseWriter)
+		return
+	}
+
+	// Following twice is a no-op, so only a new follow backfills the timeline
+	// and notifies the followee.
+	if followed > 0 {
+		backfillError := queries.BackfillTimeline(req.Context(), database.BackfillTimelineParams{
+			UserID:   userUUID.String(),
+			AuthorID: followee.ID,
+			PageSize: timelineBackfillSize,
+		})
+
+		if backfillError != nil {
+			server.SendInternalServerError(backfillError, responseWriter)
+			return
+		}
+
+		if notifyError := notify(req.Context(), queries, notificationFollow, followee.ID, userUUID.String(), ""); notifyError != nil {
+			server.SendInternalServerError(notifyError, responseWriter)
+			return
+		}
+	}
+
+	if commitError := tx.Commit(); commitError != nil {
+		server.SendInternalServerError(commitError, responseWriter)
+		return
+	}
+
+	responseWriter.WriteHeader(http.StatusNoContent)
+}
+
+func (config *apiConfig) unfollowUser(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	followee, getUserError := config.lookupUser(req.Context(), req.PathValue("user"))
+
+	if getUserError != nil {
+		server.SendError("user not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	tx, beginError := config.conn.BeginTx(req.Context(), nil)
+
+	if beginError != nil {
+		server.SendInternalServerError(beginError, responseWriter)
+		return
+	}
+
+	defer tx.Rollback()
+
+	queries := config.db.WithTx(tx)
+
+	_, unfollowError := queries.UnfollowUser(req.Context(), database.UnfollowUserParams{
+		FollowerID: userUUID.String(),
+		FolloweeID: followee.ID,
+	})
+
+	if unfollowError != nil {
+		server.SendInternalServerError(unfollowError, responseWriter)
+		return
+	}
+
+	pruneError := queries.PruneTimeline(req.Context(), database.PruneTimelineParams{
+		UserID:   userUUID.String(),
+		AuthorID: followee.ID,
+	})
+
+	if pruneError != nil {
+		server.SendInternalServerError(pruneError, responseWriter)
+		return
+	}
+
+	if commitError := tx.Commit(); commitError != nil {
+		server.SendInternalServerError(commitError, responseWriter)
+		return
+	}
+
+	responseWriter.WriteHeader(http.StatusNoContent)
+}
+
+type followResponse struct {
+	ID          string    `json:"id"`
+	Handle      *string   `json:"handle"`
+	DisplayName string    `json:"display_name"`
+	FollowedAt  time.Time `json:"followed_at"`
+}
+
+type followsResponse struct {
+	Users      []followResponse `json:"users"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+func (config *apiConfig) listFollowers(responseWriter http.ResponseWriter, req *http.Request) {
+	config.listFollows(responseWriter, req, func(ctx context.Context, params database.ListFollowersParams) ([]database.ListFollowersRow, error) {
+		return config.db.ListFollowers(ctx, params)
+	})
+}
+
+func (config *apiConfig) listFollowing(responseWriter http.ResponseWriter, req *http.Request) {
+	config.listFollows(responseWriter, req, func(ctx context.Context, params database.ListFollowersParams) ([]database.ListFollowersRow, error) {
+		rows, listError := config.db.ListFollowing(ctx, database.ListFollowingParams(params))
+
+		if listError != nil {
+			return nil, listError
+		}
+
+		followers := make([]database.ListFollowersRow, len(rows))
+
+		for i, row := range rows {
+			followers[i] = database.ListFollowersRow(row)
+		}
+
+		return followers, nil
+	})
+}
+
+// listFollows serves both directions of the follow graph; list decides which
+// side of the edge is returned.
+func (config *apiConfig) listFollows(
+	responseWriter http.ResponseWriter,
+	req *http.Request,
+	list func(context.Context, database.ListFollowersParams) ([]database.ListFollowersRow, error),
+) {
+	query := req.URL.Query()
+
+	limit, limitError := server.ParseLimit(query, 50, 200)
+
+	if limitError != nil {
+		server.SendError(limitError.Error(), http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	beforeCreatedAt, beforeUserID, cursorError := server.ParseTimeCursor(query)
+
+	if cursorError != nil {
+		server.SendError(cursorError.Error(), http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	user, getUserError := config.lookupUser(req.Context(), req.PathValue("user"))
+
+	if getUserError != nil {
+		server.SendError("user not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	rows, listError := list(req.Context(), database.ListFollowersParams{
+		UserID:          user.ID,
+		BeforeCreatedAt: beforeCreatedAt,
+		BeforeUserID:    beforeUserID,
+		PageSize:        limit + 1,
+	})
+
+	if listError != nil {
+		server.SendInternalServerError(listError, responseWriter)
+		return
+	}
+
+	nextCursor := ""
+
+	if len(rows) > int(limit) {
+		rows = rows[:limit]
+		last := rows[len(rows)-1]
+		nextCursor = server.EncodeTimeCursor(last.FollowedAt, last.ID)
+	}
+
+	response := followsResponse{
+		Users:      make([]followResponse, len(rows)),
+		NextCursor: nextCursor,
+	}
+
+	for i, row := range rows {
+		response.Users[i] = followResponse{
+			ID:          row.ID,
+			DisplayName: row.DisplayName,
+			FollowedAt:  row.FollowedAt,
+		}
+
+		if row.Handle.Valid {
+			response.Users[i].Handle = &row.Handle.String
+		}
+	}
+
+	server.ResponseWithJson(response, http.StatusOK, responseWriter)
+}
+
+func (config *apiConfig) getTimeline(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	query := req.URL.Query()
+
+	limit, limitError := server.ParseLimit(query, 50, 200)
+
+	if limitError != nil {
+		server.SendError(limitError.Error(), http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	beforeCreatedAt, beforeChirpID, cursorError := server.ParseTimeCursor(query)
+
+	if cursorError != nil {
+		server.SendError(cursorError.Error(), http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	viewer, audienceError := config.audienceFor(req.Context(), userUUID.String())
+
+	if audienceError != nil {
+		server.SendInternalServerError(audienceError, responseWriter)
+		return
+	}
+
+	rows, listTimelineError := config.db.ListTimeline(req.Context(), database.ListTimelineParams{
+		UserID:          userUUID.String(),
+		HiddenAuthorIds: viewer.feedHidden(),
+		BeforeCreatedAt: beforeCreatedAt,
+		BeforeChirpID:   beforeChirpID,
+		PageSize:        limit + 1,
+	})
+
+	if listTimelineError != nil {
+		server.SendInternalServerError(listTimelineError, responseWriter)
+		return
+	}
+
+	nextCursor := ""
+
+	if len(rows) > int(limit) {
+		rows = rows[:limit]
+		last := rows[len(rows)-1].Chirp
+		nextCursor = server.EncodeTimeCursor(last.CreatedAt, last.ID)
+	}
+
+	chirps := make([]database.Chirp, len(rows))
+
+	for i, row := range rows {
+		chirps[i] = row.Chirp
+	}
+
+	chirpResponses, buildResponseError := config.buildChirpResponses(req.Context(), viewer, chirps)
+
+	if buildResponseError != nil {
+		server.SendInternalServerError(buildResponseError, responseWriter)
+		return
+	}
+
+	type timelineResponse struct {
+		Chirps     []chirpResponse `json:"chirps"`
+		NextCursor string          `json:"next_cursor,omitempty"`
+	}
+
+	server.ResponseWithJson(timelineResponse{
+		Chirps:     chirpResponses,
+		NextCursor: nextCursor,
+	}, http.StatusOK, responseWriter)
+}
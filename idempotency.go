@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	auth "github.com/octaviocarpes/go-http-servers/internal/auth"
+	"github.com/octaviocarpes/go-http-servers/internal/database"
+	server "github.com/octaviocarpes/go-http-servers/server"
+)
+
+const (
+	maxIdempotencyKeyLength = 255
+	maxIdempotentBodyBytes  = 1 << 20
+
+	// A key whose first request has been running this long is assumed to
+	// belong to an instance that died, and may be claimed again.
+	idempotencyStaleAfter = time.Minute
+)
+
+// replayedHeaders are the response headers stored with an idempotent
+// response and sent again when it is replayed.
+var replayedHeaders = []string{
+	"Content-Type",
+	"ETag",
+	"Location",
+	"RateLimit-Policy",
+	"RateLimit-Limit",
+	"RateLimit-Remaining",
+	"RateLimit-Reset",
+	"Retry-After",
+}
+
+// responseRecorder passes a response through to the client and keeps a copy
+// of it.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (recorder *responseRecorder) WriteHeader(status int) {
+	if recorder.status == 0 {
+		recorder.status = status
+	}
+
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+func (recorder *responseRecorder) Write(data []byte) (int, error) {
+	if recorder.status == 0 {
+		recorder.status = http.StatusOK
+	}
+
+	recorder.body.Write(data)
+	return recorder.ResponseWriter.Write(data)
+}
+
+// idempotencyScope names whose keys a request's Idempotency-Key belongs to.
+// It returns false when the request is not authenticated, in which case the
+// handler runs as usual and turns the request away itself.
+type idempotencyScope func(req *http.Request) (string, bool)
+
+func (config *apiConfig) userIdempotencyScope(req *http.Request) (string, bool) {
+	token, getTokenErr := auth.GetBearerToken(req.Header)
+
+	if getTokenErr != nil {
+		return "", false
+	}
+
+	userUUID, invalidTokenError := auth.ValidateJWT(token, config.secret)
+
+	if invalidTokenError != nil {
+		return "", false
+	}
+
+	return "user:" + userUUID.String(), true
+}
+
+func (config *apiConfig) polkaIdempotencyScope(req *http.Request) (string, bool) {
+	apiKey, getTokenErr := auth.GetApiKey(req.Header)
+	return "polka", getTokenErr == nil && apiKey == config.polkaKey
+}
+
+// signupIdempotencyScope covers POST /api/users, where nobody is signed in
+// yet. Keys are random, so sharing one scope between clients is safe; a
+// client reusing another's key for a different signup is rejected like any
+// other reused key.
+func signupIdempotencyScope(_ *http.Request) (string, bool) {
+	return "signup", true
+}
+
+// idempotent lets clients retry next safely by sending an Idempotency-Key
+// header. The first response for a key is stored and replayed to retries;
+// reusing a key for a different request is rejected. Server errors and rate
+// limit rejections are not stored, so retrying those runs next again.
+func (config *apiConfig) idempotent(scopeOf idempotencyScope, next http.HandlerFunc) http.HandlerFunc {
+	return func(responseWriter http.ResponseWriter, req *http.Request) {
+		key := req.Header.Get("Idempotency-Key")
+
+		if len(key) == 0 {
+			next(responseWriter, req)
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			server.SendError("Idempotency-Key must be at most 255 characters", http.StatusBadRequest, responseWriter)
+			return
+		}
+
+		scope, ok := scopeOf(req)
+
+		if !ok {
+			next(responseWriter, req)
+			return
+		}
+
+		body, readError := io.ReadAll(http.MaxBytesReader(responseWriter, req.Body, maxIdempotentBodyBytes))
+
+		if readError != nil {
+			server.SendError("request body is too large", http.StatusRequestEntityTooLarge, responseWriter)
+			return
+		}
+
+		req.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.New()
+		io.WriteString(hash, req.Method+" "+req.URL.Path+"\n")
+		hash.Write(body)
+		requestHash := hex.EncodeToString(hash.Sum(nil))
+
+		now := time.Now()
+
+		claimed, claimError := config.db.ClaimIdempotencyKey(req.Context(), database.ClaimIdempotencyKeyParams{
+			Scope:       scope,
+			Key:         key,
+			RequestHash: requestHash,
+			ExpiresAt:   now.Add(config.idempotencyKeyTTL),
+			StaleBefore: now.Add(-idempotencyStaleAfter),
+		})
+
+		if claimError != nil {
+			server.SendInternalServerError(claimError, responseWriter)
+			return
+		}
+
+		if claimed == 0 {
+			config.replayIdempotentResponse(responseWriter, req, scope, key, requestHash)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: responseWriter}
+		next(recorder, req)
+
+		if recorder.status == 0 {
+			recorder.status = http.StatusOK
+		}
+
+		// The request is over either way; storing the result must not be
+		// cut short by a client that already hung up. Should this request
+		// have run long enough for a retry to claim the key again, the
+		// request hash and status keep it from touching the retry's result.
+		ctx := context.WithoutCancel(req.Context())
+
+		if recorder.status >= http.StatusInternalServerError || recorder.status == http.StatusTooManyRequests {
+			if releaseError := config.db.ReleaseIdempotencyKey(ctx, database.ReleaseIdempotencyKeyParams{Scope: scope, Key: key, RequestHash: requestHash}); releaseError != nil {
+				log.Printf("failed to release idempotency key: %v\n", releaseError)
+			}
+
+			return
+		}
+
+		headers := http.Header{}
+
+		for _, name := range replayedHeaders {
+			if values := responseWriter.Header().Values(name); len(values) > 0 {
+				headers[name] = values
+			}
+		}
+
+		encodedHeaders, encodeError := json.Marshal(headers)
+
+		if encodeError != nil {
+			log.Printf("failed to store idempotent response: %v\n", encodeError)
+			return
+		}
+
+		completeError := config.db.CompleteIdempotencyKey(ctx, database.CompleteIdempotencyKeyParams{
+			StatusCode:      sql.NullInt32{Int32: int32(recorder.status), Valid: true},
+			ResponseHeaders: encodedHeaders,
+			ResponseBody:    recorder.body.Bytes(),
+			Scope:           scope,
+			Key:             key,
+			RequestHash:     requestHash,
+		})
+
+		if completeError != nil {
+			log.Printf("failed to store idempotent response: %v\n", completeError)
+		}
+	}
+}
+
+func (config *apiConfig) replayIdempotentResponse(responseWriter http.ResponseWriter, req *http.Request, scope, key, requestHash string) {
+	stored, getError := config.db.GetIdempotencyKey(req.Context(), database.GetIdempotencyKeyParams{Scope: scope, Key: key})
+
+	// The first request failed and released the key after our claim lost.
+	if errors.Is(getError, sql.ErrNoRows) {
+		server.SendError("a request with this Idempotency-Key failed, retry it", http.StatusConflict, responseWriter)
+		return
+	}
+
+	if getError != nil {
+		server.SendInternalServerError(getError, responseWriter)
+		return
+	}
+
+	if stored.RequestHash != requestHash {
+		server.SendError("Idempotency-Key was already used for a different request", http.StatusUnprocessableEntity, responseWriter)
+		return
+	}
+
+	if !stored.StatusCode.Valid {
+		server.SendError("a request with this Idempotency-Key is still being processed", http.StatusConflict, responseWriter)
+		return
+	}
+
+	var headers http.Header
+
+	if decodeError := json.Unmarshal(stored.ResponseHeaders, &headers); decodeError != nil {
+		server.SendInternalServerError(decodeError, responseWriter)
+		return
+	}
+
+	for name, values := range headers {
+		responseWriter.Header()[name] = values
+	}
+
+	responseWriter.Header().Set("Idempotent-Replayed", "true")
+	responseWriter.WriteHeader(int(stored.StatusCode.Int32))
+	responseWriter.Write(stored.ResponseBody)
+}
+
+func (config *apiConfig) purgeIdempotencyKeys(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, purgeError := config.db.PurgeIdempotencyKeys(ctx, time.Now()); purgeError != nil {
+			log.Printf("failed to purge idempotency keys: %v\n", purgeError)
+		}
+	}
+}
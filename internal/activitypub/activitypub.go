@@ -0,0 +1,127 @@
+// Package activitypub has the parts of ActivityPub federation that do not
+// depend on Chirpy's database: the vocabulary, WebFinger, HTTP Signatures
+// and delivering activities to remote inboxes.
+package activitypub
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// ContentType is what ActivityPub documents are served and sent as.
+	ContentType = "application/activity+json"
+	// AcceptHeader asks a server for ActivityPub rather than HTML.
+	AcceptHeader = `application/activity+json, application/ld+json; profile="https://www.w3.org/ns/activitystreams"`
+
+	ActivityStreamsContext = "https://www.w3.org/ns/activitystreams"
+	SecurityContext        = "https://w3id.org/security/v1"
+
+	// Public addresses an activity to everyone.
+	Public = "https://www.w3.org/ns/activitystreams#Public"
+)
+
+// Context is the @context of top-level documents. Actors need the security
+// vocabulary for their public key.
+var Context = []string{ActivityStreamsContext, SecurityContext}
+
+type PublicKey struct {
+	ID           string `json:"id"`
+	Owner        string `json:"owner"`
+	PublicKeyPem string `json:"publicKeyPem"`
+}
+
+type Endpoints struct {
+	SharedInbox string `json:"sharedInbox,omitempty"`
+}
+
+type Actor struct {
+	Context           any        `json:"@context,omitempty"`
+	ID                string     `json:"id"`
+	Type              string     `json:"type"`
+	PreferredUsername string     `json:"preferredUsername"`
+	Name              string     `json:"name,omitempty"`
+	Summary           string     `json:"summary,omitempty"`
+	URL               string     `json:"url,omitempty"`
+	Inbox             string     `json:"inbox"`
+	Outbox            string     `json:"outbox,omitempty"`
+	Followers         string     `json:"followers,omitempty"`
+	Endpoints         *Endpoints `json:"endpoints,omitempty"`
+	PublicKey         PublicKey  `json:"publicKey"`
+}
+
+// SharedInbox is where activities for several of actor's server's users can
+// be sent at once, falling back to actor's own inbox.
+func (actor Actor) SharedInbox() string {
+	if actor.Endpoints != nil && len(actor.Endpoints.SharedInbox) > 0 {
+		return actor.Endpoints.SharedInbox
+	}
+
+	return actor.Inbox
+}
+
+type Note struct {
+	Context      any        `json:"@context,omitempty"`
+	ID           string     `json:"id"`
+	Type         string     `json:"type"`
+	AttributedTo string     `json:"attributedTo"`
+	Content      string     `json:"content"`
+	Published    time.Time  `json:"published"`
+	Updated      *time.Time `json:"updated,omitempty"`
+	URL          string     `json:"url,omitempty"`
+	InReplyTo    *string    `json:"inReplyTo"`
+	To           []string   `json:"to"`
+	Cc           []string   `json:"cc,omitempty"`
+}
+
+type Tombstone struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+// Activity is any activity. Object is whatever the activity is about; in
+// received activities it is either a URL or a decoded JSON object, which
+// ObjectID and ObjectType look into.
+type Activity struct {
+	Context   any        `json:"@context,omitempty"`
+	ID        string     `json:"id"`
+	Type      string     `json:"type"`
+	Actor     string     `json:"actor"`
+	Object    any        `json:"object"`
+	Published *time.Time `json:"published,omitempty"`
+	To        []string   `json:"to,omitempty"`
+	Cc        []string   `json:"cc,omitempty"`
+}
+
+func ObjectID(object any) string {
+	switch value := object.(type) {
+	case string:
+		return value
+	case map[string]any:
+		id, _ := value["id"].(string)
+		return id
+	default:
+		return ""
+	}
+}
+
+func ObjectType(object any) string {
+	fields, _ := object.(map[string]any)
+	objectType, _ := fields["type"].(string)
+	return objectType
+}
+
+type OrderedCollection struct {
+	Context      any    `json:"@context,omitempty"`
+	ID           string `json:"id"`
+	Type         string `json:"type"`
+	TotalItems   int    `json:"totalItems"`
+	OrderedItems []any  `json:"orderedItems,omitempty"`
+}
+
+// StripFragment is url without its #fragment. Key IDs are usually the
+// owning actor's URL with a fragment.
+func StripFragment(url string) string {
+	withoutFragment, _, _ := strings.Cut(url, "#")
+	return withoutFragment
+}
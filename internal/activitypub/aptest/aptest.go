@@ -0,0 +1,223 @@
+// Package aptest runs a stand-in fediverse server, the way net/http/httptest
+// runs a stand-in HTTP server. It has a single account whose inbox checks
+// HTTP signatures and records what it receives, and it can follow accounts
+// on other servers. Point it at a Chirpy started with
+// ACTIVITYPUB_ALLOW_LOCAL=true to try federation without leaving the machine.
+package aptest
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/octaviocarpes/go-http-servers/internal/activitypub"
+)
+
+const Username = "standin"
+
+type Instance struct {
+	Server   *httptest.Server
+	ActorURL string
+	KeyID    string
+	Key      *rsa.PrivateKey
+	Client   *activitypub.Client
+
+	publicPEM string
+	follows   atomic.Int64
+
+	mu       sync.Mutex
+	received []activitypub.Activity
+	failures int
+	arrived  chan struct{}
+}
+
+// NewInstance starts an instance on a local port. Close it when done.
+func NewInstance() (*Instance, error) {
+	privatePEM, publicPEM, generateError := activitypub.GenerateKey()
+
+	if generateError != nil {
+		return nil, generateError
+	}
+
+	key, parseError := activitypub.ParsePrivateKey(privatePEM)
+
+	if parseError != nil {
+		return nil, parseError
+	}
+
+	instance := &Instance{
+		Key:       key,
+		publicPEM: publicPEM,
+		arrived:   make(chan struct{}, 1),
+		Client:    activitypub.NewClient("aptest", 10*time.Second, true),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/"+Username, instance.serveActor)
+	mux.HandleFunc("POST /users/"+Username+"/inbox", instance.serveInbox)
+	mux.HandleFunc("POST /inbox", instance.serveInbox)
+
+	instance.Server = httptest.NewServer(mux)
+	instance.ActorURL = instance.Server.URL + "/users/" + Username
+	instance.KeyID = instance.ActorURL + "#main-key"
+
+	return instance, nil
+}
+
+func (instance *Instance) Close() {
+	instance.Server.Close()
+}
+
+// Actor is the instance's account as other servers see it.
+func (instance *Instance) Actor() activitypub.Actor {
+	return activitypub.Actor{
+		Context:           activitypub.Context,
+		ID:                instance.ActorURL,
+		Type:              "Person",
+		PreferredUsername: Username,
+		Inbox:             instance.ActorURL + "/inbox",
+		Endpoints:         &activitypub.Endpoints{SharedInbox: instance.Server.URL + "/inbox"},
+		PublicKey: activitypub.PublicKey{
+			ID:           instance.KeyID,
+			Owner:        instance.ActorURL,
+			PublicKeyPem: instance.publicPEM,
+		},
+	}
+}
+
+func (instance *Instance) serveActor(responseWriter http.ResponseWriter, _ *http.Request) {
+	responseWriter.Header().Set("Content-Type", activitypub.ContentType)
+	json.NewEncoder(responseWriter).Encode(instance.Actor())
+}
+
+func (instance *Instance) serveInbox(responseWriter http.ResponseWriter, req *http.Request) {
+	instance.mu.Lock()
+	failing := instance.failures > 0
+
+	if failing {
+		instance.failures--
+	}
+
+	instance.mu.Unlock()
+
+	if failing {
+		http.Error(responseWriter, "try again later", http.StatusServiceUnavailable)
+		return
+	}
+
+	body, readError := io.ReadAll(io.LimitReader(req.Body, 1<<20))
+
+	if readError != nil {
+		http.Error(responseWriter, readError.Error(), http.StatusBadRequest)
+		return
+	}
+
+	activity, _, receiveError := instance.Client.ReceiveActivity(req, body)
+
+	if receiveError != nil {
+		http.Error(responseWriter, receiveError.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	instance.mu.Lock()
+	instance.received = append(instance.received, activity)
+	instance.mu.Unlock()
+
+	select {
+	case instance.arrived <- struct{}{}:
+	default:
+	}
+
+	responseWriter.WriteHeader(http.StatusAccepted)
+}
+
+// FailNext makes the inbox answer the next n deliveries with 503 Service
+// Unavailable, to exercise retries.
+func (instance *Instance) FailNext(n int) {
+	instance.mu.Lock()
+	defer instance.mu.Unlock()
+
+	instance.failures = n
+}
+
+// Received lists the activities delivered so far, oldest first.
+func (instance *Instance) Received() []activitypub.Activity {
+	instance.mu.Lock()
+	defer instance.mu.Unlock()
+
+	return append([]activitypub.Activity{}, instance.received...)
+}
+
+// WaitFor returns the first delivered activity of activityType, waiting
+// for it until ctx is done.
+func (instance *Instance) WaitFor(ctx context.Context, activityType string) (activitypub.Activity, error) {
+	for {
+		for _, activity := range instance.Received() {
+			if activity.Type == activityType {
+				return activity, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return activitypub.Activity{}, fmt.Errorf("no %s activity arrived: %w", activityType, ctx.Err())
+		case <-instance.arrived:
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+// Send delivers activity to inbox, signed as the instance's account.
+func (instance *Instance) Send(ctx context.Context, inbox string, activity activitypub.Activity) error {
+	body, encodeError := json.Marshal(activity)
+
+	if encodeError != nil {
+		return encodeError
+	}
+
+	return instance.Client.Deliver(ctx, inbox, body, instance.KeyID, instance.Key)
+}
+
+// Follow sends a Follow for the actor at actorURL and returns it, so that
+// it can be undone or matched against the Accept.
+func (instance *Instance) Follow(ctx context.Context, actorURL string) (activitypub.Activity, error) {
+	actor, fetchError := instance.Client.FetchActor(ctx, actorURL)
+
+	if fetchError != nil {
+		return activitypub.Activity{}, fetchError
+	}
+
+	follow := activitypub.Activity{
+		Context: activitypub.ActivityStreamsContext,
+		ID:      fmt.Sprintf("%s/follows/%d", instance.ActorURL, instance.follows.Add(1)),
+		Type:    "Follow",
+		Actor:   instance.ActorURL,
+		Object:  actor.ID,
+	}
+
+	return follow, instance.Send(ctx, actor.Inbox, follow)
+}
+
+// Unfollow undoes follow.
+func (instance *Instance) Unfollow(ctx context.Context, actorURL string, follow activitypub.Activity) error {
+	actor, fetchError := instance.Client.FetchActor(ctx, actorURL)
+
+	if fetchError != nil {
+		return fetchError
+	}
+
+	return instance.Send(ctx, actor.Inbox, activitypub.Activity{
+		Context: activitypub.ActivityStreamsContext,
+		ID:      follow.ID + "/undo",
+		Type:    "Undo",
+		Actor:   instance.ActorURL,
+		Object:  follow,
+	})
+}
@@ -0,0 +1,326 @@
+package activitypub
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	maxDocumentBytes = 1 << 20
+	maxRedirects     = 5
+
+	// ActorCacheTTL is how long a fetched actor is trusted before it is
+	// fetched again. Every signed request names an actor, so without the
+	// cache each one would cost a request to the signer's server.
+	ActorCacheTTL   = 10 * time.Minute
+	maxCachedActors = 10000
+)
+
+var (
+	ErrInsecureURL    = errors.New("remote URL must use https")
+	ErrPrivateAddress = errors.New("remote address is not public")
+)
+
+// sharedAddressSpace is carrier-grade NAT space, which net/netip does not
+// count as private but is no more reachable from the internet.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// Client talks to other servers. Use NewClient, whose connections only reach
+// public addresses however a URL resolves or redirects, since the URLs it
+// is given come from documents anyone can publish.
+//
+// AllowLocal permits plain http:// URLs and loopback and private addresses,
+// which only makes sense when federating with a stand-in instance on the
+// same machine.
+type Client struct {
+	HTTP       *http.Client
+	UserAgent  string
+	AllowLocal bool
+
+	mu     sync.Mutex
+	actors map[string]cachedActor
+}
+
+type cachedActor struct {
+	actor   Actor
+	fetched time.Time
+}
+
+// NewClient returns a Client whose requests time out after timeout.
+func NewClient(userAgent string, timeout time.Duration, allowLocal bool) *Client {
+	client := &Client{UserAgent: userAgent, AllowLocal: allowLocal}
+
+	dialer := &net.Dialer{Timeout: timeout, Control: client.checkAddress}
+
+	client.HTTP = &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConnsPerHost: 4,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+
+			return client.checkURL(req.URL.String())
+		},
+	}
+
+	return client
+}
+
+func (client *Client) checkURL(rawURL string) error {
+	parsed, parseError := url.Parse(rawURL)
+
+	if parseError != nil || len(parsed.Host) == 0 {
+		return fmt.Errorf("invalid remote URL %q", rawURL)
+	}
+
+	if parsed.Scheme == "https" || (parsed.Scheme == "http" && client.AllowLocal) {
+		return nil
+	}
+
+	return ErrInsecureURL
+}
+
+// checkAddress runs for every connection, after DNS resolution, so that a
+// public name cannot lead to an internal address.
+func (client *Client) checkAddress(_, address string, _ syscall.RawConn) error {
+	if client.AllowLocal {
+		return nil
+	}
+
+	host, _, splitError := net.SplitHostPort(address)
+
+	if splitError != nil {
+		return splitError
+	}
+
+	ip, parseError := netip.ParseAddr(host)
+
+	if parseError != nil || !PublicAddress(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+	}
+
+	return nil
+}
+
+// PublicAddress reports whether ip is reachable on the internet, as opposed
+// to loopback, private, link-local (which includes cloud metadata services),
+// unspecified or multicast.
+func PublicAddress(ip netip.Addr) bool {
+	ip = ip.Unmap()
+
+	return ip.IsGlobalUnicast() && !ip.IsPrivate() && !sharedAddressSpace.Contains(ip)
+}
+
+// Actor returns the actor at actorURL, from the cache while it is fresh.
+func (client *Client) Actor(ctx context.Context, actorURL string) (Actor, error) {
+	client.mu.Lock()
+	cached, ok := client.actors[actorURL]
+	client.mu.Unlock()
+
+	if ok && time.Since(cached.fetched) < ActorCacheTTL {
+		return cached.actor, nil
+	}
+
+	return client.FetchActor(ctx, actorURL)
+}
+
+func (client *Client) cacheActor(actor Actor) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	if client.actors == nil {
+		client.actors = map[string]cachedActor{}
+	}
+
+	if len(client.actors) >= maxCachedActors {
+		for actorURL, cached := range client.actors {
+			if time.Since(cached.fetched) >= ActorCacheTTL {
+				delete(client.actors, actorURL)
+			}
+		}
+	}
+
+	if len(client.actors) < maxCachedActors {
+		client.actors[actor.ID] = cachedActor{actor: actor, fetched: time.Now()}
+	}
+}
+
+// FetchActor downloads the actor document at actorURL, bypassing the cache.
+func (client *Client) FetchActor(ctx context.Context, actorURL string) (Actor, error) {
+	if urlError := client.checkURL(actorURL); urlError != nil {
+		return Actor{}, urlError
+	}
+
+	req, requestError := http.NewRequestWithContext(ctx, http.MethodGet, actorURL, nil)
+
+	if requestError != nil {
+		return Actor{}, requestError
+	}
+
+	req.Header.Set("Accept", AcceptHeader)
+	req.Header.Set("User-Agent", client.UserAgent)
+
+	response, getError := client.HTTP.Do(req)
+
+	if getError != nil {
+		return Actor{}, getError
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return Actor{}, fmt.Errorf("fetching actor %s: status %d", actorURL, response.StatusCode)
+	}
+
+	var actor Actor
+
+	if decodeError := json.NewDecoder(io.LimitReader(response.Body, maxDocumentBytes)).Decode(&actor); decodeError != nil {
+		return Actor{}, decodeError
+	}
+
+	if actor.ID != actorURL || len(actor.Inbox) == 0 {
+		return Actor{}, fmt.Errorf("document at %s is not a usable actor", actorURL)
+	}
+
+	client.cacheActor(actor)
+
+	return actor, nil
+}
+
+// DeliveryError is a delivery the remote server turned down. Permanent ones
+// will not succeed however often they are retried.
+type DeliveryError struct {
+	Inbox      string
+	StatusCode int
+}
+
+func (deliveryError *DeliveryError) Error() string {
+	return fmt.Sprintf("delivering to %s: status %d", deliveryError.Inbox, deliveryError.StatusCode)
+}
+
+// Permanent reports whether the status means the server will never take the
+// activity. Client errors are, except those that mean "later".
+func (deliveryError *DeliveryError) Permanent() bool {
+	switch deliveryError.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return false
+	default:
+		return deliveryError.StatusCode >= 400 && deliveryError.StatusCode < 500
+	}
+}
+
+// Deliver posts activity to inbox, signed with the sending actor's key.
+func (client *Client) Deliver(ctx context.Context, inbox string, activity []byte, keyID string, key *rsa.PrivateKey) error {
+	if urlError := client.checkURL(inbox); urlError != nil {
+		return urlError
+	}
+
+	req, requestError := http.NewRequestWithContext(ctx, http.MethodPost, inbox, bytes.NewReader(activity))
+
+	if requestError != nil {
+		return requestError
+	}
+
+	req.Header.Set("Content-Type", ContentType)
+	req.Header.Set("User-Agent", client.UserAgent)
+
+	if signError := Sign(req, activity, keyID, key); signError != nil {
+		return signError
+	}
+
+	response, postError := client.HTTP.Do(req)
+
+	if postError != nil {
+		return postError
+	}
+
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, maxDocumentBytes))
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return &DeliveryError{Inbox: inbox, StatusCode: response.StatusCode}
+	}
+
+	return nil
+}
+
+// Backoff is how long to wait before retrying a delivery that has failed
+// attempts times: a minute, doubling up to a day.
+func Backoff(attempts int) time.Duration {
+	if attempts > 11 {
+		return 24 * time.Hour
+	}
+
+	return min(time.Minute<<max(attempts-1, 0), 24*time.Hour)
+}
+
+// WebFinger resolves an account such as "alice@example.com" to its actor URL.
+func (client *Client) WebFinger(ctx context.Context, account string) (string, error) {
+	user, host, ok := ParseAccount(account)
+
+	if !ok {
+		return "", fmt.Errorf("invalid account %q", account)
+	}
+
+	scheme := "https"
+
+	if client.AllowLocal {
+		scheme = "http"
+	}
+
+	query := url.Values{"resource": {"acct:" + user + "@" + host}}
+	lookupURL := scheme + "://" + host + "/.well-known/webfinger?" + query.Encode()
+
+	req, requestError := http.NewRequestWithContext(ctx, http.MethodGet, lookupURL, nil)
+
+	if requestError != nil {
+		return "", requestError
+	}
+
+	req.Header.Set("Accept", WebFingerContentType)
+	req.Header.Set("User-Agent", client.UserAgent)
+
+	response, getError := client.HTTP.Do(req)
+
+	if getError != nil {
+		return "", getError
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("webfinger %s: status %d", account, response.StatusCode)
+	}
+
+	var jrd JRD
+
+	if decodeError := json.NewDecoder(io.LimitReader(response.Body, maxDocumentBytes)).Decode(&jrd); decodeError != nil {
+		return "", decodeError
+	}
+
+	for _, link := range jrd.Links {
+		if link.Rel == "self" && link.Type == ContentType {
+			return link.Href, nil
+		}
+	}
+
+	return "", fmt.Errorf("webfinger %s: no actor link", account)
+}
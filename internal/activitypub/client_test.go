@@ -0,0 +1,143 @@
+package activitypub_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/octaviocarpes/go-http-servers/internal/activitypub"
+	"github.com/octaviocarpes/go-http-servers/internal/activitypub/aptest"
+)
+
+func newInstance(t *testing.T) *aptest.Instance {
+	t.Helper()
+
+	instance, startError := aptest.NewInstance()
+
+	if startError != nil {
+		t.Fatalf("NewInstance: %v", startError)
+	}
+
+	t.Cleanup(instance.Close)
+
+	return instance
+}
+
+func TestDeliverBetweenInstances(t *testing.T) {
+	sender, receiver := newInstance(t), newInstance(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	follow, followError := sender.Follow(ctx, receiver.ActorURL)
+
+	if followError != nil {
+		t.Fatalf("Follow: %v", followError)
+	}
+
+	received, waitError := receiver.WaitFor(ctx, "Follow")
+
+	if waitError != nil {
+		t.Fatal(waitError)
+	}
+
+	if received.ID != follow.ID || activitypub.ObjectID(received.Object) != receiver.ActorURL {
+		t.Fatalf("received %+v", received)
+	}
+
+	if unfollowError := sender.Unfollow(ctx, receiver.ActorURL, follow); unfollowError != nil {
+		t.Fatalf("Unfollow: %v", unfollowError)
+	}
+
+	undo, waitError := receiver.WaitFor(ctx, "Undo")
+
+	if waitError != nil {
+		t.Fatal(waitError)
+	}
+
+	if activitypub.ObjectType(undo.Object) != "Follow" || activitypub.ObjectID(undo.Object) != follow.ID {
+		t.Fatalf("undo object = %+v", undo.Object)
+	}
+}
+
+func TestDeliverFailures(t *testing.T) {
+	sender, receiver := newInstance(t), newInstance(t)
+	ctx := context.Background()
+	inbox := receiver.Actor().Inbox
+
+	receiver.FailNext(1)
+	sendError := sender.Send(ctx, inbox, activitypub.Activity{ID: sender.ActorURL + "/1", Type: "Create", Actor: sender.ActorURL})
+
+	var deliveryError *activitypub.DeliveryError
+
+	if !errors.As(sendError, &deliveryError) || deliveryError.Permanent() {
+		t.Fatalf("Send to a failing inbox = %v, want a temporary DeliveryError", sendError)
+	}
+
+	// Signed by the sender on behalf of someone else.
+	sendError = sender.Send(ctx, inbox, activitypub.Activity{ID: sender.ActorURL + "/2", Type: "Create", Actor: receiver.ActorURL})
+
+	if !errors.As(sendError, &deliveryError) || deliveryError.StatusCode != http.StatusUnauthorized || !deliveryError.Permanent() {
+		t.Fatalf("Send of a forged activity = %v, want a permanent 401", sendError)
+	}
+
+	if received := receiver.Received(); len(received) != 0 {
+		t.Fatalf("receiver accepted %+v", received)
+	}
+}
+
+func TestClientRequiresHTTPS(t *testing.T) {
+	instance := newInstance(t)
+	client := activitypub.NewClient("test", time.Second, false)
+
+	if _, fetchError := client.FetchActor(context.Background(), instance.ActorURL); !errors.Is(fetchError, activitypub.ErrInsecureURL) {
+		t.Fatalf("FetchActor over http = %v", fetchError)
+	}
+}
+
+func TestClientRefusesPrivateAddresses(t *testing.T) {
+	remote := httptest.NewTLSServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) {
+		t.Error("the client reached a loopback server")
+	}))
+	defer remote.Close()
+
+	client := activitypub.NewClient("test", time.Second, false)
+	client.HTTP.Transport.(*http.Transport).TLSClientConfig = remote.Client().Transport.(*http.Transport).TLSClientConfig
+
+	if _, fetchError := client.FetchActor(context.Background(), remote.URL+"/users/alice"); !errors.Is(fetchError, activitypub.ErrPrivateAddress) {
+		t.Fatalf("FetchActor from loopback = %v", fetchError)
+	}
+
+	deliverError := client.Deliver(context.Background(), remote.URL+"/inbox", []byte("{}"), "https://chirpy.example/ap/users/1#main-key", newInstance(t).Key)
+
+	if !errors.Is(deliverError, activitypub.ErrPrivateAddress) {
+		t.Fatalf("Deliver to loopback = %v", deliverError)
+	}
+}
+
+func TestPublicAddress(t *testing.T) {
+	tests := map[string]bool{
+		"93.184.216.34":    true,
+		"2606:4700::1111":  true,
+		"127.0.0.1":        false,
+		"10.1.2.3":         false,
+		"172.16.0.1":       false,
+		"192.168.1.1":      false,
+		"169.254.169.254":  false,
+		"100.64.0.1":       false,
+		"0.0.0.0":          false,
+		"::1":              false,
+		"fd00::1":          false,
+		"fe80::1":          false,
+		"::ffff:127.0.0.1": false,
+	}
+
+	for address, want := range tests {
+		if got := activitypub.PublicAddress(netip.MustParseAddr(address)); got != want {
+			t.Errorf("PublicAddress(%s) = %v", address, got)
+		}
+	}
+}
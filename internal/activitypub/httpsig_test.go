@@ -0,0 +1,173 @@
+package activitypub
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testKey(t *testing.T) (string, string) {
+	t.Helper()
+
+	privatePEM, publicPEM, generateError := GenerateKey()
+
+	if generateError != nil {
+		t.Fatalf("GenerateKey: %v", generateError)
+	}
+
+	return privatePEM, publicPEM
+}
+
+// signedRequest signs a request the way a client would and returns it as a
+// server receives it.
+func signedRequest(t *testing.T, privatePEM string, body string) *http.Request {
+	t.Helper()
+
+	key, parseError := ParsePrivateKey(privatePEM)
+
+	if parseError != nil {
+		t.Fatalf("ParsePrivateKey: %v", parseError)
+	}
+
+	outgoing, _ := http.NewRequest(http.MethodPost, "https://chirpy.example/ap/users/1/inbox?x=1", strings.NewReader(body))
+
+	if signError := Sign(outgoing, []byte(body), "https://remote.example/users/bob#main-key", key); signError != nil {
+		t.Fatalf("Sign: %v", signError)
+	}
+
+	incoming := httptest.NewRequest(http.MethodPost, "https://chirpy.example/ap/users/1/inbox?x=1", strings.NewReader(body))
+	incoming.Header = outgoing.Header.Clone()
+
+	return incoming
+}
+
+func verify(t *testing.T, req *http.Request, body string, publicPEM string) error {
+	t.Helper()
+
+	key, parseError := ParsePublicKey(publicPEM)
+
+	if parseError != nil {
+		t.Fatalf("ParsePublicKey: %v", parseError)
+	}
+
+	signature, signatureError := ParseSignature(req)
+
+	if signatureError != nil {
+		return signatureError
+	}
+
+	if signature.KeyID != "https://remote.example/users/bob#main-key" {
+		t.Fatalf("KeyID = %q", signature.KeyID)
+	}
+
+	return signature.Verify(req, []byte(body), key)
+}
+
+func TestSignAndVerify(t *testing.T) {
+	privatePEM, publicPEM := testKey(t)
+	body := `{"type":"Follow"}`
+
+	if verifyError := verify(t, signedRequest(t, privatePEM, body), body, publicPEM); verifyError != nil {
+		t.Fatalf("Verify: %v", verifyError)
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	privatePEM, publicPEM := testKey(t)
+	_, otherPublicPEM := testKey(t)
+	body := `{"type":"Follow"}`
+
+	tests := []struct {
+		name      string
+		publicPEM string
+		body      string
+		tamper    func(req *http.Request)
+	}{
+		{name: "another key", publicPEM: otherPublicPEM, body: body},
+		{name: "changed body", publicPEM: publicPEM, body: `{"type":"Delete"}`},
+		{
+			name:      "changed target",
+			publicPEM: publicPEM,
+			body:      body,
+			tamper:    func(req *http.Request) { req.URL.Path = "/ap/users/2/inbox" },
+		},
+		{
+			name:      "old date",
+			publicPEM: publicPEM,
+			body:      body,
+			tamper: func(req *http.Request) {
+				req.Header.Set("Date", time.Now().Add(-2*MaxClockSkew).UTC().Format(http.TimeFormat))
+			},
+		},
+		{
+			name:      "digest left unsigned",
+			publicPEM: publicPEM,
+			body:      body,
+			tamper: func(req *http.Request) {
+				req.Header.Set("Signature", strings.Replace(req.Header.Get("Signature"), " digest", "", 1))
+			},
+		},
+		{
+			name:      "unsigned",
+			publicPEM: publicPEM,
+			body:      body,
+			tamper:    func(req *http.Request) { req.Header.Del("Signature") },
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := signedRequest(t, privatePEM, body)
+
+			if test.tamper != nil {
+				test.tamper(req)
+			}
+
+			verifyError := verify(t, req, test.body, test.publicPEM)
+
+			if !errors.Is(verifyError, ErrInvalidSignature) && !errors.Is(verifyError, ErrNoSignature) {
+				t.Fatalf("Verify = %v, want a signature error", verifyError)
+			}
+		})
+	}
+}
+
+func TestParseAccount(t *testing.T) {
+	tests := []struct {
+		resource string
+		user     string
+		host     string
+		ok       bool
+	}{
+		{resource: "acct:alice@Chirpy.Example", user: "alice", host: "chirpy.example", ok: true},
+		{resource: "@alice@localhost:8080", user: "alice", host: "localhost:8080", ok: true},
+		{resource: "acct:alice"},
+		{resource: "acct:@chirpy.example"},
+		{resource: "acct:alice@evil.example/path"},
+	}
+
+	for _, test := range tests {
+		user, host, ok := ParseAccount(test.resource)
+
+		if user != test.user || host != test.host || ok != test.ok {
+			t.Errorf("ParseAccount(%q) = %q, %q, %v", test.resource, user, host, ok)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	if got := Backoff(1); got != time.Minute {
+		t.Errorf("Backoff(1) = %v", got)
+	}
+
+	if got := Backoff(4); got != 8*time.Minute {
+		t.Errorf("Backoff(4) = %v", got)
+	}
+
+	if got := Backoff(40); got != 24*time.Hour {
+		t.Errorf("Backoff(40) = %v", got)
+	}
+}
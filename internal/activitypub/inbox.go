@@ -0,0 +1,66 @@
+package activitypub
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ReceiveActivity authenticates an activity posted to an inbox. It looks up
+// the actor that signed the request, verifies the signature with their
+// published key and checks that the activity is that actor's own. Any error
+// means the request should be turned away as unauthorized.
+func (client *Client) ReceiveActivity(req *http.Request, body []byte) (Activity, Actor, error) {
+	signature, parseError := ParseSignature(req)
+
+	if parseError != nil {
+		return Activity{}, Actor{}, parseError
+	}
+
+	actorURL := StripFragment(signature.KeyID)
+	actor, fetchError := client.Actor(req.Context(), actorURL)
+
+	if fetchError != nil {
+		return Activity{}, Actor{}, fetchError
+	}
+
+	// The cached actor may predate a key rotation, so a signature it
+	// cannot verify gets one more try against a fresh copy.
+	if verifyError := verifyWith(req, body, signature, actor); verifyError != nil {
+		actor, fetchError = client.FetchActor(req.Context(), actorURL)
+
+		if fetchError != nil {
+			return Activity{}, Actor{}, fetchError
+		}
+
+		if verifyError := verifyWith(req, body, signature, actor); verifyError != nil {
+			return Activity{}, Actor{}, verifyError
+		}
+	}
+
+	var activity Activity
+
+	if decodeError := json.Unmarshal(body, &activity); decodeError != nil {
+		return Activity{}, Actor{}, decodeError
+	}
+
+	if activity.Actor != actor.ID {
+		return Activity{}, Actor{}, fmt.Errorf("%w: signed by %s on behalf of %s", ErrInvalidSignature, actor.ID, activity.Actor)
+	}
+
+	return activity, actor, nil
+}
+
+func verifyWith(req *http.Request, body []byte, signature Signature, actor Actor) error {
+	if actor.PublicKey.ID != signature.KeyID {
+		return fmt.Errorf("%w: %s does not own key %s", ErrInvalidSignature, actor.ID, signature.KeyID)
+	}
+
+	key, keyError := ParsePublicKey(actor.PublicKey.PublicKeyPem)
+
+	if keyError != nil {
+		return keyError
+	}
+
+	return signature.Verify(req, body, key)
+}
@@ -0,0 +1,72 @@
+package activitypub
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+)
+
+const keyBits = 2048
+
+var errNotRSAKey = errors.New("not an RSA key")
+
+// GenerateKey makes a key pair for signing an actor's requests, PEM encoded
+// the way actor documents publish it.
+func GenerateKey() (privatePEM, publicPEM string, err error) {
+	key, generateError := rsa.GenerateKey(rand.Reader, keyBits)
+
+	if generateError != nil {
+		return "", "", generateError
+	}
+
+	publicDER, marshalError := x509.MarshalPKIXPublicKey(&key.PublicKey)
+
+	if marshalError != nil {
+		return "", "", marshalError
+	}
+
+	privatePEM = string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+	publicPEM = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+
+	return privatePEM, publicPEM, nil
+}
+
+func ParsePrivateKey(privatePEM string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(privatePEM))
+
+	if block == nil {
+		return nil, errNotRSAKey
+	}
+
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
+
+// ParsePublicKey reads a publicKeyPem. Both PKIX and PKCS #1 encodings are
+// in use across the fediverse.
+func ParsePublicKey(publicPEM string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(publicPEM))
+
+	if block == nil {
+		return nil, errNotRSAKey
+	}
+
+	if block.Type == "RSA PUBLIC KEY" {
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+
+	key, parseError := x509.ParsePKIXPublicKey(block.Bytes)
+
+	if parseError != nil {
+		return nil, parseError
+	}
+
+	rsaKey, ok := key.(*rsa.PublicKey)
+
+	if !ok {
+		return nil, errNotRSAKey
+	}
+
+	return rsaKey, nil
+}
@@ -0,0 +1,33 @@
+package activitypub
+
+import "strings"
+
+const WebFingerContentType = "application/jrd+json"
+
+type Link struct {
+	Rel  string `json:"rel"`
+	Type string `json:"type,omitempty"`
+	Href string `json:"href"`
+}
+
+// JRD is a WebFinger response.
+type JRD struct {
+	Subject string   `json:"subject"`
+	Aliases []string `json:"aliases,omitempty"`
+	Links   []Link   `json:"links"`
+}
+
+// ParseAccount splits a WebFinger resource such as "acct:alice@example.com"
+// into the user and host. The acct: scheme may be left out.
+func ParseAccount(resource string) (user, host string, ok bool) {
+	account := strings.TrimPrefix(resource, "acct:")
+	account = strings.TrimPrefix(account, "@")
+
+	user, host, found := strings.Cut(account, "@")
+
+	if !found || len(user) == 0 || len(host) == 0 || strings.ContainsAny(host, "@/") {
+		return "", "", false
+	}
+
+	return user, strings.ToLower(host), true
+}
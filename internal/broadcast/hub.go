@@ -0,0 +1,106 @@
+package broadcast
+
+import "sync"
+
+// Event is one message on the hub. IDs are given by whoever publishes the
+// event and must increase from one event to the next, which is what lets a
+// subscriber resume after the last one it saw.
+type Event struct {
+	ID       int64
+	Type     string
+	AuthorID string
+	// Mentions are the users the event's chirp mentions.
+	Mentions []string
+	Data     []byte
+}
+
+// Subscription receives every event published after it was created. Events
+// is closed when the subscriber falls too far behind; the client is expected
+// to reconnect and resume.
+type Subscription struct {
+	Events <-chan Event
+	events chan Event
+}
+
+// Hub fans events out to subscribers and keeps the most recent ones for
+// replay. A subscriber that cannot keep up is dropped instead of slowing
+// down everyone else.
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[*Subscription]bool
+	replay      []Event
+	replaySize  int
+	bufferSize  int
+}
+
+// NewHub keeps replaySize events for resumption and lets each subscriber
+// fall bufferSize events behind before it is dropped.
+func NewHub(replaySize, bufferSize int) *Hub {
+	return &Hub{
+		subscribers: map[*Subscription]bool{},
+		replay:      make([]Event, 0, replaySize),
+		replaySize:  replaySize,
+		bufferSize:  bufferSize,
+	}
+}
+
+// Publish sends event to every subscriber and keeps it for replay.
+func (hub *Hub) Publish(event Event) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	if len(hub.replay) == hub.replaySize {
+		copy(hub.replay, hub.replay[1:])
+		hub.replay = hub.replay[:len(hub.replay)-1]
+	}
+
+	hub.replay = append(hub.replay, event)
+
+	for subscription := range hub.subscribers {
+		select {
+		case subscription.events <- event:
+		default:
+			hub.drop(subscription)
+		}
+	}
+}
+
+// Subscribe starts a subscription. When resuming after lastEventID it also
+// returns the buffered events since then. complete is false, and there is no
+// replay, unless lastEventID is still in the buffer: it may no longer reach
+// back that far, or the event may have been published before this hub
+// started.
+func (hub *Hub) Subscribe(lastEventID int64, resuming bool) (subscription *Subscription, replay []Event, complete bool) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	events := make(chan Event, hub.bufferSize)
+	subscription = &Subscription{Events: events, events: events}
+	hub.subscribers[subscription] = true
+
+	if !resuming {
+		return subscription, []Event{}, true
+	}
+
+	for i, event := range hub.replay {
+		if event.ID == lastEventID {
+			return subscription, append([]Event{}, hub.replay[i+1:]...), true
+		}
+	}
+
+	return subscription, []Event{}, false
+}
+
+func (hub *Hub) Unsubscribe(subscription *Subscription) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	if hub.subscribers[subscription] {
+		hub.drop(subscription)
+	}
+}
+
+func (hub *Hub) drop(subscription *Subscription) {
+	delete(hub.subscribers, subscription)
+	close(subscription.events)
+}
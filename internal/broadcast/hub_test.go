@@ -0,0 +1,111 @@
+package broadcast
+
+import "testing"
+
+func drain(subscription *Subscription) []int64 {
+	ids := []int64{}
+
+	for {
+		select {
+		case event, ok := <-subscription.Events:
+			if !ok {
+				return ids
+			}
+
+			ids = append(ids, event.ID)
+		default:
+			return ids
+		}
+	}
+}
+
+func TestPublishReachesSubscribers(t *testing.T) {
+	hub := NewHub(10, 10)
+	first, _, _ := hub.Subscribe(0, false)
+	second, _, _ := hub.Subscribe(0, false)
+
+	hub.Publish(Event{ID: 1})
+	hub.Publish(Event{ID: 2})
+
+	for _, subscription := range []*Subscription{first, second} {
+		if got := drain(subscription); len(got) != 2 || got[0] != 1 || got[1] != 2 {
+			t.Errorf("received %v, want [1 2]", got)
+		}
+	}
+
+	hub.Unsubscribe(first)
+	hub.Publish(Event{ID: 3})
+
+	if _, open := <-first.Events; open {
+		t.Error("unsubscribed subscription is still open")
+	}
+
+	if got := drain(second); len(got) != 1 || got[0] != 3 {
+		t.Errorf("received %v, want [3]", got)
+	}
+}
+
+func TestSubscribeReplaysFromLastEventID(t *testing.T) {
+	hub := NewHub(3, 10)
+
+	for id := range int64(5) {
+		hub.Publish(Event{ID: id + 1})
+	}
+
+	_, replay, complete := hub.Subscribe(3, true)
+
+	if len(replay) != 2 || replay[0].ID != 4 || replay[1].ID != 5 || !complete {
+		t.Errorf("replay after the third event = %v complete=%v, want the last two and complete", replay, complete)
+	}
+
+	_, replay, complete = hub.Subscribe(5, true)
+
+	if len(replay) != 0 || !complete {
+		t.Errorf("replay after the last event = %v complete=%v", replay, complete)
+	}
+
+	_, replay, complete = hub.Subscribe(1, true)
+
+	if len(replay) != 0 || complete {
+		t.Errorf("replay after an evicted event = %v complete=%v, want nothing and incomplete", replay, complete)
+	}
+
+	_, replay, complete = hub.Subscribe(0, false)
+
+	if len(replay) != 0 || !complete {
+		t.Errorf("new subscription replayed %v", replay)
+	}
+}
+
+// IDs may skip numbers, so only an ID the buffer holds marks a position in
+// it, even when it falls inside the buffer's range.
+func TestSubscribeNeedsABufferedID(t *testing.T) {
+	hub := NewHub(10, 10)
+	hub.Publish(Event{ID: 10})
+	hub.Publish(Event{ID: 20})
+
+	if _, replay, complete := hub.Subscribe(15, true); len(replay) != 0 || complete {
+		t.Errorf("replay after an unknown id = %v complete=%v", replay, complete)
+	}
+
+	if _, replay, complete := hub.Subscribe(9, true); len(replay) != 0 || complete {
+		t.Errorf("replay after an id before the buffer = %v complete=%v", replay, complete)
+	}
+}
+
+func TestSlowSubscriberIsDropped(t *testing.T) {
+	hub := NewHub(10, 2)
+	slow, _, _ := hub.Subscribe(0, false)
+
+	for id := range int64(3) {
+		hub.Publish(Event{ID: id + 1})
+	}
+
+	if got := drain(slow); len(got) != 2 {
+		t.Errorf("slow subscriber received %v before being dropped", got)
+	}
+
+	if _, open := <-slow.Events; open {
+		t.Error("slow subscriber was not dropped")
+	}
+}
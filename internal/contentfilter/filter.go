@@ -0,0 +1,152 @@
+package contentfilter
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+type Action string
+
+const (
+	// ActionMask replaces the word with asterisks and lets the chirp through.
+	ActionMask Action = "mask"
+	// ActionReject refuses the whole chirp.
+	ActionReject Action = "reject"
+	// ActionFlag lets the chirp through unchanged and queues it for review.
+	ActionFlag Action = "flag"
+)
+
+const mask = "****"
+
+func (action Action) Valid() bool {
+	return action == ActionMask || action == ActionReject || action == ActionFlag
+}
+
+type Rule struct {
+	ID      string
+	Pattern string
+	Action  Action
+}
+
+type Match struct {
+	RuleID string
+	Word   string
+	Action Action
+}
+
+// Result is what a filter made of a chirp body. Body has the masked words
+// replaced; Rejected and Flagged summarize the other matches.
+type Result struct {
+	Body     string
+	Rejected bool
+	Flagged  bool
+	Matches  []Match
+}
+
+// Filter matches whole words against a set of rules. It is immutable, so a
+// single Filter can be shared by every request.
+type Filter struct {
+	rules map[string]Rule
+}
+
+// New builds a filter from rules. When two patterns normalize to the same
+// skeleton, the stricter action wins.
+func New(rules []Rule) *Filter {
+	filter := &Filter{rules: make(map[string]Rule, len(rules))}
+
+	for _, rule := range rules {
+		skeleton := Normalize(rule.Pattern)
+
+		if len(skeleton) == 0 {
+			continue
+		}
+
+		if existing, ok := filter.rules[skeleton]; ok && severity(existing.Action) >= severity(rule.Action) {
+			continue
+		}
+
+		filter.rules[skeleton] = rule
+	}
+
+	return filter
+}
+
+func severity(action Action) int {
+	switch action {
+	case ActionReject:
+		return 3
+	case ActionFlag:
+		return 2
+	default:
+		return 1
+	}
+}
+
+func (filter *Filter) match(word string) (Rule, bool) {
+	for _, skeleton := range skeletons(word) {
+		if rule, ok := filter.rules[skeleton]; ok {
+			return rule, true
+		}
+	}
+
+	return Rule{}, false
+}
+
+// Apply runs body through the filter.
+func (filter *Filter) Apply(body string) Result {
+	result := Result{Matches: []Match{}}
+
+	var masked strings.Builder
+	written := 0
+
+	for _, candidates := range tokenize(body) {
+		for _, candidate := range candidates {
+			word := body[candidate.start:candidate.end]
+			rule, ok := filter.match(word)
+
+			if !ok {
+				continue
+			}
+
+			result.Matches = append(result.Matches, Match{RuleID: rule.ID, Word: word, Action: rule.Action})
+
+			switch rule.Action {
+			case ActionReject:
+				result.Rejected = true
+			case ActionFlag:
+				result.Flagged = true
+			default:
+				masked.WriteString(body[written:candidate.start])
+				masked.WriteString(mask)
+				written = candidate.end
+			}
+
+			break
+		}
+	}
+
+	masked.WriteString(body[written:])
+	result.Body = masked.String()
+
+	return result
+}
+
+// Holder keeps the current filter and lets it be swapped while requests are
+// using it, which is how rule changes take effect without a restart.
+type Holder struct {
+	current atomic.Pointer[Filter]
+}
+
+func NewHolder(filter *Filter) *Holder {
+	holder := &Holder{}
+	holder.current.Store(filter)
+	return holder
+}
+
+func (holder *Holder) Load() *Filter {
+	return holder.current.Load()
+}
+
+func (holder *Holder) Store(filter *Filter) {
+	holder.current.Store(filter)
+}
@@ -0,0 +1,123 @@
+package contentfilter
+
+import "testing"
+
+func defaultRules() []Rule {
+	return []Rule{
+		{ID: "1", Pattern: "kerfuffle", Action: ActionMask},
+		{ID: "2", Pattern: "sharbert", Action: ActionMask},
+		{ID: "3", Pattern: "fornax", Action: ActionMask},
+		{ID: "4", Pattern: "spamword", Action: ActionReject},
+		{ID: "5", Pattern: "suspicious", Action: ActionFlag},
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	cases := map[string]string{
+		"Kerfuffle":             Normalize("kerfuffle"),
+		"KERFUUUFFLE":           Normalize("kerfuffle"),
+		"k3rfuffl3":             Normalize("kerfuffle"),
+		"k\u00e9rf\u00fcffle":   Normalize("kerfuffle"),
+		"ke\u0301rfu\u0308ffle": Normalize("kerfuffle"),
+		"\uff4b\uff45\uff52\uff46\uff55\uff46\uff46\uff4c\uff45": Normalize("kerfuffle"),
+		"k\u0435rfuffl\u0435": Normalize("kerfuffle"),
+		"ker\u200bfuffle":     Normalize("kerfuffle"),
+		"$h@rb3rt":            Normalize("sharbert"),
+		"f0rn4x":              Normalize("fornax"),
+	}
+
+	for input, want := range cases {
+		if got := Normalize(input); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestApplyMasksPunctuatedWords(t *testing.T) {
+	filter := New(defaultRules())
+
+	cases := map[string]string{
+		"I had something interesting for breakfast": "I had something interesting for breakfast",
+		"Kerfuffle!":                         "****!",
+		"what a fornax.":                     "what a ****.",
+		"(sharbert), k3rfuffl3 and F0RNAX?!": "(****), **** and ****?!",
+		"$harbert is here":                   "**** is here",
+		"kerfuffles are fine":                "kerfuffles are fine",
+		"mail fornax@example.com":            "mail fornax@example.com",
+	}
+
+	for body, want := range cases {
+		result := filter.Apply(body)
+
+		if result.Body != want || result.Rejected || result.Flagged {
+			t.Errorf("Apply(%q) = %+v, want body %q", body, result, want)
+		}
+	}
+}
+
+func TestApplyKeepsDistinctWordsApart(t *testing.T) {
+	filter := New([]Rule{
+		{ID: "1", Pattern: "boob", Action: ActionMask},
+		{ID: "2", Pattern: "ass", Action: ActionMask},
+	})
+
+	cases := map[string]string{
+		"Bob said hi":          "Bob said hi",
+		"call 8008 now":        "call 8008 now",
+		"as good as it gets":   "as good as it gets",
+		"I am 45":              "I am 45",
+		"kind of a boob":       "kind of a ****",
+		"b00b and boooob":      "**** and ****",
+		"what an a$$":          "what an ****",
+		"such an asssss":       "such an ****",
+		"Bobby passes the ass": "Bobby passes the ****",
+	}
+
+	for body, want := range cases {
+		if result := filter.Apply(body); result.Body != want {
+			t.Errorf("Apply(%q) = %q, want %q", body, result.Body, want)
+		}
+	}
+}
+
+func TestApplyActions(t *testing.T) {
+	filter := New(defaultRules())
+
+	rejected := filter.Apply("buy SPAMW0RD now")
+
+	if !rejected.Rejected || len(rejected.Matches) != 1 || rejected.Matches[0].RuleID != "4" {
+		t.Fatalf("reject rule not applied: %+v", rejected)
+	}
+
+	flagged := filter.Apply("this is suspicious, fornax")
+
+	if !flagged.Flagged || flagged.Rejected || flagged.Body != "this is suspicious, ****" {
+		t.Fatalf("flag rule not applied: %+v", flagged)
+	}
+}
+
+func TestNewPrefersStricterAction(t *testing.T) {
+	filter := New([]Rule{
+		{ID: "1", Pattern: "fornax", Action: ActionMask},
+		{ID: "2", Pattern: "F0RNAX", Action: ActionReject},
+		{ID: "3", Pattern: "fornax", Action: ActionFlag},
+	})
+
+	if result := filter.Apply("fornax"); !result.Rejected {
+		t.Fatalf("expected the reject rule to win, got %+v", result)
+	}
+}
+
+func TestHolderSwapsFilters(t *testing.T) {
+	holder := NewHolder(New(nil))
+
+	if result := holder.Load().Apply("fornax"); result.Body != "fornax" {
+		t.Fatalf("empty filter changed the body: %q", result.Body)
+	}
+
+	holder.Store(New(defaultRules()))
+
+	if result := holder.Load().Apply("fornax"); result.Body != "****" {
+		t.Fatalf("reloaded filter did not apply: %q", result.Body)
+	}
+}
@@ -0,0 +1,187 @@
+package contentfilter
+
+import (
+	"strings"
+	"unicode"
+)
+
+// foldings maps characters people substitute for letters to the letter they
+// stand for: accented Latin letters, Cyrillic and Greek lookalikes, and
+// leetspeak digits and symbols. Both rules and chirps are folded the same
+// way, so several letters may share one target (l, 1, | and ! all become i).
+var foldings = map[rune]rune{
+	// Latin with diacritics.
+	'à': 'a', 'á': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a', 'å': 'a', 'ā': 'a', 'ă': 'a', 'ą': 'a',
+	'ç': 'c', 'ć': 'c', 'č': 'c',
+	'ď': 'd', 'đ': 'd',
+	'è': 'e', 'é': 'e', 'ê': 'e', 'ë': 'e', 'ē': 'e', 'ė': 'e', 'ę': 'e', 'ě': 'e',
+	'ğ': 'g',
+	'ì': 'i', 'í': 'i', 'î': 'i', 'ï': 'i', 'ī': 'i', 'į': 'i', 'ı': 'i',
+	'ñ': 'n', 'ń': 'n', 'ň': 'n',
+	'ò': 'o', 'ó': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o', 'ø': 'o', 'ō': 'o', 'ő': 'o',
+	'ř': 'r',
+	'ś': 's', 'š': 's', 'ş': 's', 'ß': 's',
+	'ť': 't', 'ţ': 't',
+	'ù': 'u', 'ú': 'u', 'û': 'u', 'ü': 'u', 'ū': 'u', 'ů': 'u', 'ű': 'u',
+	'ý': 'y', 'ÿ': 'y',
+	'ź': 'z', 'ż': 'z', 'ž': 'z',
+
+	// Cyrillic homoglyphs.
+	'а': 'a', 'в': 'b', 'е': 'e', 'ё': 'e', 'к': 'k', 'м': 'm', 'н': 'h', 'о': 'o',
+	'р': 'p', 'с': 'c', 'т': 't', 'у': 'y', 'х': 'x', 'і': 'i', 'ј': 'j', 'ѕ': 's',
+
+	// Greek homoglyphs.
+	'α': 'a', 'β': 'b', 'ε': 'e', 'η': 'n', 'ι': 'i', 'κ': 'k', 'ν': 'v', 'ο': 'o',
+	'ρ': 'p', 'τ': 't', 'υ': 'u', 'χ': 'x',
+
+	// Leetspeak.
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '6': 'g', '7': 't', '8': 'b', '9': 'g',
+	'@': 'a', '$': 's', '!': 'i', '|': 'i', '+': 't',
+
+	// Letters that leetspeak makes ambiguous.
+	'l': 'i',
+}
+
+// Normalize reduces word to the skeleton that rules are matched against:
+// compatibility forms are unfolded, case and marks are dropped, lookalikes
+// and leetspeak are folded, and letters stretched to three or more are
+// collapsed so that "KERFUUUFFLE" and "k3rfuffl3" meet "kerfuffle". Doubled
+// letters are kept, so that "bob" does not meet "boob" nor "as" "ass", and a
+// word made only of digits is a number rather than leetspeak.
+func Normalize(word string) string {
+	return collapse(fold(word), 1)
+}
+
+// skeletons are the skeletons a word in a chirp may match a rule by: its
+// Normalize form and, since a stretched letter may stand for a doubled one
+// as in "boooob", the form with stretches cut to two.
+func skeletons(word string) []string {
+	folded := fold(word)
+	single, double := collapse(folded, 1), collapse(folded, 2)
+
+	if single == double {
+		return []string{single}
+	}
+
+	return []string{single, double}
+}
+
+func fold(word string) []rune {
+	number := len(word) > 0 && strings.IndexFunc(word, func(r rune) bool { return !unicode.IsDigit(r) }) < 0
+	runes := make([]rune, 0, len(word))
+
+	for _, r := range word {
+		// Fullwidth forms, as in "ｋｅｒｆｕｆｆｌｅ".
+		if r >= 0xFF01 && r <= 0xFF5E {
+			r -= 0xFF01 - '!'
+		}
+
+		if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+			continue
+		}
+
+		r = unicode.ToLower(r)
+
+		if folded, ok := foldings[r]; ok && !(number && unicode.IsDigit(r)) {
+			r = folded
+		}
+
+		runes = append(runes, r)
+	}
+
+	return runes
+}
+
+// collapse shortens every run of three or more equal runes to keep of them.
+func collapse(runes []rune, keep int) string {
+	var skeleton strings.Builder
+
+	for start := 0; start < len(runes); {
+		end := start
+
+		for end < len(runes) && runes[end] == runes[start] {
+			end++
+		}
+
+		length := end - start
+
+		if length >= 3 {
+			length = keep
+		}
+
+		for range length {
+			skeleton.WriteRune(runes[start])
+		}
+
+		start = end
+	}
+
+	return skeleton.String()
+}
+
+// isWordRune reports whether r can be part of a token. Leetspeak symbols are
+// included so that "$harbert" stays one word; trailing ones are trimmed again
+// by tokenize when they are just punctuation.
+func isWordRune(r rune) bool {
+	if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+		return true
+	}
+
+	return r == '@' || r == '$' || r == '!' || r == '|' || r == '+'
+}
+
+func isLeetSymbol(r rune) bool {
+	return r == '@' || r == '$' || r == '!' || r == '|' || r == '+'
+}
+
+type token struct {
+	start, end int
+}
+
+// tokenize splits body into words at whitespace and punctuation. A word may
+// contain leetspeak symbols, but symbols at its edges are only kept as
+// alternatives: tokenize yields both "fornax!" and "fornax" for "fornax!".
+func tokenize(body string) [][]token {
+	words := [][]token{}
+	start := -1
+
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+
+		candidates := []token{{start, end}}
+		trimmedStart, trimmedEnd := start, end
+
+		for trimmedStart < trimmedEnd && isLeetSymbol(rune(body[trimmedStart])) {
+			trimmedStart++
+		}
+
+		for trimmedEnd > trimmedStart && isLeetSymbol(rune(body[trimmedEnd-1])) {
+			trimmedEnd--
+		}
+
+		if (trimmedStart != start || trimmedEnd != end) && trimmedStart < trimmedEnd {
+			candidates = append(candidates, token{trimmedStart, trimmedEnd})
+		}
+
+		words = append(words, candidates)
+		start = -1
+	}
+
+	for i, r := range body {
+		if isWordRune(r) {
+			if start < 0 {
+				start = i
+			}
+
+			continue
+		}
+
+		flush(i)
+	}
+
+	flush(len(body))
+
+	return words
+}
@@ -7,27 +7,40 @@ package database
 
 import (
 	"context"
+	"database/sql"
 )
 
 const createChirp = `-- name: CreateChirp :one
-INSERT INTO chirps (id, user_id, body, created_at, updated_at)
+INSERT INTO chirps (id, user_id, body, in_reply_to, quote_of, publish_at, created_at, updated_at)
 VALUES (
     gen_random_uuid(),
     $1,
     $2,
+    $3,
+    $4,
+    $5,
     NOW(),
     NOW()
 )
-RETURNING id, body, user_id, created_at, updated_at
+RETURNING id, body, user_id, created_at, updated_at, deleted_at, in_reply_to, rechirp_of, quote_of, like_count, publish_at, hidden_at
 `
 
 type CreateChirpParams struct {
-	UserID string
-	Body   string
+	UserID    string
+	Body      string
+	InReplyTo sql.NullString
+	QuoteOf   sql.NullString
+	PublishAt sql.NullTime
 }
 
 func (q *Queries) CreateChirp(ctx context.Context, arg CreateChirpParams) (Chirp, error) {
-	row := q.db.QueryRowContext(ctx, createChirp, arg.UserID, arg.Body)
+	row := q.db.QueryRowContext(ctx, createChirp,
+		arg.UserID,
+		arg.Body,
+		arg.InReplyTo,
+		arg.QuoteOf,
+		arg.PublishAt,
+	)
 	var i Chirp
 	err := row.Scan(
 		&i.ID,
@@ -35,6 +48,13 @@ func (q *Queries) CreateChirp(ctx context.Context, arg CreateChirpParams) (Chirp
 		&i.UserID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.InReplyTo,
+		&i.RechirpOf,
+		&i.QuoteOf,
+		&i.LikeCount,
+		&i.PublishAt,
+		&i.HiddenAt,
 	)
 	return i, err
 }
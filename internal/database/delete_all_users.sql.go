@@ -10,7 +10,19 @@ import (
 )
 
 const deleteAllUsers = `-- name: DeleteAllUsers :exec
-DELETE FROM users WHERE id IN (SELECT id FROM users) RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red
+WITH deleted_users AS (
+    UPDATE users
+    SET deleted_at = NOW(), updated_at = NOW()
+    WHERE deleted_at IS NULL
+    RETURNING id
+), revoked_tokens AS (
+    UPDATE refresh_tokens
+    SET revoked_at = NOW(), updated_at = NOW()
+    WHERE revoked_at IS NULL AND user_id IN (SELECT id FROM deleted_users)
+)
+UPDATE chirps
+SET deleted_at = NOW(), updated_at = NOW()
+WHERE deleted_at IS NULL AND user_id IN (SELECT id FROM deleted_users)
 `
 
 func (q *Queries) DeleteAllUsers(ctx context.Context) error {
@@ -10,7 +10,7 @@ import (
 )
 
 const getChirpByID = `-- name: GetChirpByID :one
-SELECT id, body, user_id, created_at, updated_at FROM chirps
+SELECT id, body, user_id, created_at, updated_at, deleted_at, in_reply_to, rechirp_of, quote_of, like_count, publish_at, hidden_at FROM chirps
 WHERE id = $1
 `
 
@@ -23,6 +23,13 @@ func (q *Queries) GetChirpByID(ctx context.Context, id string) (Chirp, error) {
 		&i.UserID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.InReplyTo,
+		&i.RechirpOf,
+		&i.QuoteOf,
+		&i.LikeCount,
+		&i.PublishAt,
+		&i.HiddenAt,
 	)
 	return i, err
 }
@@ -10,8 +10,8 @@ import (
 )
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, created_at, updated_at, email, hashed_password, is_chirpy_red FROM users
-WHERE id = $1
+SELECT id, created_at, updated_at, email, hashed_password, is_chirpy_red, deleted_at, is_admin, handle, display_name, bio, avatar_url, is_moderator, suspended_until, audience_updated_at FROM users
+WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetUserByID(ctx context.Context, id string) (User, error) {
@@ -24,6 +24,15 @@ func (q *Queries) GetUserByID(ctx context.Context, id string) (User, error) {
 		&i.Email,
 		&i.HashedPassword,
 		&i.IsChirpyRed,
+		&i.DeletedAt,
+		&i.IsAdmin,
+		&i.Handle,
+		&i.DisplayName,
+		&i.Bio,
+		&i.AvatarUrl,
+		&i.IsModerator,
+		&i.SuspendedUntil,
+		&i.AudienceUpdatedAt,
 	)
 	return i, err
 }
@@ -8,24 +8,35 @@ package database
 import (
 	"context"
 	"database/sql"
+
+	"github.com/lib/pq"
 )
 
 const listChirps = `-- name: ListChirps :many
-SELECT id, body, user_id, created_at, updated_at
+SELECT id, body, user_id, created_at, updated_at, deleted_at, in_reply_to, rechirp_of, quote_of, like_count, publish_at, hidden_at
 FROM chirps
-WHERE user_id = COALESCE($2, user_id)
+WHERE user_id = COALESCE($2, user_id) AND deleted_at IS NULL AND hidden_at IS NULL
+    AND NOT (user_id = ANY($3::text[]))
+    AND (publish_at IS NULL OR user_id = $4::text)
 ORDER BY
     CASE WHEN $1 THEN created_at END ASC,
     CASE WHEN $1 = FALSE THEN created_at END DESC
 `
 
 type ListChirpsParams struct {
-	Column1  interface{}
-	AuthorID sql.NullString
+	Column1         interface{}
+	AuthorID        sql.NullString
+	HiddenAuthorIds []string
+	ViewerID        string
 }
 
 func (q *Queries) ListChirps(ctx context.Context, arg ListChirpsParams) ([]Chirp, error) {
-	rows, err := q.db.QueryContext(ctx, listChirps, arg.Column1, arg.AuthorID)
+	rows, err := q.db.QueryContext(ctx, listChirps,
+		arg.Column1,
+		arg.AuthorID,
+		pq.Array(arg.HiddenAuthorIds),
+		arg.ViewerID,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -39,6 +50,13 @@ func (q *Queries) ListChirps(ctx context.Context, arg ListChirpsParams) ([]Chirp
 			&i.UserID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.InReplyTo,
+			&i.RechirpOf,
+			&i.QuoteOf,
+			&i.LikeCount,
+			&i.PublishAt,
+			&i.HiddenAt,
 		); err != nil {
 			return nil, err
 		}
@@ -6,15 +6,207 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 )
 
+type ActivityDelivery struct {
+	ID            string
+	UserID        string
+	InboxUrl      string
+	Activity      string
+	ObjectID      string
+	ObjectActor   string
+	Attempts      int32
+	NextAttemptAt time.Time
+	LastError     string
+	CreatedAt     time.Time
+}
+
+type ActorKey struct {
+	UserID        string
+	PublicKeyPem  string
+	PrivateKeyPem string
+	CreatedAt     time.Time
+}
+
+type Bookmark struct {
+	UserID    string
+	ChirpID   string
+	CreatedAt time.Time
+}
+
 type Chirp struct {
 	ID        string
 	Body      string
 	UserID    string
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	DeletedAt sql.NullTime
+	InReplyTo sql.NullString
+	RechirpOf sql.NullString
+	QuoteOf   sql.NullString
+	LikeCount int32
+	PublishAt sql.NullTime
+	HiddenAt  sql.NullTime
+}
+
+type ChirpEvent struct {
+	ID        int64
+	Type      string
+	ChirpID   string
+	AuthorID  string
+	CreatedAt time.Time
+}
+
+type ChirpFingerprint struct {
+	ChirpID   string
+	UserID    string
+	Hash      string
+	Simhash   int64
+	CreatedAt time.Time
+}
+
+type ChirpFlag struct {
+	ChirpID   string
+	Words     []string
+	CreatedAt time.Time
+	Reasons   []string
+}
+
+type ChirpHashtag struct {
+	ChirpID   string
+	Tag       string
+	CreatedAt time.Time
+}
+
+type ChirpLike struct {
+	ChirpID   string
+	UserID    string
+	CreatedAt time.Time
+}
+
+type ChirpMedium struct {
+	ChirpID  string
+	MediaID  string
+	Position int32
+}
+
+type ChirpMention struct {
+	ChirpID string
+	UserID  string
+}
+
+type ContentFilterRule struct {
+	ID        string
+	Pattern   string
+	Action    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type Draft struct {
+	ID           string
+	UserID       string
+	Body         string
+	InReplyTo    sql.NullString
+	QuoteOf      sql.NullString
+	MediaIds     []string
+	PublishAt    sql.NullTime
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	PollOptions  []string
+	PollClosesAt sql.NullTime
+}
+
+type Follow struct {
+	FollowerID string
+	FolloweeID string
+	CreatedAt  time.Time
+}
+
+type HashtagTrend struct {
+	Tag         string
+	Score       float64
+	Uses        int64
+	RefreshedAt time.Time
+}
+
+type IdempotencyKey struct {
+	Scope           string
+	Key             string
+	RequestHash     string
+	StatusCode      sql.NullInt32
+	ResponseBody    []byte
+	CreatedAt       time.Time
+	ExpiresAt       time.Time
+	ResponseHeaders json.RawMessage
+}
+
+type Medium struct {
+	ID                   string
+	UserID               string
+	ContentType          string
+	ByteSize             int64
+	Width                int32
+	Height               int32
+	StorageKey           string
+	ThumbnailKey         string
+	ThumbnailContentType string
+	CreatedAt            time.Time
+}
+
+type ModerationDecision struct {
+	ID          string
+	ModeratorID string
+	UserID      string
+	ChirpID     sql.NullString
+	Action      string
+	Note        string
+	CreatedAt   time.Time
+}
+
+type Notification struct {
+	ID        string
+	UserID    string
+	ActorID   sql.NullString
+	Type      string
+	ChirpID   sql.NullString
+	CreatedAt time.Time
+	ReadAt    sql.NullTime
+}
+
+type NotificationPreference struct {
+	UserID    string
+	Type      string
+	Enabled   bool
+	UpdatedAt time.Time
+}
+
+type Poll struct {
+	ChirpID  string
+	ClosesAt time.Time
+}
+
+type PollOption struct {
+	ChirpID   string
+	Position  int32
+	Label     string
+	VoteCount int32
+}
+
+type PollVote struct {
+	ChirpID   string
+	UserID    string
+	Position  int32
+	CreatedAt time.Time
+}
+
+type RateLimitBucket struct {
+	Key       string
+	Tokens    float64
+	Allowed   bool
+	UpdatedAt time.Time
 }
 
 type RefreshToken struct {
@@ -26,11 +218,67 @@ type RefreshToken struct {
 	UpdatedAt time.Time
 }
 
-type User struct {
-	ID             string
+type RemoteFollower struct {
+	UserID         string
+	ActorUrl       string
+	InboxUrl       string
+	SharedInboxUrl string
+	FollowID       string
 	CreatedAt      time.Time
-	UpdatedAt      time.Time
-	Email          string
-	HashedPassword string
-	IsChirpyRed    sql.NullBool
+}
+
+type Report struct {
+	ID         string
+	ReporterID string
+	UserID     string
+	ChirpID    sql.NullString
+	Reason     string
+	Details    string
+	DecisionID sql.NullString
+	CreatedAt  time.Time
+	ResolvedAt sql.NullTime
+}
+
+type ScheduledChirpFailure struct {
+	ChirpID       string
+	Attempts      int32
+	LastError     string
+	NextAttemptAt time.Time
+}
+
+type TimelineEntry struct {
+	UserID    string
+	ChirpID   string
+	AuthorID  string
+	CreatedAt time.Time
+}
+
+type User struct {
+	ID                string
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	Email             string
+	HashedPassword    string
+	IsChirpyRed       sql.NullBool
+	DeletedAt         sql.NullTime
+	IsAdmin           bool
+	Handle            sql.NullString
+	DisplayName       string
+	Bio               string
+	AvatarUrl         string
+	IsModerator       bool
+	SuspendedUntil    sql.NullTime
+	AudienceUpdatedAt time.Time
+}
+
+type UserBlock struct {
+	BlockerID string
+	BlockedID string
+	CreatedAt time.Time
+}
+
+type UserMute struct {
+	MuterID   string
+	MutedID   string
+	CreatedAt time.Time
 }
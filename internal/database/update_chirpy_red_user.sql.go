@@ -13,7 +13,7 @@ import (
 const updateChirpyRedUser = `-- name: UpdateChirpyRedUser :one
 UPDATE users SET is_chirpy_red = $1
 WHERE id = $2
-RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red
+RETURNING id, created_at, updated_at, email, hashed_password, is_chirpy_red, deleted_at, is_admin, handle, display_name, bio, avatar_url, is_moderator, suspended_until, audience_updated_at
 `
 
 type UpdateChirpyRedUserParams struct {
@@ -31,6 +31,15 @@ func (q *Queries) UpdateChirpyRedUser(ctx context.Context, arg UpdateChirpyRedUs
 		&i.Email,
 		&i.HashedPassword,
 		&i.IsChirpyRed,
+		&i.DeletedAt,
+		&i.IsAdmin,
+		&i.Handle,
+		&i.DisplayName,
+		&i.Bio,
+		&i.AvatarUrl,
+		&i.IsModerator,
+		&i.SuspendedUntil,
+		&i.AudienceUpdatedAt,
 	)
 	return i, err
 }
@@ -0,0 +1,165 @@
+package duplicates
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind says which recent chirps a new chirp duplicates.
+type Kind string
+
+const (
+	// KindAuthorExact is a repost of one of the author's own recent chirps.
+	KindAuthorExact Kind = "author_exact"
+	// KindAuthorNear is a lightly edited repost of the author's own chirp.
+	KindAuthorNear Kind = "author_near"
+	// KindGlobalExact is a body other accounts have recently posted too.
+	KindGlobalExact Kind = "global_exact"
+	// KindGlobalNear is a lightly edited copy of what other accounts posted.
+	KindGlobalNear Kind = "global_near"
+)
+
+var Kinds = []Kind{KindAuthorExact, KindAuthorNear, KindGlobalExact, KindGlobalNear}
+
+type Action string
+
+const (
+	ActionAllow  Action = "allow"
+	ActionFlag   Action = "flag"
+	ActionReject Action = "reject"
+)
+
+func severity(action Action) int {
+	switch action {
+	case ActionReject:
+		return 2
+	case ActionFlag:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Policy maps each kind of duplicate to what happens to the chirp. Kinds
+// that are missing are allowed.
+type Policy map[Kind]Action
+
+// ParsePolicy reads a policy written as "kind=action" pairs separated by
+// commas, for example "author_exact=reject,global_near=flag".
+func ParsePolicy(value string) (Policy, error) {
+	policy := Policy{}
+
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+
+		if len(pair) == 0 {
+			continue
+		}
+
+		kind, action, found := strings.Cut(pair, "=")
+		kind, action = strings.TrimSpace(kind), strings.TrimSpace(action)
+
+		if !found || !validKind(Kind(kind)) {
+			return nil, fmt.Errorf("unknown duplicate kind in %q", pair)
+		}
+
+		if severity(Action(action)) == 0 && Action(action) != ActionAllow {
+			return nil, fmt.Errorf("unknown duplicate action in %q", pair)
+		}
+
+		policy[Kind(kind)] = Action(action)
+	}
+
+	return policy, nil
+}
+
+func validKind(kind Kind) bool {
+	for _, known := range Kinds {
+		if kind == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Decide is the strictest action among the kinds a chirp matched.
+func (policy Policy) Decide(kinds []Kind) Action {
+	decision := ActionAllow
+
+	for _, kind := range kinds {
+		if action := policy[kind]; severity(action) > severity(decision) {
+			decision = action
+		}
+	}
+
+	return decision
+}
+
+// Candidate is a recent chirp a new one is compared against.
+type Candidate struct {
+	UserID  string
+	Hash    string
+	Simhash uint64
+	// Removed is set once the candidate's chirp has been deleted or hidden.
+	// It no longer counts: posting again what was taken down is not spam.
+	Removed bool
+}
+
+// Detector compares fingerprints against recent chirps.
+type Detector struct {
+	// MinTokens keeps short chirps such as "good morning" out of the check;
+	// everyone posts those.
+	MinTokens int
+	// MaxDistance is the largest simhash distance still counted as a near
+	// duplicate.
+	MaxDistance int
+	// GlobalMinAuthors is how many other accounts must have posted a body
+	// before it counts as a global duplicate.
+	GlobalMinAuthors int
+}
+
+// Check reports which kinds of duplicate fingerprint is, by authorID, given
+// the author's recent chirps and the recent chirps of everyone else.
+func (detector Detector) Check(authorID string, fingerprint Fingerprint, candidates []Candidate) []Kind {
+	kinds := []Kind{}
+
+	if fingerprint.Tokens < detector.MinTokens {
+		return kinds
+	}
+
+	matched := map[Kind]bool{}
+	exactAuthors := map[string]bool{}
+	nearAuthors := map[string]bool{}
+
+	for _, candidate := range candidates {
+		if candidate.Removed {
+			continue
+		}
+
+		exact := candidate.Hash == fingerprint.Hash
+		near := !exact && Distance(candidate.Simhash, fingerprint.Simhash) <= detector.MaxDistance
+
+		switch {
+		case candidate.UserID == authorID && exact:
+			matched[KindAuthorExact] = true
+		case candidate.UserID == authorID && near:
+			matched[KindAuthorNear] = true
+		case exact:
+			exactAuthors[candidate.UserID] = true
+		case near:
+			nearAuthors[candidate.UserID] = true
+		}
+	}
+
+	matched[KindGlobalExact] = len(exactAuthors) >= detector.GlobalMinAuthors
+	matched[KindGlobalNear] = len(nearAuthors) >= detector.GlobalMinAuthors
+
+	for _, kind := range Kinds {
+		if matched[kind] {
+			kinds = append(kinds, kind)
+		}
+	}
+
+	return kinds
+}
@@ -0,0 +1,132 @@
+package duplicates
+
+import "testing"
+
+func TestComputeIgnoresCosmeticChanges(t *testing.T) {
+	original := Compute("Buy cheap followers at example dot com today")
+
+	for _, body := range []string{
+		"buy cheap followers at example dot com today",
+		"BUY CHEAP FOLLOWERS... at example dot com TODAY!!!",
+		"Buy ch3ap f0llowers at examp1e dot com todaaaay",
+		"Buy  cheap\nfollowers at example dot com today",
+	} {
+		if got := Compute(body); got.Hash != original.Hash {
+			t.Errorf("Compute(%q) has a different hash", body)
+		}
+	}
+
+	if Compute("Buy cheap followers at example dot com tomorrow").Hash == original.Hash {
+		t.Error("a different word kept the same hash")
+	}
+}
+
+func TestSimhashKeepsNearDuplicatesClose(t *testing.T) {
+	original := Compute("the quick brown fox jumps over the lazy dog near the river bank today")
+	edited := Compute("the quick brown fox jumps over the lazy dog near the river bank tonight")
+	unrelated := Compute("scheduled maintenance for the database cluster starts at midnight utc")
+
+	if distance := Distance(original.Simhash, edited.Simhash); distance > 10 {
+		t.Errorf("edited chirp is %d bits away, want it close", distance)
+	}
+
+	if distance := Distance(original.Simhash, unrelated.Simhash); distance < 16 {
+		t.Errorf("unrelated chirp is only %d bits away", distance)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	detector := Detector{MinTokens: 3, MaxDistance: 3, GlobalMinAuthors: 2}
+	fingerprint := Compute("win a free phone now")
+	near := fingerprint.Simhash ^ 0b101
+
+	candidates := []Candidate{
+		{UserID: "author", Hash: fingerprint.Hash, Simhash: fingerprint.Simhash},
+		{UserID: "author", Hash: "other", Simhash: near},
+		{UserID: "bot-1", Hash: fingerprint.Hash, Simhash: fingerprint.Simhash},
+		{UserID: "bot-2", Hash: fingerprint.Hash, Simhash: fingerprint.Simhash},
+		{UserID: "bot-3", Hash: "other", Simhash: near},
+	}
+
+	kinds := detector.Check("author", fingerprint, candidates)
+	want := []Kind{KindAuthorExact, KindAuthorNear, KindGlobalExact}
+
+	if len(kinds) != len(want) {
+		t.Fatalf("Check() = %v, want %v", kinds, want)
+	}
+
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Fatalf("Check() = %v, want %v", kinds, want)
+		}
+	}
+
+	if short := detector.Check("author", Compute("gm"), candidates); len(short) != 0 {
+		t.Errorf("short chirp matched %v", short)
+	}
+}
+
+func TestCheckIgnoresRemovedChirps(t *testing.T) {
+	detector := Detector{MinTokens: 3, MaxDistance: 3, GlobalMinAuthors: 1}
+	fingerprint := Compute("win a free phone now")
+
+	// The author deleted the chirp and is posting it again; the other copy
+	// was hidden by a moderator.
+	candidates := []Candidate{
+		{UserID: "author", Hash: fingerprint.Hash, Simhash: fingerprint.Simhash, Removed: true},
+		{UserID: "bot-1", Hash: fingerprint.Hash, Simhash: fingerprint.Simhash, Removed: true},
+	}
+
+	if kinds := detector.Check("author", fingerprint, candidates); len(kinds) != 0 {
+		t.Fatalf("Check() = %v, want no duplicates", kinds)
+	}
+
+	candidates[0].Removed = false
+
+	if kinds := detector.Check("author", fingerprint, candidates); len(kinds) != 1 || kinds[0] != KindAuthorExact {
+		t.Fatalf("Check() = %v, want [%s]", kinds, KindAuthorExact)
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	policy, parseError := ParsePolicy("author_exact=reject, global_exact=flag,global_near=allow")
+
+	if parseError != nil {
+		t.Fatal(parseError)
+	}
+
+	cases := map[Action][]Kind{
+		ActionReject: {KindGlobalExact, KindAuthorExact},
+		ActionFlag:   {KindGlobalExact, KindGlobalNear},
+		ActionAllow:  {KindAuthorNear, KindGlobalNear},
+	}
+
+	for want, kinds := range cases {
+		if got := policy.Decide(kinds); got != want {
+			t.Errorf("Decide(%v) = %q, want %q", kinds, got, want)
+		}
+	}
+
+	for _, invalid := range []string{"author_exact", "somebody=reject", "author_exact=ban"} {
+		if _, parseError := ParsePolicy(invalid); parseError == nil {
+			t.Errorf("ParsePolicy(%q) succeeded", invalid)
+		}
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	metrics := &Metrics{}
+	metrics.Record([]Kind{}, ActionAllow)
+	metrics.Record([]Kind{KindAuthorExact, KindGlobalExact}, ActionReject)
+	metrics.Record([]Kind{KindGlobalNear}, ActionFlag)
+
+	snapshot := metrics.Snapshot()
+
+	if snapshot.Checked != 3 || snapshot.Rejected != 1 || snapshot.Flagged != 1 {
+		t.Errorf("Snapshot() = %+v", snapshot)
+	}
+
+	if snapshot.Matches[KindAuthorExact] != 1 || snapshot.Matches[KindGlobalNear] != 1 || snapshot.Matches[KindAuthorNear] != 0 {
+		t.Errorf("Snapshot().Matches = %v", snapshot.Matches)
+	}
+}
@@ -0,0 +1,95 @@
+package duplicates
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash/fnv"
+	"math/bits"
+	"strings"
+	"unicode"
+
+	"github.com/octaviocarpes/go-http-servers/internal/contentfilter"
+)
+
+// Fingerprint identifies a chirp body. Hash only matches bodies that are the
+// same once normalized; Simhash is close, in Hamming distance, for bodies
+// that share most of their words.
+type Fingerprint struct {
+	Hash    string
+	Simhash uint64
+	Tokens  int
+}
+
+// tokens splits body into words and normalizes each the way the content
+// filter does, so case, accents, leetspeak and stretched letters do not make
+// a repost look new.
+func tokens(body string) []string {
+	words := strings.FieldsFunc(body, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf)
+	})
+
+	normalized := make([]string, 0, len(words))
+
+	for _, word := range words {
+		if skeleton := contentfilter.Normalize(word); len(skeleton) > 0 {
+			normalized = append(normalized, skeleton)
+		}
+	}
+
+	return normalized
+}
+
+func Compute(body string) Fingerprint {
+	words := tokens(body)
+	sum := sha256.Sum256([]byte(strings.Join(words, " ")))
+
+	return Fingerprint{
+		Hash:    hex.EncodeToString(sum[:]),
+		Simhash: simhash(words),
+		Tokens:  len(words),
+	}
+}
+
+// simhash folds the hashes of every word and every pair of neighbouring
+// words into one 64-bit value. Pairs keep word order relevant without
+// letting one changed word move the result too far.
+func simhash(words []string) uint64 {
+	var weights [64]int
+
+	add := func(feature string) {
+		hasher := fnv.New64a()
+		hasher.Write([]byte(feature))
+		hash := hasher.Sum64()
+
+		for bit := range weights {
+			if hash&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	for i, word := range words {
+		add(word)
+
+		if i > 0 {
+			add(words[i-1] + " " + word)
+		}
+	}
+
+	var result uint64
+
+	for bit, weight := range weights {
+		if weight > 0 {
+			result |= 1 << bit
+		}
+	}
+
+	return result
+}
+
+// Distance is the number of bits in which two simhashes differ.
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
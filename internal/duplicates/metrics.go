@@ -0,0 +1,55 @@
+package duplicates
+
+import "sync/atomic"
+
+// Metrics counts what the detector has seen since the process started.
+type Metrics struct {
+	checked  atomic.Int64
+	rejected atomic.Int64
+	flagged  atomic.Int64
+	// matches has one counter per entry in Kinds.
+	matches [4]atomic.Int64
+}
+
+// Snapshot is a copy of Metrics at one point in time.
+type Snapshot struct {
+	Checked  int64          `json:"checked"`
+	Rejected int64          `json:"rejected"`
+	Flagged  int64          `json:"flagged"`
+	Matches  map[Kind]int64 `json:"matches"`
+}
+
+// Record counts one checked chirp, the kinds it matched and what was done.
+func (metrics *Metrics) Record(kinds []Kind, action Action) {
+	metrics.checked.Add(1)
+
+	for _, kind := range kinds {
+		for i, known := range Kinds {
+			if kind == known {
+				metrics.matches[i].Add(1)
+			}
+		}
+	}
+
+	switch action {
+	case ActionReject:
+		metrics.rejected.Add(1)
+	case ActionFlag:
+		metrics.flagged.Add(1)
+	}
+}
+
+func (metrics *Metrics) Snapshot() Snapshot {
+	snapshot := Snapshot{
+		Checked:  metrics.checked.Load(),
+		Rejected: metrics.rejected.Load(),
+		Flagged:  metrics.flagged.Load(),
+		Matches:  make(map[Kind]int64, len(Kinds)),
+	}
+
+	for i, kind := range Kinds {
+		snapshot.Matches[kind] = metrics.matches[i].Load()
+	}
+
+	return snapshot
+}
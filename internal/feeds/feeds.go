@@ -0,0 +1,190 @@
+// Package feeds renders syndication feeds as RSS 2.0 or Atom. Text is
+// escaped by encoding/xml, which also replaces characters XML cannot carry.
+package feeds
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const (
+	RSSContentType  = "application/rss+xml; charset=utf-8"
+	AtomContentType = "application/atom+xml; charset=utf-8"
+)
+
+// Feed is a format-neutral feed. ID and each Item's ID must never change for
+// the same feed and item, since readers use them to tell what is new.
+type Feed struct {
+	ID          string
+	Title       string
+	Description string
+	// Link is the page the feed is about and Self the feed's own URL.
+	Link    string
+	Self    string
+	Updated time.Time
+	Items   []Item
+}
+
+type Item struct {
+	ID        string
+	Title     string
+	Link      string
+	Author    string
+	Content   string
+	Published time.Time
+	Updated   time.Time
+}
+
+type rssLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Description string  `xml:"description"`
+	Creator     string  `xml:"dc:creator,omitempty"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Self          rssLink   `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	DCNS    string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+func rssDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC1123Z)
+}
+
+// RSS renders feed as RSS 2.0. Item IDs become GUIDs that are not permalinks.
+func RSS(feed Feed) ([]byte, error) {
+	document := rssDocument{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		DCNS:    "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:         feed.Title,
+			Link:          feed.Link,
+			Description:   feed.Description,
+			LastBuildDate: rssDate(feed.Updated),
+			Self:          rssLink{Href: feed.Self, Rel: "self", Type: "application/rss+xml"},
+			Items:         make([]rssItem, len(feed.Items)),
+		},
+	}
+
+	for i, item := range feed.Items {
+		document.Channel.Items[i] = rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Content,
+			Creator:     item.Author,
+			GUID:        rssGUID{Value: item.ID},
+			PubDate:     rssDate(item.Published),
+		}
+	}
+
+	return marshal(document)
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	ID        string     `xml:"id"`
+	Title     atomText   `xml:"title"`
+	Link      atomLink   `xml:"link"`
+	Author    atomPerson `xml:"author"`
+	Content   atomText   `xml:"content"`
+	Published string     `xml:"published"`
+	Updated   string     `xml:"updated"`
+}
+
+type atomDocument struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string      `xml:"id"`
+	Title    atomText    `xml:"title"`
+	Subtitle atomText    `xml:"subtitle"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+func atomDate(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Atom renders feed as Atom. Every entry carries its own author, which is
+// what lets the feed itself go without one.
+func Atom(feed Feed) ([]byte, error) {
+	document := atomDocument{
+		ID:       feed.ID,
+		Title:    atomText{Type: "text", Value: feed.Title},
+		Subtitle: atomText{Type: "text", Value: feed.Description},
+		Updated:  atomDate(feed.Updated),
+		Links: []atomLink{
+			{Href: feed.Self, Rel: "self", Type: "application/atom+xml"},
+			{Href: feed.Link, Rel: "alternate"},
+		},
+		Entries: make([]atomEntry, len(feed.Items)),
+	}
+
+	for i, item := range feed.Items {
+		document.Entries[i] = atomEntry{
+			ID:        item.ID,
+			Title:     atomText{Type: "text", Value: item.Title},
+			Link:      atomLink{Href: item.Link, Rel: "alternate"},
+			Author:    atomPerson{Name: item.Author},
+			Content:   atomText{Type: "text", Value: item.Content},
+			Published: atomDate(item.Published),
+			Updated:   atomDate(item.Updated),
+		}
+	}
+
+	return marshal(document)
+}
+
+func marshal(document any) ([]byte, error) {
+	body, marshalError := xml.MarshalIndent(document, "", "  ")
+
+	if marshalError != nil {
+		return nil, marshalError
+	}
+
+	return append([]byte(xml.Header), body...), nil
+}
@@ -0,0 +1,134 @@
+package feeds
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+var published = time.Date(2024, 3, 1, 12, 30, 0, 0, time.FixedZone("BRT", -3*60*60))
+
+func testFeed() Feed {
+	return Feed{
+		ID:          "urn:uuid:feed",
+		Title:       "Chirps by <alice> & friends",
+		Description: "Recent chirps",
+		Link:        "http://localhost:8080/api/chirps",
+		Self:        "http://localhost:8080/feeds/chirps.rss",
+		Updated:     published,
+		Items: []Item{{
+			ID:        "urn:uuid:chirp",
+			Title:     "<script>alert(1)</script>",
+			Link:      "http://localhost:8080/api/chirps/chirp?a=1&b=2",
+			Author:    "@alice",
+			Content:   "a & b < c\x00",
+			Published: published,
+			Updated:   published,
+		}},
+	}
+}
+
+func TestRSS(t *testing.T) {
+	body, renderError := RSS(testFeed())
+
+	if renderError != nil {
+		t.Fatalf("RSS: %v", renderError)
+	}
+
+	if strings.Contains(string(body), "<script>") || strings.Contains(string(body), "\x00") {
+		t.Fatalf("unescaped text in:\n%s", body)
+	}
+
+	var parsed struct {
+		Channel struct {
+			Title string `xml:"title"`
+			Items []struct {
+				Title       string `xml:"title"`
+				Link        string `xml:"link"`
+				Description string `xml:"description"`
+				GUID        struct {
+					IsPermaLink string `xml:"isPermaLink,attr"`
+					Value       string `xml:",chardata"`
+				} `xml:"guid"`
+				PubDate string `xml:"pubDate"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+
+	if parseError := xml.Unmarshal(body, &parsed); parseError != nil {
+		t.Fatalf("output is not XML: %v\n%s", parseError, body)
+	}
+
+	if parsed.Channel.Title != "Chirps by <alice> & friends" {
+		t.Errorf("title = %q", parsed.Channel.Title)
+	}
+
+	item := parsed.Channel.Items[0]
+
+	if item.Title != "<script>alert(1)</script>" || item.Link != "http://localhost:8080/api/chirps/chirp?a=1&b=2" {
+		t.Errorf("item = %+v", item)
+	}
+
+	if item.Description != "a & b < c�" {
+		t.Errorf("description = %q", item.Description)
+	}
+
+	if item.GUID.Value != "urn:uuid:chirp" || item.GUID.IsPermaLink != "false" {
+		t.Errorf("guid = %+v", item.GUID)
+	}
+
+	if item.PubDate != "Fri, 01 Mar 2024 15:30:00 +0000" {
+		t.Errorf("pubDate = %q", item.PubDate)
+	}
+}
+
+func TestAtom(t *testing.T) {
+	body, renderError := Atom(testFeed())
+
+	if renderError != nil {
+		t.Fatalf("Atom: %v", renderError)
+	}
+
+	if !strings.Contains(string(body), `<feed xmlns="http://www.w3.org/2005/Atom">`) {
+		t.Fatalf("missing Atom namespace in:\n%s", body)
+	}
+
+	var parsed struct {
+		ID      string `xml:"id"`
+		Updated string `xml:"updated"`
+		Links   []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Entries []struct {
+			ID        string `xml:"id"`
+			Title     string `xml:"title"`
+			Content   string `xml:"content"`
+			Author    string `xml:"author>name"`
+			Published string `xml:"published"`
+		} `xml:"entry"`
+	}
+
+	if parseError := xml.Unmarshal(body, &parsed); parseError != nil {
+		t.Fatalf("output is not XML: %v\n%s", parseError, body)
+	}
+
+	if parsed.ID != "urn:uuid:feed" || parsed.Updated != "2024-03-01T15:30:00Z" {
+		t.Errorf("feed = %+v", parsed)
+	}
+
+	if len(parsed.Links) != 2 || parsed.Links[0].Rel != "self" || parsed.Links[0].Href != "http://localhost:8080/feeds/chirps.rss" {
+		t.Errorf("links = %+v", parsed.Links)
+	}
+
+	entry := parsed.Entries[0]
+
+	if entry.ID != "urn:uuid:chirp" || entry.Title != "<script>alert(1)</script>" || entry.Author != "@alice" {
+		t.Errorf("entry = %+v", entry)
+	}
+
+	if entry.Content != "a & b < c�" || entry.Published != "2024-03-01T15:30:00Z" {
+		t.Errorf("entry = %+v", entry)
+	}
+}
@@ -0,0 +1,93 @@
+package media
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	// MaxFrames and MaxFramePixels bound an animated GIF. gif.DecodeAll
+	// allocates every frame up front, so the dimensions checked by Process
+	// say nothing about what a GIF with many frames costs.
+	MaxFrames      = 500
+	MaxFramePixels = 100_000_000
+
+	gifExtension       = 0x21
+	gifImageDescriptor = 0x2C
+	gifTrailer         = 0x3B
+	gifColorTableFlag  = 0x80
+)
+
+// checkGIFFrames walks the GIF block by block, skipping the compressed pixel
+// data, and rejects it once it holds too many frames or too many pixels
+// across them. Structural damage is left for the decoder to report.
+func checkGIFFrames(data []byte) error {
+	// Header and logical screen descriptor.
+	if len(data) < 13 {
+		return nil
+	}
+
+	offset := 13
+
+	if data[10]&gifColorTableFlag != 0 {
+		offset += 3 << (data[10]&0x07 + 1)
+	}
+
+	frames, pixels := 0, 0
+
+	for offset < len(data) {
+		switch data[offset] {
+		case gifExtension:
+			offset = skipSubBlocks(data, offset+2)
+		case gifImageDescriptor:
+			if offset+10 > len(data) {
+				return nil
+			}
+
+			width := int(binary.LittleEndian.Uint16(data[offset+5:]))
+			height := int(binary.LittleEndian.Uint16(data[offset+7:]))
+			flags := data[offset+9]
+
+			frames++
+			pixels += width * height
+
+			if frames > MaxFrames {
+				return fmt.Errorf("%w: more than %d frames", ErrTooManyPixels, MaxFrames)
+			}
+
+			if pixels > MaxFramePixels {
+				return fmt.Errorf("%w: frames hold more than %d pixels", ErrTooManyPixels, MaxFramePixels)
+			}
+
+			offset += 10
+
+			if flags&gifColorTableFlag != 0 {
+				offset += 3 << (flags&0x07 + 1)
+			}
+
+			// LZW minimum code size, then the image data.
+			offset = skipSubBlocks(data, offset+1)
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// skipSubBlocks returns the offset just past the chain of length-prefixed
+// sub-blocks starting at offset.
+func skipSubBlocks(data []byte, offset int) int {
+	for offset < len(data) {
+		size := int(data[offset])
+		offset++
+
+		if size == 0 {
+			break
+		}
+
+		offset += size
+	}
+
+	return offset
+}
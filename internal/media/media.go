@@ -0,0 +1,186 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+const (
+	// MaxPixels bounds width*height so a tiny, highly compressed file cannot
+	// expand into gigabytes once decoded.
+	MaxPixels = 40_000_000
+
+	ThumbnailSize = 320
+	jpegQuality   = 90
+)
+
+var (
+	ErrUnsupportedType = errors.New("unsupported media type")
+	ErrTooManyPixels   = errors.New("image dimensions are too large")
+)
+
+type Image struct {
+	Data        []byte
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// Processed is an upload after it went through Process: the original is
+// re-encoded without any metadata and the thumbnail fits in a
+// ThumbnailSize square.
+type Processed struct {
+	Original  Image
+	Thumbnail Image
+}
+
+// Process sniffs the uploaded bytes, rejects anything that is not a JPEG, PNG
+// or GIF, and re-encodes the image. Re-encoding drops EXIF, XMP, comments and
+// every other ancillary chunk, so GPS tags and camera serials never reach
+// storage. JPEG orientation is applied to the pixels before it is dropped.
+func Process(data []byte) (Processed, error) {
+	contentType := http.DetectContentType(data)
+
+	if contentType != "image/jpeg" && contentType != "image/png" && contentType != "image/gif" {
+		return Processed{}, ErrUnsupportedType
+	}
+
+	config, _, configError := image.DecodeConfig(bytes.NewReader(data))
+
+	if configError != nil {
+		return Processed{}, ErrUnsupportedType
+	}
+
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > MaxPixels {
+		return Processed{}, ErrTooManyPixels
+	}
+
+	switch contentType {
+	case "image/jpeg":
+		return processJPEG(data)
+	case "image/png":
+		return processPNG(data)
+	default:
+		return processGIF(data)
+	}
+}
+
+func processJPEG(data []byte) (Processed, error) {
+	decoded, decodeError := jpeg.Decode(bytes.NewReader(data))
+
+	if decodeError != nil {
+		return Processed{}, ErrUnsupportedType
+	}
+
+	oriented := orient(decoded, jpegOrientation(data))
+
+	original, encodeError := encodeJPEG(oriented)
+
+	if encodeError != nil {
+		return Processed{}, encodeError
+	}
+
+	thumbnail, thumbnailError := encodeJPEG(thumbnail(oriented))
+
+	if thumbnailError != nil {
+		return Processed{}, thumbnailError
+	}
+
+	return Processed{Original: original, Thumbnail: thumbnail}, nil
+}
+
+func processPNG(data []byte) (Processed, error) {
+	decoded, decodeError := png.Decode(bytes.NewReader(data))
+
+	if decodeError != nil {
+		return Processed{}, ErrUnsupportedType
+	}
+
+	original, encodeError := encodePNG(decoded)
+
+	if encodeError != nil {
+		return Processed{}, encodeError
+	}
+
+	thumbnail, thumbnailError := encodePNG(thumbnail(decoded))
+
+	if thumbnailError != nil {
+		return Processed{}, thumbnailError
+	}
+
+	return Processed{Original: original, Thumbnail: thumbnail}, nil
+}
+
+// processGIF keeps every frame so animations survive, within MaxFrames and
+// MaxFramePixels. The thumbnail is the first frame.
+func processGIF(data []byte) (Processed, error) {
+	if framesError := checkGIFFrames(data); framesError != nil {
+		return Processed{}, framesError
+	}
+
+	decoded, decodeError := gif.DecodeAll(bytes.NewReader(data))
+
+	if decodeError != nil || len(decoded.Image) == 0 {
+		return Processed{}, ErrUnsupportedType
+	}
+
+	var encoded bytes.Buffer
+
+	if encodeError := gif.EncodeAll(&encoded, decoded); encodeError != nil {
+		return Processed{}, encodeError
+	}
+
+	original := Image{
+		Data:        encoded.Bytes(),
+		ContentType: "image/gif",
+		Width:       decoded.Config.Width,
+		Height:      decoded.Config.Height,
+	}
+
+	firstFrame := image.NewRGBA(image.Rect(0, 0, decoded.Config.Width, decoded.Config.Height))
+	draw.Draw(firstFrame, decoded.Image[0].Bounds(), decoded.Image[0], decoded.Image[0].Bounds().Min, draw.Over)
+
+	thumbnail, thumbnailError := encodePNG(thumbnail(firstFrame))
+
+	if thumbnailError != nil {
+		return Processed{}, thumbnailError
+	}
+
+	return Processed{Original: original, Thumbnail: thumbnail}, nil
+}
+
+func encodeJPEG(img image.Image) (Image, error) {
+	var encoded bytes.Buffer
+
+	if encodeError := jpeg.Encode(&encoded, img, &jpeg.Options{Quality: jpegQuality}); encodeError != nil {
+		return Image{}, encodeError
+	}
+
+	return Image{
+		Data:        encoded.Bytes(),
+		ContentType: "image/jpeg",
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+	}, nil
+}
+
+func encodePNG(img image.Image) (Image, error) {
+	var encoded bytes.Buffer
+
+	if encodeError := png.Encode(&encoded, img); encodeError != nil {
+		return Image{}, encodeError
+	}
+
+	return Image{
+		Data:        encoded.Bytes(),
+		ContentType: "image/png",
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+	}, nil
+}
@@ -0,0 +1,205 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func solid(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+
+	return img
+}
+
+// withExif splices an APP1 segment holding a big-endian TIFF header with a
+// single orientation entry right after the JPEG SOI marker.
+func withExif(jpegData []byte, orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = append(tiff, 0x00, 0x01)
+	entry := make([]byte, 12)
+	binary.BigEndian.PutUint16(entry[0:], orientationTag)
+	binary.BigEndian.PutUint16(entry[2:], 3)
+	binary.BigEndian.PutUint32(entry[4:], 1)
+	binary.BigEndian.PutUint16(entry[8:], orientation)
+	tiff = append(tiff, entry...)
+	tiff = append(tiff, 0, 0, 0, 0)
+	tiff = append(tiff, []byte("GPS secret location")...)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	result := append([]byte{}, jpegData[:2]...)
+	result = append(result, segment...)
+	return append(result, jpegData[2:]...)
+}
+
+func TestProcessJPEGStripsExifAndAppliesOrientation(t *testing.T) {
+	var encoded bytes.Buffer
+	jpeg.Encode(&encoded, solid(40, 20), nil)
+
+	data := withExif(encoded.Bytes(), 6)
+
+	if got := jpegOrientation(data); got != 6 {
+		t.Fatalf("jpegOrientation = %d, want 6", got)
+	}
+
+	processed, err := Process(data)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Contains(processed.Original.Data, []byte("Exif")) || bytes.Contains(processed.Original.Data, []byte("GPS secret")) {
+		t.Fatal("processed JPEG still carries EXIF data")
+	}
+
+	if processed.Original.Width != 20 || processed.Original.Height != 40 {
+		t.Fatalf("dimensions = %dx%d, want 20x40 after rotation", processed.Original.Width, processed.Original.Height)
+	}
+
+	if processed.Original.ContentType != "image/jpeg" {
+		t.Fatalf("content type = %q", processed.Original.ContentType)
+	}
+}
+
+func TestProcessPNGThumbnail(t *testing.T) {
+	var encoded bytes.Buffer
+	png.Encode(&encoded, solid(1000, 500))
+
+	processed, err := Process(encoded.Bytes())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if processed.Thumbnail.Width != ThumbnailSize || processed.Thumbnail.Height != ThumbnailSize/2 {
+		t.Fatalf("thumbnail = %dx%d, want %dx%d", processed.Thumbnail.Width, processed.Thumbnail.Height, ThumbnailSize, ThumbnailSize/2)
+	}
+
+	if _, err := png.Decode(bytes.NewReader(processed.Thumbnail.Data)); err != nil {
+		t.Fatalf("thumbnail is not a valid PNG: %v", err)
+	}
+}
+
+func TestProcessGIFKeepsFrames(t *testing.T) {
+	palette := color.Palette{color.Black, color.White}
+	animation := &gif.GIF{
+		Image: []*image.Paletted{
+			image.NewPaletted(image.Rect(0, 0, 10, 10), palette),
+			image.NewPaletted(image.Rect(0, 0, 10, 10), palette),
+		},
+		Delay: []int{10, 10},
+	}
+
+	var encoded bytes.Buffer
+	gif.EncodeAll(&encoded, animation)
+
+	processed, err := Process(encoded.Bytes())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := gif.DecodeAll(bytes.NewReader(processed.Original.Data))
+
+	if err != nil || len(decoded.Image) != 2 {
+		t.Fatalf("processed GIF lost its frames: %v", err)
+	}
+}
+
+func TestProcessRejectsOtherTypes(t *testing.T) {
+	for _, data := range [][]byte{
+		[]byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"),
+		[]byte("%PDF-1.4"),
+		{0xFF, 0xD8, 0xFF, 0xE0, 0x00},
+	} {
+		if _, err := Process(data); !errors.Is(err, ErrUnsupportedType) {
+			t.Errorf("Process(%q) error = %v, want ErrUnsupportedType", data, err)
+		}
+	}
+}
+
+func TestProcessRejectsHugeDimensions(t *testing.T) {
+	var encoded bytes.Buffer
+	png.Encode(&encoded, image.NewGray(image.Rect(0, 0, 1, 1)))
+
+	data := encoded.Bytes()
+	// Patch the IHDR dimensions and checksum; DecodeConfig only reads the
+	// header, so the missing pixel data never matters.
+	binary.BigEndian.PutUint32(data[16:], 100_000)
+	binary.BigEndian.PutUint32(data[20:], 100_000)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+
+	if _, err := Process(data); !errors.Is(err, ErrTooManyPixels) {
+		t.Fatalf("Process error = %v, want ErrTooManyPixels", err)
+	}
+}
+
+func TestProcessRejectsGIFsWithTooManyFrames(t *testing.T) {
+	palette := color.Palette{color.Black, color.White}
+	animation := &gif.GIF{}
+
+	for range MaxFrames + 1 {
+		animation.Image = append(animation.Image, image.NewPaletted(image.Rect(0, 0, 1, 1), palette))
+		animation.Delay = append(animation.Delay, 0)
+	}
+
+	var encoded bytes.Buffer
+	gif.EncodeAll(&encoded, animation)
+
+	if _, err := Process(encoded.Bytes()); !errors.Is(err, ErrTooManyPixels) {
+		t.Fatalf("Process error = %v, want ErrTooManyPixels", err)
+	}
+}
+
+func TestProcessRejectsGIFsWithHugeFrames(t *testing.T) {
+	var encoded bytes.Buffer
+	gif.Encode(&encoded, image.NewPaletted(image.Rect(0, 0, 1, 1), color.Palette{color.Black}), nil)
+
+	data := encoded.Bytes()
+	// Patch the frame's own dimensions, leaving the logical screen at 1x1 so
+	// that only the frame check can catch it.
+	descriptor := bytes.Index(data, []byte{0x2C, 0, 0, 0, 0})
+	binary.LittleEndian.PutUint16(data[descriptor+5:], 10_001)
+	binary.LittleEndian.PutUint16(data[descriptor+7:], 10_000)
+
+	if _, err := Process(data); !errors.Is(err, ErrTooManyPixels) {
+		t.Fatalf("Process error = %v, want ErrTooManyPixels", err)
+	}
+}
+
+func TestOrient(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	src.Set(0, 0, color.RGBA{R: 255, A: 255})
+
+	cases := map[int]image.Point{
+		2: {1, 0},
+		3: {1, 0},
+		6: {0, 0},
+		8: {0, 1},
+	}
+
+	for orientation, want := range cases {
+		dst := orient(src, orientation)
+
+		if r, _, _, _ := dst.At(want.X, want.Y).RGBA(); r != 0xFFFF {
+			t.Errorf("orientation %d: red pixel not at %v", orientation, want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package media
+
+import (
+	"image"
+	"image/draw"
+)
+
+// thumbnail scales img down to fit in a ThumbnailSize square, keeping its
+// aspect ratio. Each output pixel is the average of the source pixels it
+// covers, which avoids the aliasing of nearest-neighbour sampling.
+func thumbnail(img image.Image) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width <= ThumbnailSize && height <= ThumbnailSize {
+		return img
+	}
+
+	dstWidth, dstHeight := ThumbnailSize, ThumbnailSize
+
+	if width > height {
+		dstHeight = max(1, height*ThumbnailSize/width)
+	} else {
+		dstWidth = max(1, width*ThumbnailSize/height)
+	}
+
+	src := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+
+	for y := 0; y < dstHeight; y++ {
+		y0 := y * height / dstHeight
+		y1 := max(y0+1, (y+1)*height/dstHeight)
+
+		for x := 0; x < dstWidth; x++ {
+			x0 := x * width / dstWidth
+			x1 := max(x0+1, (x+1)*width/dstWidth)
+
+			var r, g, b, a, count uint64
+
+			for sy := y0; sy < y1; sy++ {
+				offset := src.PixOffset(x0, sy)
+
+				for sx := x0; sx < x1; sx++ {
+					r += uint64(src.Pix[offset])
+					g += uint64(src.Pix[offset+1])
+					b += uint64(src.Pix[offset+2])
+					a += uint64(src.Pix[offset+3])
+					count++
+					offset += 4
+				}
+			}
+
+			offset := dst.PixOffset(x, y)
+			dst.Pix[offset] = uint8(r / count)
+			dst.Pix[offset+1] = uint8(g / count)
+			dst.Pix[offset+2] = uint8(b / count)
+			dst.Pix[offset+3] = uint8(a / count)
+		}
+	}
+
+	return dst
+}
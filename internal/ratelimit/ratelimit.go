@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Policy allows Limit requests per Window. Tokens refill continuously, so a
+// client that has been idle for a whole window can spend Limit at once.
+type Policy struct {
+	Limit  int
+	Window time.Duration
+}
+
+// RefillRate is how many tokens the bucket regains per second.
+func (policy Policy) RefillRate() float64 {
+	return float64(policy.Limit) / policy.Window.Seconds()
+}
+
+// Decision is the outcome of taking a token from a bucket, with Tokens being
+// what the bucket holds afterwards.
+type Decision struct {
+	Policy  Policy
+	Allowed bool
+	Tokens  float64
+}
+
+// Remaining is how many more requests would be allowed right now.
+func (decision Decision) Remaining() int {
+	return max(0, int(math.Floor(decision.Tokens)))
+}
+
+// RetryAfter is how long until the bucket holds a whole token again.
+func (decision Decision) RetryAfter() time.Duration {
+	return decision.until(1)
+}
+
+// Reset is how long until the bucket is full again.
+func (decision Decision) Reset() time.Duration {
+	return decision.until(float64(decision.Policy.Limit))
+}
+
+func (decision Decision) until(tokens float64) time.Duration {
+	missing := tokens - decision.Tokens
+
+	if missing <= 0 {
+		return 0
+	}
+
+	return time.Duration(missing / decision.Policy.RefillRate() * float64(time.Second))
+}
+
+// WriteHeaders sets the RateLimit-* headers from the IETF draft, plus
+// Retry-After when the request was rejected. Durations are rounded up to
+// whole seconds so that a client waiting that long is never rejected again
+// for the same reason.
+func (decision Decision) WriteHeaders(header http.Header) {
+	header.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", decision.Policy.Limit, seconds(decision.Policy.Window)))
+	header.Set("RateLimit-Limit", strconv.Itoa(decision.Policy.Limit))
+	header.Set("RateLimit-Remaining", strconv.Itoa(decision.Remaining()))
+	header.Set("RateLimit-Reset", strconv.Itoa(seconds(decision.Reset())))
+
+	if !decision.Allowed {
+		header.Set("Retry-After", strconv.Itoa(max(1, seconds(decision.RetryAfter()))))
+	}
+}
+
+func seconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDecisionDurations(t *testing.T) {
+	policy := Policy{Limit: 10, Window: 10 * time.Minute}
+
+	if rate := policy.RefillRate(); rate != 1.0/60 {
+		t.Fatalf("RefillRate() = %v, want one token a minute", rate)
+	}
+
+	decision := Decision{Policy: policy, Allowed: false, Tokens: 0.5}
+
+	if got := decision.RetryAfter(); got != 30*time.Second {
+		t.Errorf("RetryAfter() = %v, want 30s", got)
+	}
+
+	if got := decision.Reset(); got != 9*time.Minute+30*time.Second {
+		t.Errorf("Reset() = %v, want 9m30s", got)
+	}
+
+	if got := decision.Remaining(); got != 0 {
+		t.Errorf("Remaining() = %d, want 0", got)
+	}
+
+	full := Decision{Policy: policy, Allowed: true, Tokens: 10}
+
+	if full.RetryAfter() != 0 || full.Reset() != 0 || full.Remaining() != 10 {
+		t.Errorf("full bucket = %v %v %d, want 0 0 10", full.RetryAfter(), full.Reset(), full.Remaining())
+	}
+}
+
+func TestWriteHeaders(t *testing.T) {
+	policy := Policy{Limit: 30, Window: time.Hour}
+
+	allowed := http.Header{}
+	Decision{Policy: policy, Allowed: true, Tokens: 28.2}.WriteHeaders(allowed)
+
+	want := map[string]string{
+		"RateLimit-Policy":    "30;w=3600",
+		"RateLimit-Limit":     "30",
+		"RateLimit-Remaining": "28",
+		"RateLimit-Reset":     "216",
+		"Retry-After":         "",
+	}
+
+	for name, value := range want {
+		if got := allowed.Get(name); got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+
+	rejected := http.Header{}
+	Decision{Policy: policy, Allowed: false, Tokens: 0.999999}.WriteHeaders(rejected)
+
+	if got := rejected.Get("Retry-After"); got != "1" {
+		t.Errorf("Retry-After = %q, want at least one second", got)
+	}
+
+	if got := rejected.Get("RateLimit-Remaining"); got != "0" {
+		t.Errorf("RateLimit-Remaining = %q, want 0", got)
+	}
+}
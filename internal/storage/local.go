@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Local stores objects as files below a root directory.
+type Local struct {
+	root string
+}
+
+func NewLocal(root string) (*Local, error) {
+	if mkdirError := os.MkdirAll(root, 0o755); mkdirError != nil {
+		return nil, mkdirError
+	}
+
+	return &Local{root: root}, nil
+}
+
+func (local *Local) path(key string) (string, error) {
+	cleaned := filepath.Clean(filepath.FromSlash(key))
+
+	if len(key) == 0 || filepath.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+
+	return filepath.Join(local.root, cleaned), nil
+}
+
+// Put writes to a temporary file first so readers never see a partial object.
+func (local *Local) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	path, pathError := local.path(key)
+
+	if pathError != nil {
+		return pathError
+	}
+
+	if mkdirError := os.MkdirAll(filepath.Dir(path), 0o755); mkdirError != nil {
+		return mkdirError
+	}
+
+	file, createError := os.CreateTemp(filepath.Dir(path), ".upload-*")
+
+	if createError != nil {
+		return createError
+	}
+
+	defer os.Remove(file.Name())
+
+	if _, writeError := file.Write(data); writeError != nil {
+		file.Close()
+		return writeError
+	}
+
+	if closeError := file.Close(); closeError != nil {
+		return closeError
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+func (local *Local) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, pathError := local.path(key)
+
+	if pathError != nil {
+		return nil, pathError
+	}
+
+	file, openError := os.Open(path)
+
+	if errors.Is(openError, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+
+	return file, openError
+}
+
+func (local *Local) Delete(ctx context.Context, key string) error {
+	path, pathError := local.path(key)
+
+	if pathError != nil {
+		return pathError
+	}
+
+	if removeError := os.Remove(path); removeError != nil && !errors.Is(removeError, fs.ErrNotExist) {
+		return removeError
+	}
+
+	return nil
+}
@@ -0,0 +1,234 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3 talks to any S3-compatible object store (AWS, MinIO, R2, ...) using
+// path-style URLs and Signature Version 4, so it needs no SDK.
+type S3 struct {
+	Endpoint        string
+	Bucket          string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	Client          *http.Client
+}
+
+func NewS3(endpoint, bucket, region, accessKeyID, secretAccessKey string) *S3 {
+	return &S3{
+		Endpoint:        strings.TrimSuffix(endpoint, "/"),
+		Bucket:          bucket,
+		Region:          region,
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+		Client:          &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (store *S3) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	req, requestError := store.newRequest(ctx, http.MethodPut, key, data)
+
+	if requestError != nil {
+		return requestError
+	}
+
+	req.Header.Set("Content-Type", contentType)
+
+	res, doError := store.do(req, data)
+
+	if doError != nil {
+		return doError
+	}
+
+	return res.Body.Close()
+}
+
+func (store *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, requestError := store.newRequest(ctx, http.MethodGet, key, nil)
+
+	if requestError != nil {
+		return nil, requestError
+	}
+
+	res, doError := store.do(req, nil)
+
+	if doError != nil {
+		return nil, doError
+	}
+
+	return res.Body, nil
+}
+
+func (store *S3) Delete(ctx context.Context, key string) error {
+	req, requestError := store.newRequest(ctx, http.MethodDelete, key, nil)
+
+	if requestError != nil {
+		return requestError
+	}
+
+	res, doError := store.do(req, nil)
+
+	if doError == ErrNotFound {
+		return nil
+	}
+
+	if doError != nil {
+		return doError
+	}
+
+	return res.Body.Close()
+}
+
+func (store *S3) newRequest(ctx context.Context, method, key string, data []byte) (*http.Request, error) {
+	target := store.Endpoint + "/" + uriEncode(store.Bucket, false) + "/" + uriEncode(key, false)
+
+	return http.NewRequestWithContext(ctx, method, target, bytes.NewReader(data))
+}
+
+func (store *S3) do(req *http.Request, data []byte) (*http.Response, error) {
+	signRequest(req, data, store.AccessKeyID, store.SecretAccessKey, store.Region, time.Now())
+
+	client := store.Client
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, doError := client.Do(req)
+
+	if doError != nil {
+		return nil, doError
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		res.Body.Close()
+		return nil, ErrNotFound
+	}
+
+	if res.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		res.Body.Close()
+		return nil, fmt.Errorf("s3 %s %s: %s: %s", req.Method, req.URL.Path, res.Status, bytes.TrimSpace(message))
+	}
+
+	return res, nil
+}
+
+// signRequest adds the headers for AWS Signature Version 4. Every header
+// already on the request is signed along with host and the x-amz-* headers.
+func signRequest(req *http.Request, payload []byte, accessKeyID, secretAccessKey, region string, now time.Time) {
+	payloadHash := sha256.Sum256(payload)
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+
+	headers := map[string]string{"host": req.URL.Host}
+
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+
+	names := make([]string, 0, len(headers))
+
+	for name := range headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uriEncode(req.URL.Path, false),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalHash[:])
+
+	signingKey := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+
+	for key := range query {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	pairs := []string{}
+
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes everything except the unreserved characters, as
+// SigV4 requires. Slashes are kept unless encodeSlash is set.
+func uriEncode(value string, encodeSlash bool) string {
+	var encoded strings.Builder
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			encoded.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			encoded.WriteByte(c)
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", c)
+		}
+	}
+
+	return encoded.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
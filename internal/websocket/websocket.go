@@ -0,0 +1,368 @@
+// Package websocket is the server side of RFC 6455: the opening handshake
+// and message framing, with pings answered and fragmented messages
+// reassembled. Extensions and subprotocols are not supported.
+package websocket
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// MessageType is a frame opcode.
+type MessageType byte
+
+const (
+	continuationFrame MessageType = 0x0
+	TextMessage       MessageType = 0x1
+	BinaryMessage     MessageType = 0x2
+	CloseMessage      MessageType = 0x8
+	PingMessage       MessageType = 0x9
+	PongMessage       MessageType = 0xA
+)
+
+func (messageType MessageType) control() bool {
+	return messageType >= CloseMessage
+}
+
+// Close codes used by the server.
+const (
+	CloseNormal          = 1000
+	CloseGoingAway       = 1001
+	CloseProtocolError   = 1002
+	CloseUnsupportedData = 1003
+	ClosePolicyViolation = 1008
+	CloseMessageTooBig   = 1009
+	CloseTryAgainLater   = 1013
+)
+
+const maxControlPayload = 125
+
+var (
+	ErrBadHandshake = errors.New("not a websocket handshake")
+	ErrReadLimit    = errors.New("message exceeds the read limit")
+)
+
+// CloseError is returned by ReadMessage once the peer has closed the
+// connection.
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (closeError *CloseError) Error() string {
+	return fmt.Sprintf("websocket closed: %d %s", closeError.Code, closeError.Reason)
+}
+
+type protocolError struct {
+	message string
+}
+
+func (protocolError *protocolError) Error() string {
+	return "websocket protocol error: " + protocolError.message
+}
+
+// Conn is an upgraded connection. ReadMessage must only be called from one
+// goroutine; writes may come from any number of them.
+type Conn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	writeMu sync.Mutex
+	closed  bool
+
+	readLimit   int64
+	pongHandler func()
+}
+
+// AcceptKey is the Sec-WebSocket-Accept value for a client's
+// Sec-WebSocket-Key.
+func AcceptKey(key string) string {
+	hash := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+func headerContains(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Upgrade completes the handshake for req and takes over its connection.
+// When req is not a valid handshake it responds with an error itself and
+// returns ErrBadHandshake.
+func Upgrade(responseWriter http.ResponseWriter, req *http.Request) (*Conn, error) {
+	key := req.Header.Get("Sec-WebSocket-Key")
+
+	if req.Method != http.MethodGet ||
+		!headerContains(req.Header, "Connection", "upgrade") ||
+		!headerContains(req.Header, "Upgrade", "websocket") ||
+		len(key) == 0 {
+		http.Error(responseWriter, "websocket handshake expected", http.StatusBadRequest)
+		return nil, ErrBadHandshake
+	}
+
+	if req.Header.Get("Sec-WebSocket-Version") != "13" {
+		responseWriter.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(responseWriter, "unsupported websocket version", http.StatusUpgradeRequired)
+		return nil, ErrBadHandshake
+	}
+
+	conn, readWriter, hijackError := http.NewResponseController(responseWriter).Hijack()
+
+	if hijackError != nil {
+		http.Error(responseWriter, "websocket upgrade is not supported here", http.StatusInternalServerError)
+		return nil, hijackError
+	}
+
+	// The server's deadlines no longer apply once the connection is ours.
+	conn.SetDeadline(time.Time{})
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + AcceptKey(key) + "\r\n\r\n"
+
+	if _, writeError := conn.Write([]byte(response)); writeError != nil {
+		conn.Close()
+		return nil, writeError
+	}
+
+	return &Conn{conn: conn, reader: readWriter.Reader, readLimit: 1 << 16}, nil
+}
+
+// SetReadLimit caps the size of a message, after reassembly. Larger messages
+// close the connection.
+func (conn *Conn) SetReadLimit(limit int64) {
+	conn.readLimit = limit
+}
+
+// SetPongHandler is called from ReadMessage for every pong received.
+func (conn *Conn) SetPongHandler(handler func()) {
+	conn.pongHandler = handler
+}
+
+func (conn *Conn) SetReadDeadline(deadline time.Time) error {
+	return conn.conn.SetReadDeadline(deadline)
+}
+
+// WriteMessage sends data as one frame and gives up once deadline passes. A
+// zero deadline waits forever.
+func (conn *Conn) WriteMessage(messageType MessageType, data []byte, deadline time.Time) error {
+	if messageType.control() && len(data) > maxControlPayload {
+		return &protocolError{"control frame payload too long"}
+	}
+
+	conn.writeMu.Lock()
+	defer conn.writeMu.Unlock()
+
+	if conn.closed {
+		return net.ErrClosed
+	}
+
+	conn.conn.SetWriteDeadline(deadline)
+
+	header := []byte{0x80 | byte(messageType), 0}
+
+	switch length := len(data); {
+	case length <= 125:
+		header[1] = byte(length)
+	case length <= 0xFFFF:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(length))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(length))
+	}
+
+	if _, writeError := conn.conn.Write(append(header, data...)); writeError != nil {
+		return writeError
+	}
+
+	if messageType == CloseMessage {
+		conn.closed = true
+	}
+
+	return nil
+}
+
+func closePayload(code int, reason string) []byte {
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+	payload = append(payload, reason...)
+
+	if len(payload) > maxControlPayload {
+		payload = payload[:maxControlPayload]
+	}
+
+	return payload
+}
+
+// Close sends a close frame with code and reason, then closes the
+// connection without waiting for the peer's reply.
+func (conn *Conn) Close(code int, reason string) error {
+	conn.WriteMessage(CloseMessage, closePayload(code, reason), time.Now().Add(time.Second))
+	return conn.conn.Close()
+}
+
+type frame struct {
+	fin         bool
+	messageType MessageType
+	payload     []byte
+}
+
+func (conn *Conn) readFrame(remaining int64) (frame, error) {
+	var header [2]byte
+
+	if _, readError := io.ReadFull(conn.reader, header[:]); readError != nil {
+		return frame{}, readError
+	}
+
+	current := frame{fin: header[0]&0x80 != 0, messageType: MessageType(header[0] & 0x0F)}
+
+	if header[0]&0x70 != 0 {
+		return frame{}, &protocolError{"reserved bits set"}
+	}
+
+	// Clients must mask everything they send.
+	if header[1]&0x80 == 0 {
+		return frame{}, &protocolError{"unmasked client frame"}
+	}
+
+	length := uint64(header[1] & 0x7F)
+
+	switch length {
+	case 126:
+		var extended [2]byte
+
+		if _, readError := io.ReadFull(conn.reader, extended[:]); readError != nil {
+			return frame{}, readError
+		}
+
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+
+		if _, readError := io.ReadFull(conn.reader, extended[:]); readError != nil {
+			return frame{}, readError
+		}
+
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+
+	if current.messageType.control() {
+		if !current.fin || length > maxControlPayload {
+			return frame{}, &protocolError{"invalid control frame"}
+		}
+	} else if length > uint64(remaining) {
+		return frame{}, ErrReadLimit
+	}
+
+	var mask [4]byte
+
+	if _, readError := io.ReadFull(conn.reader, mask[:]); readError != nil {
+		return frame{}, readError
+	}
+
+	current.payload = make([]byte, length)
+
+	if _, readError := io.ReadFull(conn.reader, current.payload); readError != nil {
+		return frame{}, readError
+	}
+
+	for i := range current.payload {
+		current.payload[i] ^= mask[i%4]
+	}
+
+	return current, nil
+}
+
+// ReadMessage returns the next text or binary message. Pings are answered
+// and pongs handed to the pong handler along the way. When the peer closes
+// the connection the close is acknowledged and a *CloseError returned; on
+// protocol violations the connection is closed with the matching code.
+func (conn *Conn) ReadMessage() (MessageType, []byte, error) {
+	var messageType MessageType
+	var message []byte
+
+	for {
+		current, readError := conn.readFrame(conn.readLimit - int64(len(message)))
+
+		if readError != nil {
+			var protocolViolation *protocolError
+
+			switch {
+			case errors.As(readError, &protocolViolation):
+				conn.Close(CloseProtocolError, protocolViolation.message)
+			case errors.Is(readError, ErrReadLimit):
+				conn.Close(CloseMessageTooBig, "message too big")
+			}
+
+			return 0, nil, readError
+		}
+
+		switch current.messageType {
+		case PingMessage:
+			if pongError := conn.WriteMessage(PongMessage, current.payload, time.Now().Add(time.Second)); pongError != nil {
+				return 0, nil, pongError
+			}
+
+			continue
+		case PongMessage:
+			if conn.pongHandler != nil {
+				conn.pongHandler()
+			}
+
+			continue
+		case CloseMessage:
+			closeError := &CloseError{Code: 1005}
+
+			if len(current.payload) >= 2 {
+				closeError.Code = int(binary.BigEndian.Uint16(current.payload))
+				closeError.Reason = string(current.payload[2:])
+			}
+
+			conn.WriteMessage(CloseMessage, current.payload[:min(len(current.payload), 2)], time.Now().Add(time.Second))
+			conn.conn.Close()
+
+			return 0, nil, closeError
+		case TextMessage, BinaryMessage:
+			if messageType != 0 {
+				conn.Close(CloseProtocolError, "expected a continuation frame")
+				return 0, nil, &protocolError{"expected a continuation frame"}
+			}
+
+			messageType = current.messageType
+		case continuationFrame:
+			if messageType == 0 {
+				conn.Close(CloseProtocolError, "unexpected continuation frame")
+				return 0, nil, &protocolError{"unexpected continuation frame"}
+			}
+		default:
+			conn.Close(CloseProtocolError, "unknown opcode")
+			return 0, nil, &protocolError{"unknown opcode"}
+		}
+
+		message = append(message, current.payload...)
+
+		if current.fin {
+			return messageType, message, nil
+		}
+	}
+}
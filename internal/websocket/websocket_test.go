@@ -0,0 +1,251 @@
+package websocket
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAcceptKey(t *testing.T) {
+	// The example from RFC 6455, section 1.3.
+	if got := AcceptKey("dGhlIHNhbXBsZSBub25jZQ=="); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("AcceptKey = %q", got)
+	}
+}
+
+// echoServer upgrades every request and sends each message back.
+func echoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, req *http.Request) {
+		conn, upgradeError := Upgrade(responseWriter, req)
+
+		if upgradeError != nil {
+			return
+		}
+
+		conn.SetReadLimit(1024)
+
+		for {
+			messageType, message, readError := conn.ReadMessage()
+
+			if readError != nil {
+				return
+			}
+
+			if writeError := conn.WriteMessage(messageType, message, time.Time{}); writeError != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+type testClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func dial(t *testing.T, server *httptest.Server) *testClient {
+	t.Helper()
+
+	conn, dialError := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+
+	if dialError != nil {
+		t.Fatalf("dial: %v", dialError)
+	}
+
+	t.Cleanup(func() { conn.Close() })
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	io.WriteString(conn, "GET / HTTP/1.1\r\n"+
+		"Host: example.com\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: keep-alive, Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n"+
+		"Sec-WebSocket-Version: 13\r\n\r\n")
+
+	reader := bufio.NewReader(conn)
+	response, readError := http.ReadResponse(reader, nil)
+
+	if readError != nil {
+		t.Fatalf("reading handshake response: %v", readError)
+	}
+
+	if response.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake status = %d", response.StatusCode)
+	}
+
+	if accept := response.Header.Get("Sec-WebSocket-Accept"); accept != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("Sec-WebSocket-Accept = %q", accept)
+	}
+
+	return &testClient{conn: conn, reader: reader}
+}
+
+func (client *testClient) send(t *testing.T, fin bool, opcode MessageType, payload []byte, masked bool) {
+	t.Helper()
+
+	first := byte(opcode)
+
+	if fin {
+		first |= 0x80
+	}
+
+	frame := []byte{first, byte(len(payload))}
+	mask := []byte{1, 2, 3, 4}
+
+	if masked {
+		frame[1] |= 0x80
+		frame = append(frame, mask...)
+	}
+
+	for i, b := range payload {
+		if masked {
+			b ^= mask[i%4]
+		}
+
+		frame = append(frame, b)
+	}
+
+	if _, writeError := client.conn.Write(frame); writeError != nil {
+		t.Fatalf("write: %v", writeError)
+	}
+}
+
+func (client *testClient) receive(t *testing.T) (MessageType, []byte) {
+	t.Helper()
+
+	var header [2]byte
+
+	if _, readError := io.ReadFull(client.reader, header[:]); readError != nil {
+		t.Fatalf("read: %v", readError)
+	}
+
+	if header[1]&0x80 != 0 {
+		t.Fatal("server frames must not be masked")
+	}
+
+	length := int(header[1])
+
+	if length == 126 {
+		var extended [2]byte
+		io.ReadFull(client.reader, extended[:])
+		length = int(binary.BigEndian.Uint16(extended[:]))
+	}
+
+	payload := make([]byte, length)
+
+	if _, readError := io.ReadFull(client.reader, payload); readError != nil {
+		t.Fatalf("read: %v", readError)
+	}
+
+	return MessageType(header[0] & 0x0F), payload
+}
+
+func TestEchoReassemblesFragments(t *testing.T) {
+	client := dial(t, echoServer(t))
+
+	client.send(t, false, TextMessage, []byte("hello, "), true)
+	client.send(t, true, PingMessage, []byte("in between"), true)
+	client.send(t, true, continuationFrame, []byte("world"), true)
+
+	messageType, payload := client.receive(t)
+
+	if messageType != PongMessage || string(payload) != "in between" {
+		t.Fatalf("got %v %q, want the pong first", messageType, payload)
+	}
+
+	messageType, payload = client.receive(t)
+
+	if messageType != TextMessage || string(payload) != "hello, world" {
+		t.Fatalf("got %v %q", messageType, payload)
+	}
+}
+
+func TestLongMessage(t *testing.T) {
+	client := dial(t, echoServer(t))
+	message := strings.Repeat("a", 300)
+
+	// Built by hand to use the 16-bit length form.
+	frame := []byte{0x80 | byte(BinaryMessage), 0x80 | 126, 0x01, 0x2C, 0, 0, 0, 0}
+	client.conn.Write(append(frame, message...))
+
+	messageType, payload := client.receive(t)
+
+	if messageType != BinaryMessage || string(payload) != message {
+		t.Fatalf("got %v with %d bytes", messageType, len(payload))
+	}
+}
+
+func closeCode(t *testing.T, client *testClient) int {
+	t.Helper()
+
+	messageType, payload := client.receive(t)
+
+	if messageType != CloseMessage || len(payload) < 2 {
+		t.Fatalf("got %v %q, want a close frame", messageType, payload)
+	}
+
+	return int(binary.BigEndian.Uint16(payload))
+}
+
+func TestUnmaskedFrameIsAProtocolError(t *testing.T) {
+	client := dial(t, echoServer(t))
+	client.send(t, true, TextMessage, []byte("hi"), false)
+
+	if code := closeCode(t, client); code != CloseProtocolError {
+		t.Fatalf("close code = %d", code)
+	}
+}
+
+func TestReadLimit(t *testing.T) {
+	client := dial(t, echoServer(t))
+
+	client.send(t, false, TextMessage, []byte(strings.Repeat("x", 120)), true)
+
+	// The limit applies to the whole message, not to each fragment.
+	for i := 0; i < 9; i++ {
+		client.send(t, false, continuationFrame, []byte(strings.Repeat("x", 120)), true)
+	}
+
+	if code := closeCode(t, client); code != CloseMessageTooBig {
+		t.Fatalf("close code = %d", code)
+	}
+}
+
+func TestCloseHandshake(t *testing.T) {
+	client := dial(t, echoServer(t))
+	client.send(t, true, CloseMessage, binary.BigEndian.AppendUint16(nil, CloseGoingAway), true)
+
+	if code := closeCode(t, client); code != CloseGoingAway {
+		t.Fatalf("close code = %d", code)
+	}
+
+	if _, readError := client.reader.ReadByte(); !errors.Is(readError, io.EOF) {
+		t.Fatalf("connection still open: %v", readError)
+	}
+}
+
+func TestUpgradeRejectsPlainRequests(t *testing.T) {
+	response, getError := http.Get(echoServer(t).URL)
+
+	if getError != nil {
+		t.Fatalf("get: %v", getError)
+	}
+
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusBadRequest {
+		t.Fatalf("status = %d", response.StatusCode)
+	}
+}
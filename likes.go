@@ -0,0 +1,162 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/octaviocarpes/go-http-servers/internal/database"
+	server "github.com/octaviocarpes/go-http-servers/server"
+)
+
+func (config *apiConfig) likeChirp(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	chirp, shareError := config.shareableChirp(req.Context(), req.PathValue("id"))
+
+	if shareError != nil {
+		sendShareableChirpError(shareError, responseWriter)
+		return
+	}
+
+	if blockedError := checkNotBlocked(req.Context(), config.db, userUUID.String(), chirp.UserID); blockedError != nil {
+		sendBlockedError(blockedError, responseWriter)
+		return
+	}
+
+	// Liking twice is a no-op: the insert and the counter bump happen in one
+	// statement, so the counter only moves when a like row was created.
+	liked, likeError := config.db.LikeChirp(req.Context(), database.LikeChirpParams{
+		ChirpID: chirp.ID,
+		UserID:  userUUID.String(),
+	})
+
+	if likeError != nil {
+		server.SendInternalServerError(likeError, responseWriter)
+		return
+	}
+
+	if liked > 0 {
+		if notifyError := notify(req.Context(), config.db, notificationLike, chirp.UserID, userUUID.String(), chirp.ID); notifyError != nil {
+			log.Printf("failed to notify like: %v\n", notifyError)
+		}
+	}
+
+	responseWriter.WriteHeader(http.StatusNoContent)
+}
+
+func (config *apiConfig) unlikeChirp(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	chirpID := req.PathValue("id")
+
+	if chirp, getChirpError := config.db.GetChirpByID(req.Context(), chirpID); getChirpError == nil && chirp.RechirpOf.Valid {
+		chirpID = chirp.RechirpOf.String
+	}
+
+	_, unlikeError := config.db.UnlikeChirp(req.Context(), database.UnlikeChirpParams{
+		ChirpID: chirpID,
+		UserID:  userUUID.String(),
+	})
+
+	if unlikeError != nil {
+		server.SendInternalServerError(unlikeError, responseWriter)
+		return
+	}
+
+	responseWriter.WriteHeader(http.StatusNoContent)
+}
+
+func (config *apiConfig) listChirpLikers(responseWriter http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+
+	limit, limitError := server.ParseLimit(query, 50, 200)
+
+	if limitError != nil {
+		server.SendError(limitError.Error(), http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	beforeCreatedAt, beforeUserID, cursorError := server.ParseTimeCursor(query)
+
+	if cursorError != nil {
+		server.SendError(cursorError.Error(), http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	chirp, getChirpError := config.db.GetChirpByID(req.Context(), req.PathValue("id"))
+
+	if getChirpError != nil {
+		server.SendError("chirp not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	if chirp.DeletedAt.Valid {
+		config.sendChirpTombstone(chirp, responseWriter)
+		return
+	}
+
+	viewer, audienceError := config.requestAudience(req)
+
+	if audienceError != nil {
+		server.SendInternalServerError(audienceError, responseWriter)
+		return
+	}
+
+	if !viewer.canSeeChirp(chirp) || (chirp.HiddenAt.Valid && !viewer.moderator) {
+		server.SendError("chirp not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	likers, listLikersError := config.db.ListChirpLikers(req.Context(), database.ListChirpLikersParams{
+		ChirpID:         chirp.ID,
+		BeforeCreatedAt: beforeCreatedAt,
+		BeforeUserID:    beforeUserID,
+		PageSize:        limit + 1,
+	})
+
+	if listLikersError != nil {
+		server.SendInternalServerError(listLikersError, responseWriter)
+		return
+	}
+
+	nextCursor := ""
+
+	if len(likers) > int(limit) {
+		likers = likers[:limit]
+		last := likers[len(likers)-1]
+		nextCursor = server.EncodeTimeCursor(last.CreatedAt, last.UserID)
+	}
+
+	type likerResponse struct {
+		UserID  string    `json:"user_id"`
+		LikedAt time.Time `json:"liked_at"`
+	}
+
+	type likersResponse struct {
+		Likers     []likerResponse `json:"likers"`
+		NextCursor string          `json:"next_cursor,omitempty"`
+	}
+
+	response := likersResponse{
+		Likers:     make([]likerResponse, len(likers)),
+		NextCursor: nextCursor,
+	}
+
+	for i, liker := range likers {
+		response.Likers[i] = likerResponse{
+			UserID:  liker.UserID,
+			LikedAt: liker.CreatedAt,
+		}
+	}
+
+	server.ResponseWithJson(response, http.StatusOK, responseWriter)
+}
@@ -1,30 +1,133 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+	"github.com/octaviocarpes/go-http-servers/internal/activitypub"
 	auth "github.com/octaviocarpes/go-http-servers/internal/auth"
+	"github.com/octaviocarpes/go-http-servers/internal/broadcast"
+	"github.com/octaviocarpes/go-http-servers/internal/contentfilter"
 	"github.com/octaviocarpes/go-http-servers/internal/database"
+	"github.com/octaviocarpes/go-http-servers/internal/duplicates"
+	"github.com/octaviocarpes/go-http-servers/internal/ratelimit"
+	"github.com/octaviocarpes/go-http-servers/internal/storage"
 	server "github.com/octaviocarpes/go-http-servers/server"
 	utils "github.com/octaviocarpes/go-http-servers/utils"
 )
 
 type apiConfig struct {
-	fileserverHits atomic.Int32
-	db             *database.Queries
-	secret         string
-	polkaKey       string
+	fileserverHits        atomic.Int32
+	conn                  *sql.DB
+	db                    *database.Queries
+	secret                string
+	polkaKey              string
+	retention             time.Duration
+	trendsWindow          time.Duration
+	trendsHalfLife        time.Duration
+	media                 storage.Storage
+	mediaMaxBytes         int64
+	chirpLimit            int
+	chirpyRedChirpLimit   int
+	contentFilter         *contentfilter.Holder
+	chirpRate             ratelimit.Policy
+	chirpyRedChirpRate    ratelimit.Policy
+	duplicateDetector     duplicates.Detector
+	duplicatePolicy       duplicates.Policy
+	duplicateAuthorWindow time.Duration
+	duplicateGlobalWindow time.Duration
+	duplicateMetrics      *duplicates.Metrics
+	idempotencyKeyTTL     time.Duration
+	chirpEvents           *broadcast.Hub
+	streamReplaySize      int
+	chirpEventRetention   time.Duration
+	streamHeartbeat       time.Duration
+	socketPingInterval    time.Duration
+	publicURL             string
+	federation            *activitypub.Client
+	activityMaxAttempts   int
+	inboxRate             ratelimit.Policy
+}
+
+func (config *apiConfig) authenticate(responseWriter http.ResponseWriter, req *http.Request) (uuid.UUID, bool) {
+	token, getTokenErr := auth.GetBearerToken(req.Header)
+
+	if getTokenErr != nil {
+		server.SendError("Unauthorized", http.StatusUnauthorized, responseWriter)
+		return uuid.UUID{}, false
+	}
+
+	userUUID, invalidTokenError := auth.ValidateJWT(token, config.secret)
+
+	if invalidTokenError != nil {
+		server.SendError("Unauthorized", http.StatusUnauthorized, responseWriter)
+		return uuid.UUID{}, false
+	}
+
+	// Access tokens outlive their users being deleted.
+	active, isActiveError := config.db.IsActiveUser(req.Context(), userUUID.String())
+
+	if isActiveError != nil {
+		server.SendInternalServerError(isActiveError, responseWriter)
+		return uuid.UUID{}, false
+	}
+
+	if !active {
+		server.SendError("Unauthorized", http.StatusUnauthorized, responseWriter)
+		return uuid.UUID{}, false
+	}
+
+	return userUUID, true
+}
+
+// authenticateAdmin is authenticate for endpoints that only admins may use.
+func (config *apiConfig) authenticateAdmin(responseWriter http.ResponseWriter, req *http.Request) (uuid.UUID, bool) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return uuid.UUID{}, false
+	}
+
+	user, getUserError := config.db.GetUserByID(req.Context(), userUUID.String())
+
+	if getUserError != nil || !user.IsAdmin {
+		server.SendError("Forbidden", http.StatusForbidden, responseWriter)
+		return uuid.UUID{}, false
+	}
+
+	return userUUID, true
+}
+
+// authenticateModerator is authenticate for the moderation queue, which
+// moderators and admins may use.
+func (config *apiConfig) authenticateModerator(responseWriter http.ResponseWriter, req *http.Request) (uuid.UUID, bool) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return uuid.UUID{}, false
+	}
+
+	user, getUserError := config.db.GetUserByID(req.Context(), userUUID.String())
+
+	if getUserError != nil || !(user.IsAdmin || user.IsModerator) {
+		server.SendError("Forbidden", http.StatusForbidden, responseWriter)
+		return uuid.UUID{}, false
+	}
+
+	return userUUID, true
 }
 
 func (config *apiConfig) middlewareMetricsInc(next http.Handler) http.Handler {
@@ -82,8 +185,9 @@ func (config *apiConfig) resetMetricsHandler(responseWriter http.ResponseWriter,
 
 func (config *apiConfig) createUser(responseWriter http.ResponseWriter, req *http.Request) {
 	type createUserBody struct {
-		Email    string `json:"email"`
-		Password string `json:"password"`
+		Email    string  `json:"email"`
+		Password string  `json:"password"`
+		Handle   *string `json:"handle"`
 	}
 
 	decoder := json.NewDecoder(req.Body)
@@ -96,6 +200,17 @@ func (config *apiConfig) createUser(responseWriter http.ResponseWriter, req *htt
 		return
 	}
 
+	var handle sql.NullString
+
+	if payload.Handle != nil {
+		if !utils.IsValidHandle(*payload.Handle) {
+			server.SendError("handle must be 3 to 15 letters, digits or underscores", http.StatusBadRequest, responseWriter)
+			return
+		}
+
+		handle = sql.NullString{String: *payload.Handle, Valid: true}
+	}
+
 	hashedPassword, hashError := auth.HashPassword(payload.Password)
 
 	if hashError != nil {
@@ -106,8 +221,14 @@ func (config *apiConfig) createUser(responseWriter http.ResponseWriter, req *htt
 	user, createUserError := config.db.CreateUser(req.Context(), database.CreateUserParams{
 		Email:          payload.Email,
 		HashedPassword: hashedPassword,
+		Handle:         handle,
 	})
 
+	if isUniqueViolation(createUserError) {
+		server.SendError("email or handle is already taken", http.StatusConflict, responseWriter)
+		return
+	}
+
 	if createUserError != nil {
 		server.SendInternalServerError(createUserError, responseWriter)
 		return
@@ -118,6 +239,7 @@ func (config *apiConfig) createUser(responseWriter http.ResponseWriter, req *htt
 		CreatedAt   time.Time `json:"created_at"`
 		UpdatedAt   time.Time `json:"updated_at"`
 		Email       string    `json:"email"`
+		Handle      *string   `json:"handle"`
 		IsChirpyRed bool      `json:"is_chirpy_red"`
 	}
 
@@ -129,6 +251,10 @@ func (config *apiConfig) createUser(responseWriter http.ResponseWriter, req *htt
 		IsChirpyRed: user.IsChirpyRed.Bool,
 	}
 
+	if user.Handle.Valid {
+		response.Handle = &user.Handle.String
+	}
+
 	server.ResponseWithJson(response, http.StatusCreated, responseWriter)
 }
 
@@ -151,76 +277,64 @@ func (config *apiConfig) createChirp(responseWriter http.ResponseWriter, req *ht
 		return
 	}
 
-	type createChirpBody struct {
-		Body string `json:"body"`
+	if !config.limitChirps(responseWriter, req, userUUID.String()) {
+		return
 	}
 
-	const chirpSizeLimit = 140
-
-	decodedPayload, decodeError := server.DecodeBody[createChirpBody](req.Body)
+	decodedPayload, decodeError := server.DecodeBody[chirpInput](req.Body)
 
 	if decodeError != nil {
 		server.SendInternalServerError(decodeError, responseWriter)
 		return
 	}
 
-	if len(decodedPayload.Body) > chirpSizeLimit {
-		responsePayload := struct {
-			Error string `json:"error"`
-		}{
-			Error: "Chirp is too long",
-		}
+	prepared, prepareError := config.prepareChirp(req.Context(), userUUID.String(), decodedPayload)
 
-		server.ResponseWithJson(responsePayload, http.StatusBadRequest, responseWriter)
+	if prepareError != nil {
+		sendChirpInputError(prepareError, responseWriter)
 		return
 	}
 
-	responsePhrase := strings.Split(decodedPayload.Body, " ")
-	lowerCasePhrase := strings.Split(strings.ToLower(decodedPayload.Body), " ")
-
-	for i := 0; i < len(responsePhrase); i++ {
-		word := lowerCasePhrase[i]
+	chirp, createChirpError := config.insertChirp(req.Context(), prepared)
 
-		if utils.IsProfaneWord(word) {
-			responsePhrase[i] = "****"
-		}
-	}
-
-	cleanedBody := strings.Join(responsePhrase, " ")
-
-	payload := database.CreateChirpParams{
-		Body:   cleanedBody,
-		UserID: userUUID.String(),
+	if errors.Is(createChirpError, errMediaUnavailable) {
+		sendChirpMediaError(createChirpError, responseWriter)
+		return
 	}
 
-	chirp, createChirpError := config.db.CreateChirp(req.Context(), payload)
-
 	if createChirpError != nil {
 		server.SendInternalServerError(createChirpError, responseWriter)
 		return
 	}
 
-	responsePayload := struct {
-		Id        string    `json:"id"`
-		Body      string    `json:"body"`
-		UserId    string    `json:"user_id"`
-		CreatedAt time.Time `json:"created_at"`
-		UpdatedAt time.Time `json:"updated_at"`
-	}{
-		Id:        chirp.ID,
-		Body:      chirp.Body,
-		UserId:    chirp.UserID,
-		CreatedAt: chirp.CreatedAt,
-		UpdatedAt: chirp.UpdatedAt,
+	config.recordDuplicates(prepared)
+
+	response, buildResponseError := config.buildChirpResponse(req.Context(), audience{viewerID: userUUID.String()}, chirp)
+
+	if buildResponseError != nil {
+		server.SendInternalServerError(buildResponseError, responseWriter)
+		return
 	}
 
-	server.ResponseWithJson(responsePayload, http.StatusCreated, responseWriter)
+	server.ResponseWithJson(response, http.StatusCreated, responseWriter)
 }
 
 func (config *apiConfig) listChirps(responseWriter http.ResponseWriter, req *http.Request) {
 	authorID := req.URL.Query().Get("author_id")
+	handle := req.URL.Query().Get("handle")
 	sort := req.URL.Query().Get("sort")
 
+	if len(handle) > 0 {
+		author, getAuthorError := config.db.GetUserByHandle(req.Context(), handle)
+
+		if getAuthorError != nil || (len(authorID) > 0 && authorID != author.ID) {
+			server.SendError("user not found", http.StatusNotFound, responseWriter)
+			return
+		}
+
+		authorID = author.ID
+	}
+
 	var authorParam sql.NullString
 	sortParam := true
 
@@ -246,9 +360,18 @@ func (config *apiConfig) listChirps(responseWriter http.ResponseWriter, req *htt
 		sortParam = false
 	}
 
+	viewer, audienceError := config.requestAudience(req)
+
+	if audienceError != nil {
+		server.SendInternalServerError(audienceError, responseWriter)
+		return
+	}
+
 	chirps, listChirpsError := config.db.ListChirps(req.Context(), database.ListChirpsParams{
-		AuthorID: authorParam,
-		Column1:  sortParam,
+		AuthorID:        authorParam,
+		Column1:         sortParam,
+		HiddenAuthorIds: viewer.feedHidden(),
+		ViewerID:        viewer.viewerID,
 	})
 
 	if listChirpsError != nil {
@@ -256,27 +379,34 @@ func (config *apiConfig) listChirps(responseWriter http.ResponseWriter, req *htt
 		return
 	}
 
-	type chirpResponse struct {
-		ID        string    `json:"id"`
-		Body      string    `json:"body"`
-		UserID    string    `json:"user_id"`
-		CreatedAt time.Time `json:"created_at"`
-		UpdatedAt time.Time `json:"updated_at"`
+	response, buildResponseError := config.buildChirpResponses(req.Context(), viewer, chirps)
+
+	if buildResponseError != nil {
+		server.SendInternalServerError(buildResponseError, responseWriter)
+		return
+	}
+
+	// A chirp leaving the list moves its own updated_at, not that of any chirp
+	// still in it, so the latest one is taken over all of the author's
+	// chirps. What a viewer gets also depends on their blocks and mutes.
+	listLastModified, lastModifiedError := config.db.GetChirpsLastModified(req.Context(), authorParam)
+
+	if lastModifiedError != nil {
+		server.SendInternalServerError(lastModifiedError, responseWriter)
+		return
 	}
 
-	response := make([]chirpResponse, len(chirps))
+	audienceUpdatedAt, audienceUpdatedError := config.audienceUpdatedAt(req.Context(), viewer)
 
-	for i, chirp := range chirps {
-		response[i] = chirpResponse{
-			ID:        chirp.ID,
-			Body:      chirp.Body,
-			UserID:    chirp.UserID,
-			CreatedAt: chirp.CreatedAt,
-			UpdatedAt: chirp.UpdatedAt,
-		}
+	if audienceUpdatedError != nil {
+		server.SendInternalServerError(audienceUpdatedError, responseWriter)
+		return
 	}
 
-	server.ResponseWithJson(response, http.StatusOK, responseWriter)
+	lastModified := latest(chirpsLastModified(response), listLastModified.Time, audienceUpdatedAt)
+
+	responseWriter.Header().Set("Vary", "Authorization")
+	server.ResponseWithConditionalJson(response, lastModified, req, responseWriter)
 }
 
 func (config *apiConfig) getChirpById(responseWriter http.ResponseWriter, req *http.Request) {
@@ -290,23 +420,41 @@ func (config *apiConfig) getChirpById(responseWriter http.ResponseWriter, req *h
 		return
 	}
 
-	type chirpResponse struct {
-		ID        string    `json:"id"`
-		Body      string    `json:"body"`
-		UserID    string    `json:"user_id"`
-		CreatedAt time.Time `json:"created_at"`
-		UpdatedAt time.Time `json:"updated_at"`
+	if chirp.DeletedAt.Valid {
+		config.sendChirpTombstone(chirp, responseWriter)
+		return
 	}
 
-	response := chirpResponse{
-		ID:        chirp.ID,
-		Body:      chirp.Body,
-		UserID:    chirp.UserID,
-		CreatedAt: chirp.CreatedAt,
-		UpdatedAt: chirp.UpdatedAt,
+	viewer, audienceError := config.requestAudience(req)
+
+	if audienceError != nil {
+		server.SendInternalServerError(audienceError, responseWriter)
+		return
 	}
 
-	server.ResponseWithJson(response, http.StatusOK, responseWriter)
+	if !viewer.canSeeChirp(chirp) {
+		server.SendError("chirp not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	response, buildResponseError := config.buildChirpResponse(req.Context(), viewer, chirp)
+
+	if buildResponseError != nil {
+		server.SendInternalServerError(buildResponseError, responseWriter)
+		return
+	}
+
+	audienceUpdatedAt, audienceUpdatedError := config.audienceUpdatedAt(req.Context(), viewer)
+
+	if audienceUpdatedError != nil {
+		server.SendInternalServerError(audienceUpdatedError, responseWriter)
+		return
+	}
+
+	lastModified := latest(chirpsLastModified([]chirpResponse{response}), audienceUpdatedAt)
+
+	responseWriter.Header().Set("Vary", "Authorization")
+	server.ResponseWithConditionalJson(response, lastModified, req, responseWriter)
 }
 
 func (config *apiConfig) login(responseWriter http.ResponseWriter, req *http.Request) {
@@ -429,6 +577,20 @@ func (config *apiConfig) refreshSession(responseWriter http.ResponseWriter, req
 		return
 	}
 
+	active, isActiveError := config.db.IsActiveUser(req.Context(), dbToken.UserID)
+
+	if isActiveError != nil {
+		server.SendInternalServerError(isActiveError, responseWriter)
+		return
+	}
+
+	if !active {
+		responseWriter.WriteHeader(http.StatusUnauthorized)
+		responseWriter.Header().Set("Content-Type", "application/json")
+		responseWriter.Write([]byte(`{"error": "Unauthorized"}`))
+		return
+	}
+
 	userId, err := uuid.Parse(dbToken.UserID)
 
 	if err != nil {
@@ -551,7 +713,18 @@ func (config *apiConfig) deleteChirp(responseWriter http.ResponseWriter, req *ht
 
 	chirpID := req.PathValue("id")
 
-	chirp, getChirpError := config.db.GetChirpByID(req.Context(), chirpID)
+	tx, beginError := config.conn.BeginTx(req.Context(), nil)
+
+	if beginError != nil {
+		server.SendInternalServerError(beginError, responseWriter)
+		return
+	}
+
+	defer tx.Rollback()
+
+	queries := config.db.WithTx(tx)
+
+	chirp, getChirpError := queries.GetChirpForUpdate(req.Context(), chirpID)
 
 	if getChirpError != nil {
 		responseWriter.Header().Set("Content-Type", "application/json")
@@ -565,7 +738,16 @@ func (config *apiConfig) deleteChirp(responseWriter http.ResponseWriter, req *ht
 		return
 	}
 
-	deleteChirpError := config.db.DeleteChirp(req.Context(), database.DeleteChirpParams{
+	if chirp.DeletedAt.Valid {
+		config.sendChirpTombstone(chirp, responseWriter)
+		return
+	}
+
+	if !config.checkChirpIfMatch(responseWriter, req, userUUID.String(), chirp) {
+		return
+	}
+
+	deleteChirpError := queries.DeleteChirp(req.Context(), database.DeleteChirpParams{
 		ID:     chirpID,
 		UserID: userUUID.String(),
 	})
@@ -575,6 +757,31 @@ func (config *apiConfig) deleteChirp(responseWriter http.ResponseWriter, req *ht
 		return
 	}
 
+	if touchError := queries.TouchChirpReferences(req.Context(), chirpID); touchError != nil {
+		server.SendInternalServerError(touchError, responseWriter)
+		return
+	}
+
+	// Streams never heard about a chirp that was still scheduled.
+	if !chirp.PublishAt.Valid {
+		if notifyError := notifyChirpEvent(req.Context(), queries, chirpEventDeleted, chirpID, userUUID.String()); notifyError != nil {
+			server.SendInternalServerError(notifyError, responseWriter)
+			return
+		}
+	}
+
+	if federates(chirp) {
+		if federateError := federateChirp(req.Context(), queries, activityDelete, userUUID.String(), chirpID); federateError != nil {
+			server.SendInternalServerError(federateError, responseWriter)
+			return
+		}
+	}
+
+	if commitError := tx.Commit(); commitError != nil {
+		server.SendInternalServerError(commitError, responseWriter)
+		return
+	}
+
 	responseWriter.WriteHeader(http.StatusNoContent)
 }
 
@@ -646,11 +853,109 @@ func (config *apiConfig) polkaWebhooks(responseWriter http.ResponseWriter, req *
 	}
 }
 
+func isUniqueViolation(err error) bool {
+	var pqError *pq.Error
+	return errors.As(err, &pqError) && pqError.Code == "23505"
+}
+
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+
+	if len(value) == 0 {
+		return fallback
+	}
+
+	duration, parseError := time.ParseDuration(value)
+
+	if parseError != nil {
+		log.Printf("invalid %s %q, using %v\n", key, value, fallback)
+		return fallback
+	}
+
+	return duration
+}
+
+func intFromEnv(key string, fallback int) int {
+	value := os.Getenv(key)
+
+	if len(value) == 0 {
+		return fallback
+	}
+
+	number, parseError := strconv.Atoi(value)
+
+	if parseError != nil || number <= 0 {
+		log.Printf("invalid %s %q, using %v\n", key, value, fallback)
+		return fallback
+	}
+
+	return number
+}
+
 func main() {
 	godotenv.Load()
 	dbURL := os.Getenv("DB_URL")
 	polkaKey := os.Getenv("POLKA_KEY")
 	jwtSecret := os.Getenv("JWT_SECRET")
+	retention := durationFromEnv("SOFT_DELETE_RETENTION", 30*24*time.Hour)
+	trendsWindow := durationFromEnv("TRENDS_WINDOW", 24*time.Hour)
+	trendsHalfLife := durationFromEnv("TRENDS_HALF_LIFE", 6*time.Hour)
+	trendsRefreshInterval := durationFromEnv("TRENDS_REFRESH_INTERVAL", 5*time.Minute)
+	scheduledPublishInterval := durationFromEnv("SCHEDULED_PUBLISH_INTERVAL", 15*time.Second)
+	chirpLimit := intFromEnv("CHIRP_LIMIT", 140)
+	chirpyRedChirpLimit := intFromEnv("CHIRPY_RED_CHIRP_LIMIT", 280)
+	contentFilterReloadInterval := durationFromEnv("CONTENT_FILTER_RELOAD_INTERVAL", 30*time.Second)
+	chirpRateWindow := durationFromEnv("CHIRP_RATE_WINDOW", time.Hour)
+	chirpRate := ratelimit.Policy{Limit: intFromEnv("CHIRP_RATE_LIMIT", 20), Window: chirpRateWindow}
+	chirpyRedChirpRate := ratelimit.Policy{Limit: intFromEnv("CHIRPY_RED_CHIRP_RATE_LIMIT", 60), Window: chirpRateWindow}
+	duplicateAuthorWindow := durationFromEnv("DUPLICATE_AUTHOR_WINDOW", 24*time.Hour)
+	duplicateGlobalWindow := durationFromEnv("DUPLICATE_GLOBAL_WINDOW", time.Hour)
+	idempotencyKeyTTL := durationFromEnv("IDEMPOTENCY_KEY_TTL", 24*time.Hour)
+	streamReplaySize := intFromEnv("STREAM_REPLAY_SIZE", 1000)
+	chirpEventRetention := durationFromEnv("STREAM_EVENT_RETENTION", 24*time.Hour)
+	streamHeartbeat := durationFromEnv("STREAM_HEARTBEAT_INTERVAL", 15*time.Second)
+	socketPingInterval := durationFromEnv("WS_PING_INTERVAL", 30*time.Second)
+	publicURL := strings.TrimSuffix(os.Getenv("PUBLIC_URL"), "/")
+
+	if len(publicURL) == 0 {
+		publicURL = "http://localhost:8080"
+	}
+
+	activityDeliveryInterval := durationFromEnv("ACTIVITYPUB_DELIVERY_INTERVAL", 10*time.Second)
+	activityMaxAttempts := intFromEnv("ACTIVITYPUB_MAX_ATTEMPTS", 10)
+	inboxRate := ratelimit.Policy{Limit: intFromEnv("ACTIVITYPUB_INBOX_RATE_LIMIT", 120), Window: time.Minute}
+	duplicateDetector := duplicates.Detector{
+		MinTokens:        intFromEnv("DUPLICATE_MIN_WORDS", 4),
+		MaxDistance:      intFromEnv("DUPLICATE_MAX_DISTANCE", 3),
+		GlobalMinAuthors: intFromEnv("DUPLICATE_GLOBAL_MIN_AUTHORS", 3),
+	}
+	duplicatePolicy, policyError := duplicates.ParsePolicy(os.Getenv("DUPLICATE_POLICY"))
+
+	if policyError != nil || len(duplicatePolicy) == 0 {
+		if policyError != nil {
+			log.Printf("invalid DUPLICATE_POLICY: %v, using the default\n", policyError)
+		}
+
+		duplicatePolicy = duplicates.Policy{
+			duplicates.KindAuthorExact: duplicates.ActionReject,
+			duplicates.KindAuthorNear:  duplicates.ActionFlag,
+			duplicates.KindGlobalExact: duplicates.ActionFlag,
+			duplicates.KindGlobalNear:  duplicates.ActionFlag,
+		}
+	}
+
+	mediaMaxBytes, parseError := strconv.ParseInt(os.Getenv("MEDIA_MAX_BYTES"), 10, 64)
+
+	if parseError != nil || mediaMaxBytes <= 0 {
+		mediaMaxBytes = 5 * 1024 * 1024
+	}
+
+	mediaStorage, storageError := newStorageFromEnv()
+
+	if storageError != nil {
+		log.Fatalf("failed to set up media storage: %v", storageError)
+	}
+
 	db, err := sql.Open("postgres", dbURL)
 
 	if err != nil {
@@ -664,11 +969,51 @@ func main() {
 	const port = ":8080"
 
 	config := apiConfig{
-		fileserverHits: atomic.Int32{},
-		db:             dbQueries,
-		secret:         jwtSecret,
-		polkaKey:       polkaKey,
-	}
+		fileserverHits:        atomic.Int32{},
+		conn:                  db,
+		db:                    dbQueries,
+		secret:                jwtSecret,
+		polkaKey:              polkaKey,
+		retention:             retention,
+		trendsWindow:          trendsWindow,
+		trendsHalfLife:        trendsHalfLife,
+		media:                 mediaStorage,
+		mediaMaxBytes:         mediaMaxBytes,
+		chirpLimit:            chirpLimit,
+		chirpyRedChirpLimit:   chirpyRedChirpLimit,
+		contentFilter:         contentfilter.NewHolder(contentfilter.New(nil)),
+		chirpRate:             chirpRate,
+		chirpyRedChirpRate:    chirpyRedChirpRate,
+		duplicateDetector:     duplicateDetector,
+		duplicatePolicy:       duplicatePolicy,
+		duplicateAuthorWindow: duplicateAuthorWindow,
+		duplicateGlobalWindow: duplicateGlobalWindow,
+		duplicateMetrics:      &duplicates.Metrics{},
+		idempotencyKeyTTL:     idempotencyKeyTTL,
+		chirpEvents:           broadcast.NewHub(streamReplaySize, streamSubscriberBuffer),
+		streamReplaySize:      streamReplaySize,
+		chirpEventRetention:   chirpEventRetention,
+		streamHeartbeat:       streamHeartbeat,
+		socketPingInterval:    socketPingInterval,
+		publicURL:             publicURL,
+		federation:            activitypub.NewClient("Chirpy", 10*time.Second, os.Getenv("ACTIVITYPUB_ALLOW_LOCAL") == "true"),
+		activityMaxAttempts:   activityMaxAttempts,
+		inboxRate:             inboxRate,
+	}
+
+	if reloadError := config.reloadContentFilter(context.Background()); reloadError != nil {
+		log.Printf("failed to load content filter rules: %v\n", reloadError)
+	}
+
+	go config.purgeDeletedRecords(context.Background(), time.Hour)
+	go config.refreshTrends(context.Background(), trendsRefreshInterval)
+	go config.publishScheduledChirps(context.Background(), scheduledPublishInterval)
+	go config.purgeRateLimitBuckets(context.Background(), time.Hour)
+	go config.purgeIdempotencyKeys(context.Background(), time.Hour)
+	go config.refreshContentFilter(context.Background(), contentFilterReloadInterval)
+	go config.listenForChirpEvents(context.Background(), dbURL)
+	go config.purgeChirpEvents(context.Background(), time.Hour)
+	go config.deliverActivities(context.Background(), activityDeliveryInterval)
 
 	mux := http.NewServeMux()
 
@@ -677,19 +1022,82 @@ func main() {
 	mux.Handle("/app/", config.middlewareMetricsInc(handler))
 
 	mux.HandleFunc("GET /api/healthz", config.healthHandler)
-	mux.HandleFunc("POST /api/users", config.createUser)
+	mux.HandleFunc("POST /api/users", config.idempotent(signupIdempotencyScope, config.createUser))
 	mux.HandleFunc("PUT /api/users", config.updateUser)
+	mux.HandleFunc("GET /api/users/{handle}", config.getUserProfile)
+	mux.HandleFunc("PUT /api/users/profile", config.unlessSuspended(config.updateUserProfile))
+	mux.HandleFunc("POST /api/users/{user}/follow", config.unlessSuspended(config.followUser))
+	mux.HandleFunc("DELETE /api/users/{user}/follow", config.unfollowUser)
+	mux.HandleFunc("GET /api/users/{user}/followers", config.listFollowers)
+	mux.HandleFunc("GET /api/users/{user}/following", config.listFollowing)
+	mux.HandleFunc("GET /api/timeline", config.getTimeline)
+	mux.HandleFunc("POST /api/users/{user}/block", config.blockUser)
+	mux.HandleFunc("DELETE /api/users/{user}/block", config.unblockUser)
+	mux.HandleFunc("POST /api/users/{user}/mute", config.muteUser)
+	mux.HandleFunc("DELETE /api/users/{user}/mute", config.unmuteUser)
+	mux.HandleFunc("POST /api/users/{user}/report", config.unlessSuspended(config.reportUser))
+	mux.HandleFunc("GET /api/blocks", config.listBlocks)
+	mux.HandleFunc("GET /api/mutes", config.listMutes)
 	mux.HandleFunc("GET /api/chirps", config.listChirps)
 	mux.HandleFunc("GET /api/chirps/{id}", config.getChirpById)
+	mux.HandleFunc("GET /api/chirps/scheduled", config.listScheduledChirps)
+	mux.HandleFunc("GET /api/chirps/stream", config.streamChirps)
+	mux.HandleFunc("GET /api/ws", config.chirpSocket)
+	mux.HandleFunc("GET /feeds/{feed}", config.globalFeed)
+	mux.HandleFunc("GET /feeds/users/{feed}", config.userFeed)
+	mux.HandleFunc("GET /.well-known/webfinger", config.webFinger)
+	mux.HandleFunc("GET /ap/users/{id}", config.getActor)
+	mux.HandleFunc("GET /ap/users/{id}/outbox", config.getActorOutbox)
+	mux.HandleFunc("GET /ap/users/{id}/followers", config.getActorFollowers)
+	mux.HandleFunc("POST /ap/users/{id}/inbox", config.postInbox)
+	mux.HandleFunc("GET /ap/chirps/{id}", config.getNote)
+	mux.HandleFunc("PUT /api/chirps/{id}/schedule", config.unlessSuspended(config.rescheduleChirp))
+	mux.HandleFunc("DELETE /api/chirps/{id}/schedule", config.cancelScheduledChirp)
+	mux.HandleFunc("POST /api/chirps/{id}/votes", config.unlessSuspended(config.votePoll))
+	mux.HandleFunc("POST /api/drafts", config.createDraft)
+	mux.HandleFunc("GET /api/drafts", config.listDrafts)
+	mux.HandleFunc("GET /api/drafts/{id}", config.getDraft)
+	mux.HandleFunc("PUT /api/drafts/{id}", config.updateDraft)
+	mux.HandleFunc("DELETE /api/drafts/{id}", config.deleteDraft)
+	mux.HandleFunc("POST /api/drafts/{id}/publish", config.unlessSuspended(config.publishDraft))
+	mux.HandleFunc("GET /api/chirps/{id}/thread", config.getChirpThread)
 	mux.HandleFunc("DELETE /api/chirps/{id}", config.deleteChirp)
-	mux.HandleFunc("POST /api/chirps", config.createChirp)
+	mux.HandleFunc("POST /api/chirps", config.unlessSuspended(config.idempotent(config.userIdempotencyScope, config.createChirp)))
+	mux.HandleFunc("POST /api/chirps/{id}/restore", config.unlessSuspended(config.restoreChirp))
+	mux.HandleFunc("POST /api/chirps/{id}/rechirp", config.unlessSuspended(config.rechirp))
+	mux.HandleFunc("DELETE /api/chirps/{id}/rechirp", config.undoRechirp)
+	mux.HandleFunc("POST /api/chirps/{id}/like", config.unlessSuspended(config.likeChirp))
+	mux.HandleFunc("DELETE /api/chirps/{id}/like", config.unlikeChirp)
+	mux.HandleFunc("GET /api/chirps/{id}/likes", config.listChirpLikers)
+	mux.HandleFunc("POST /api/chirps/{id}/report", config.unlessSuspended(config.reportChirp))
+	mux.HandleFunc("GET /api/bookmarks", config.listBookmarks)
+	mux.HandleFunc("POST /api/bookmarks", config.addBookmark)
+	mux.HandleFunc("DELETE /api/bookmarks/{chirp_id}", config.removeBookmark)
+	mux.HandleFunc("GET /api/hashtags/{tag}/chirps", config.listHashtagChirps)
+	mux.HandleFunc("GET /api/trends", config.listTrends)
+	mux.HandleFunc("POST /api/media", config.unlessSuspended(config.uploadMedia))
+	mux.HandleFunc("GET /api/media/{id}", config.serveMedia)
+	mux.HandleFunc("GET /api/media/{id}/thumbnail", config.serveMediaThumbnail)
+	mux.HandleFunc("GET /api/notifications", config.listNotifications)
+	mux.HandleFunc("GET /api/notifications/unread_count", config.countUnreadNotifications)
+	mux.HandleFunc("POST /api/notifications/read", config.markNotificationsRead)
+	mux.HandleFunc("GET /api/notifications/preferences", config.getNotificationPreferences)
+	mux.HandleFunc("PUT /api/notifications/preferences", config.updateNotificationPreferences)
 	mux.HandleFunc("POST /api/login", config.login)
 	mux.HandleFunc("POST /api/refresh", config.refreshSession)
 	mux.HandleFunc("POST /api/revoke", config.revokeSession)
-	mux.HandleFunc("POST /api/polka/webhooks", config.polkaWebhooks)
+	mux.HandleFunc("GET /api/moderation/reports", config.listReports)
+	mux.HandleFunc("POST /api/moderation/reports/{id}/decisions", config.unlessSuspended(config.decideReport))
+	mux.HandleFunc("POST /api/polka/webhooks", config.idempotent(config.polkaIdempotencyScope, config.polkaWebhooks))
 
 	mux.HandleFunc("GET /admin/metrics", config.metricsHandler)
+	mux.HandleFunc("GET /admin/metrics/duplicates", config.duplicateMetricsHandler)
 	mux.HandleFunc("POST /admin/reset", config.resetMetricsHandler)
+	mux.HandleFunc("GET /admin/content-filter/rules", config.listContentFilterRules)
+	mux.HandleFunc("POST /admin/content-filter/rules", config.createContentFilterRule)
+	mux.HandleFunc("PUT /admin/content-filter/rules/{id}", config.updateContentFilterRule)
+	mux.HandleFunc("DELETE /admin/content-filter/rules/{id}", config.deleteContentFilterRule)
+	mux.HandleFunc("GET /admin/content-filter/flags", config.listFlaggedChirps)
 
 	server := http.Server{
 		Addr:    port,
@@ -0,0 +1,355 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/octaviocarpes/go-http-servers/internal/database"
+	"github.com/octaviocarpes/go-http-servers/internal/media"
+	"github.com/octaviocarpes/go-http-servers/internal/storage"
+	server "github.com/octaviocarpes/go-http-servers/server"
+)
+
+const maxChirpMedia = 4
+
+var (
+	errTooManyMedia     = fmt.Errorf("a chirp can have at most %d media", maxChirpMedia)
+	errDuplicateMedia   = errors.New("media ids must be unique")
+	errMediaNotFound    = errors.New("media not found")
+	errMediaNotOwned    = errors.New("media belongs to another user")
+	errMediaUnavailable = errors.New("media is already attached to a chirp")
+)
+
+type mediaResponse struct {
+	ID           string `json:"id"`
+	ContentType  string `json:"content_type"`
+	Width        int32  `json:"width"`
+	Height       int32  `json:"height"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+func newMediaResponse(medium database.Medium) mediaResponse {
+	return mediaResponse{
+		ID:           medium.ID,
+		ContentType:  medium.ContentType,
+		Width:        medium.Width,
+		Height:       medium.Height,
+		URL:          "/api/media/" + medium.ID,
+		ThumbnailURL: "/api/media/" + medium.ID + "/thumbnail",
+	}
+}
+
+// newStorageFromEnv picks the media backend. MEDIA_STORAGE=s3 talks to any
+// S3-compatible endpoint; anything else keeps files under MEDIA_DIR.
+func newStorageFromEnv() (storage.Storage, error) {
+	if os.Getenv("MEDIA_STORAGE") == "s3" {
+		return storage.NewS3(
+			os.Getenv("S3_ENDPOINT"),
+			os.Getenv("S3_BUCKET"),
+			os.Getenv("S3_REGION"),
+			os.Getenv("S3_ACCESS_KEY_ID"),
+			os.Getenv("S3_SECRET_ACCESS_KEY"),
+		), nil
+	}
+
+	mediaDir := os.Getenv("MEDIA_DIR")
+
+	if len(mediaDir) == 0 {
+		mediaDir = "./media"
+	}
+
+	return storage.NewLocal(mediaDir)
+}
+
+func (config *apiConfig) uploadMedia(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	// Leave room for the multipart boundaries and headers around the file.
+	req.Body = http.MaxBytesReader(responseWriter, req.Body, config.mediaMaxBytes+64*1024)
+
+	reader, multipartError := req.MultipartReader()
+
+	if multipartError != nil {
+		server.SendError("expected a multipart/form-data body", http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	var data []byte
+
+	for {
+		part, partError := reader.NextPart()
+
+		if partError == io.EOF {
+			break
+		}
+
+		var maxBytesError *http.MaxBytesError
+
+		if errors.As(partError, &maxBytesError) {
+			server.SendError("file is too large", http.StatusRequestEntityTooLarge, responseWriter)
+			return
+		}
+
+		if partError != nil {
+			server.SendError("invalid multipart body", http.StatusBadRequest, responseWriter)
+			return
+		}
+
+		if part.FormName() != "file" {
+			continue
+		}
+
+		fileData, readError := io.ReadAll(io.LimitReader(part, config.mediaMaxBytes+1))
+
+		if errors.As(readError, &maxBytesError) || int64(len(fileData)) > config.mediaMaxBytes {
+			server.SendError("file is too large", http.StatusRequestEntityTooLarge, responseWriter)
+			return
+		}
+
+		if readError != nil {
+			server.SendError("invalid multipart body", http.StatusBadRequest, responseWriter)
+			return
+		}
+
+		data = fileData
+		break
+	}
+
+	if data == nil {
+		server.SendError("missing file field", http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	processed, processError := media.Process(data)
+
+	if errors.Is(processError, media.ErrUnsupportedType) {
+		server.SendError("only JPEG, PNG and GIF images are supported", http.StatusUnsupportedMediaType, responseWriter)
+		return
+	}
+
+	if errors.Is(processError, media.ErrTooManyPixels) {
+		server.SendError(processError.Error(), http.StatusRequestEntityTooLarge, responseWriter)
+		return
+	}
+
+	if processError != nil {
+		server.SendInternalServerError(processError, responseWriter)
+		return
+	}
+
+	id := uuid.NewString()
+	storageKey := "media/" + id + "/original"
+	thumbnailKey := "media/" + id + "/thumbnail"
+
+	if putError := config.media.Put(req.Context(), storageKey, processed.Original.Data, processed.Original.ContentType); putError != nil {
+		server.SendInternalServerError(putError, responseWriter)
+		return
+	}
+
+	if putError := config.media.Put(req.Context(), thumbnailKey, processed.Thumbnail.Data, processed.Thumbnail.ContentType); putError != nil {
+		config.deleteStoredMedia(req.Context(), storageKey)
+		server.SendInternalServerError(putError, responseWriter)
+		return
+	}
+
+	medium, createMediaError := config.db.CreateMedia(req.Context(), database.CreateMediaParams{
+		ID:                   id,
+		UserID:               userUUID.String(),
+		ContentType:          processed.Original.ContentType,
+		ByteSize:             int64(len(processed.Original.Data)),
+		Width:                int32(processed.Original.Width),
+		Height:               int32(processed.Original.Height),
+		StorageKey:           storageKey,
+		ThumbnailKey:         thumbnailKey,
+		ThumbnailContentType: processed.Thumbnail.ContentType,
+	})
+
+	if createMediaError != nil {
+		config.deleteStoredMedia(req.Context(), storageKey, thumbnailKey)
+		server.SendInternalServerError(createMediaError, responseWriter)
+		return
+	}
+
+	server.ResponseWithJson(newMediaResponse(medium), http.StatusCreated, responseWriter)
+}
+
+func (config *apiConfig) deleteStoredMedia(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if deleteError := config.media.Delete(ctx, key); deleteError != nil {
+			log.Printf("failed to delete stored media %s: %v\n", key, deleteError)
+		}
+	}
+}
+
+func (config *apiConfig) serveMedia(responseWriter http.ResponseWriter, req *http.Request) {
+	config.sendMedia(responseWriter, req, false)
+}
+
+func (config *apiConfig) serveMediaThumbnail(responseWriter http.ResponseWriter, req *http.Request) {
+	config.sendMedia(responseWriter, req, true)
+}
+
+// sendMedia streams a stored object to viewers who can see the chirp it is
+// attached to. Media never changes once uploaded, but whether it may be seen
+// does, so only the client may cache it and it is revalidated by id.
+func (config *apiConfig) sendMedia(responseWriter http.ResponseWriter, req *http.Request, thumbnail bool) {
+	medium, getMediaError := config.db.GetMedia(req.Context(), req.PathValue("id"))
+
+	if getMediaError != nil {
+		server.SendError("media not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	visible, visibleError := config.mediaVisible(req, medium)
+
+	if visibleError != nil {
+		server.SendInternalServerError(visibleError, responseWriter)
+		return
+	}
+
+	if !visible {
+		server.SendError("media not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	key, contentType, etag := medium.StorageKey, medium.ContentType, `"`+medium.ID+`"`
+
+	if thumbnail {
+		key, contentType, etag = medium.ThumbnailKey, medium.ThumbnailContentType, `"`+medium.ID+`-thumbnail"`
+	}
+
+	header := responseWriter.Header()
+	header.Set("Cache-Control", "private, no-cache")
+	header.Set("Vary", "Authorization")
+	header.Set("ETag", etag)
+
+	if req.Header.Get("If-None-Match") == etag {
+		responseWriter.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	object, getObjectError := config.media.Get(req.Context(), key)
+
+	if errors.Is(getObjectError, storage.ErrNotFound) {
+		header.Del("Cache-Control")
+		header.Del("ETag")
+		server.SendError("media not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	if getObjectError != nil {
+		header.Del("Cache-Control")
+		header.Del("ETag")
+		server.SendInternalServerError(getObjectError, responseWriter)
+		return
+	}
+
+	defer object.Close()
+
+	header.Set("Content-Type", contentType)
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("Content-Security-Policy", "default-src 'none'")
+
+	if !thumbnail {
+		header.Set("Content-Length", strconv.FormatInt(medium.ByteSize, 10))
+	}
+
+	responseWriter.WriteHeader(http.StatusOK)
+	io.Copy(responseWriter, object)
+}
+
+// mediaVisible reports whether the requester may see medium. Media that is
+// not attached yet is only its uploader's; attached media goes wherever the
+// chirp's content does.
+func (config *apiConfig) mediaVisible(req *http.Request, medium database.Medium) (bool, error) {
+	viewer, audienceError := config.requestAudience(req)
+
+	if audienceError != nil {
+		return false, audienceError
+	}
+
+	attachment, getChirpError := config.db.GetMediaChirp(req.Context(), medium.ID)
+
+	if errors.Is(getChirpError, sql.ErrNoRows) {
+		return viewer.viewerID == medium.UserID, nil
+	}
+
+	if getChirpError != nil {
+		return false, getChirpError
+	}
+
+	chirp := attachment.Chirp
+
+	if chirp.DeletedAt.Valid || (chirp.HiddenAt.Valid && !viewer.moderator) {
+		return false, nil
+	}
+
+	return viewer.canSeeChirp(chirp), nil
+}
+
+// checkChirpMedia verifies that userID may attach mediaIDs to a new chirp.
+// The attach itself happens in insertChirp, which re-checks atomically.
+func (config *apiConfig) checkChirpMedia(ctx context.Context, userID string, mediaIDs []string) error {
+	if len(mediaIDs) > maxChirpMedia {
+		return errTooManyMedia
+	}
+
+	for i, id := range mediaIDs {
+		if slices.Contains(mediaIDs[:i], id) {
+			return errDuplicateMedia
+		}
+	}
+
+	if len(mediaIDs) == 0 {
+		return nil
+	}
+
+	rows, listError := config.db.ListMediaForAttach(ctx, mediaIDs)
+
+	if listError != nil {
+		return listError
+	}
+
+	if len(rows) != len(mediaIDs) {
+		return errMediaNotFound
+	}
+
+	for _, row := range rows {
+		if row.UserID != userID {
+			return errMediaNotOwned
+		}
+
+		if row.Attached {
+			return errMediaUnavailable
+		}
+	}
+
+	return nil
+}
+
+func sendChirpMediaError(mediaError error, responseWriter http.ResponseWriter) {
+	switch {
+	case errors.Is(mediaError, errTooManyMedia), errors.Is(mediaError, errDuplicateMedia), errors.Is(mediaError, errMediaNotFound):
+		server.SendError(mediaError.Error(), http.StatusBadRequest, responseWriter)
+	case errors.Is(mediaError, errMediaNotOwned):
+		server.SendError(mediaError.Error(), http.StatusForbidden, responseWriter)
+	case errors.Is(mediaError, errMediaUnavailable):
+		server.SendError(mediaError.Error(), http.StatusConflict, responseWriter)
+	default:
+		server.SendInternalServerError(mediaError, responseWriter)
+	}
+}
@@ -0,0 +1,583 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	auth "github.com/octaviocarpes/go-http-servers/internal/auth"
+	"github.com/octaviocarpes/go-http-servers/internal/database"
+	server "github.com/octaviocarpes/go-http-servers/server"
+)
+
+const (
+	maxReportDetailsLength  = 500
+	maxModerationNoteLength = 1000
+	defaultSuspensionDays   = 7
+	maxSuspensionDays       = 365
+)
+
+const reportReasonOther = "other"
+
+const (
+	moderationActionDismiss = "dismiss"
+	moderationActionHide    = "hide"
+	moderationActionDelete  = "delete"
+	moderationActionWarn    = "warn"
+	moderationActionSuspend = "suspend"
+)
+
+var errReportResolved = errors.New("report is already resolved")
+
+var reportReasons = []string{
+	"spam",
+	"harassment",
+	"hate",
+	"violence",
+	"self_harm",
+	"sexual_content",
+	"impersonation",
+	reportReasonOther,
+}
+
+var moderationActions = []string{
+	moderationActionDismiss,
+	moderationActionHide,
+	moderationActionDelete,
+	moderationActionWarn,
+	moderationActionSuspend,
+}
+
+type reportResponse struct {
+	ID         string         `json:"id"`
+	ReporterID string         `json:"reporter_id"`
+	UserID     string         `json:"user_id"`
+	ChirpID    *string        `json:"chirp_id"`
+	Reason     string         `json:"reason"`
+	Details    string         `json:"details"`
+	CreatedAt  time.Time      `json:"created_at"`
+	Chirp      *chirpResponse `json:"chirp,omitempty"`
+}
+
+// suspended reports whether a moderator has suspended user for now.
+func suspended(user database.User) bool {
+	return user.SuspendedUntil.Valid && user.SuspendedUntil.Time.After(time.Now())
+}
+
+// unlessSuspended turns suspended users away from next with 403 Forbidden.
+// It wraps every route through which a user acts on others or publishes
+// anything; routes that only touch their own account, such as logging in,
+// deleting their chirps or blocking someone, stay open. Requests without a
+// valid token go through for next to reject.
+func (config *apiConfig) unlessSuspended(next http.HandlerFunc) http.HandlerFunc {
+	return func(responseWriter http.ResponseWriter, req *http.Request) {
+		token, getTokenError := auth.GetBearerToken(req.Header)
+
+		if getTokenError != nil {
+			next(responseWriter, req)
+			return
+		}
+
+		userUUID, invalidTokenError := auth.ValidateJWT(token, config.secret)
+
+		if invalidTokenError != nil {
+			next(responseWriter, req)
+			return
+		}
+
+		user, getUserError := config.db.GetUserByID(req.Context(), userUUID.String())
+
+		if getUserError == nil && suspended(user) {
+			server.SendError(errSuspended.Error(), http.StatusForbidden, responseWriter)
+			return
+		}
+
+		next(responseWriter, req)
+	}
+}
+
+func newReportResponse(report database.Report) reportResponse {
+	response := reportResponse{
+		ID:         report.ID,
+		ReporterID: report.ReporterID,
+		UserID:     report.UserID,
+		Reason:     report.Reason,
+		Details:    report.Details,
+		CreatedAt:  report.CreatedAt,
+	}
+
+	if report.ChirpID.Valid {
+		chirpID := report.ChirpID.String
+		response.ChirpID = &chirpID
+	}
+
+	return response
+}
+
+type moderationDecisionResponse struct {
+	ID              string    `json:"id"`
+	ModeratorID     string    `json:"moderator_id"`
+	UserID          string    `json:"user_id"`
+	ChirpID         *string   `json:"chirp_id"`
+	Action          string    `json:"action"`
+	Note            string    `json:"note"`
+	CreatedAt       time.Time `json:"created_at"`
+	ResolvedReports []string  `json:"resolved_reports"`
+}
+
+type reportBody struct {
+	Reason  string `json:"reason"`
+	Details string `json:"details"`
+}
+
+func decodeReportBody(responseWriter http.ResponseWriter, req *http.Request) (reportBody, bool) {
+	decodedPayload, decodeError := server.DecodeBody[reportBody](req.Body)
+
+	if decodeError != nil {
+		server.SendError("invalid request body", http.StatusBadRequest, responseWriter)
+		return reportBody{}, false
+	}
+
+	if !slices.Contains(reportReasons, decodedPayload.Reason) {
+		server.SendError("reason must be one of "+strings.Join(reportReasons, ", "), http.StatusBadRequest, responseWriter)
+		return reportBody{}, false
+	}
+
+	decodedPayload.Details = strings.TrimSpace(decodedPayload.Details)
+
+	if utf8.RuneCountInString(decodedPayload.Details) > maxReportDetailsLength {
+		server.SendError("details must be at most 500 characters", http.StatusBadRequest, responseWriter)
+		return reportBody{}, false
+	}
+
+	if decodedPayload.Reason == reportReasonOther && len(decodedPayload.Details) == 0 {
+		server.SendError("details are required when the reason is other", http.StatusBadRequest, responseWriter)
+		return reportBody{}, false
+	}
+
+	return decodedPayload, true
+}
+
+// fileReport records a report by reporterID and answers the request. Each
+// reporter has at most one open report per chirp or user.
+func (config *apiConfig) fileReport(responseWriter http.ResponseWriter, req *http.Request, params database.CreateReportParams) {
+	body, ok := decodeReportBody(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	params.Reason = body.Reason
+	params.Details = body.Details
+
+	report, createError := config.db.CreateReport(req.Context(), params)
+
+	if isUniqueViolation(createError) {
+		server.SendError("you have already reported this", http.StatusConflict, responseWriter)
+		return
+	}
+
+	if createError != nil {
+		server.SendInternalServerError(createError, responseWriter)
+		return
+	}
+
+	server.ResponseWithJson(newReportResponse(report), http.StatusCreated, responseWriter)
+}
+
+func (config *apiConfig) reportChirp(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	chirp, getChirpError := config.db.GetChirpByID(req.Context(), req.PathValue("id"))
+
+	// A rechirp has no content of its own, so reporting one reports the
+	// chirp it shares.
+	if getChirpError == nil && chirp.RechirpOf.Valid {
+		chirp, getChirpError = config.db.GetChirpByID(req.Context(), chirp.RechirpOf.String)
+	}
+
+	if getChirpError != nil || chirp.PublishAt.Valid {
+		server.SendError("chirp not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	if chirp.UserID == userUUID.String() {
+		server.SendError("you cannot report your own chirp", http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	config.fileReport(responseWriter, req, database.CreateReportParams{
+		ReporterID: userUUID.String(),
+		UserID:     chirp.UserID,
+		ChirpID:    sql.NullString{String: chirp.ID, Valid: true},
+	})
+}
+
+func (config *apiConfig) reportUser(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	reported, getUserError := config.lookupUser(req.Context(), req.PathValue("user"))
+
+	if getUserError != nil || reported.DeletedAt.Valid {
+		server.SendError("user not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	if reported.ID == userUUID.String() {
+		server.SendError("you cannot report yourself", http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	config.fileReport(responseWriter, req, database.CreateReportParams{
+		ReporterID: userUUID.String(),
+		UserID:     reported.ID,
+	})
+}
+
+// listReports is the moderation queue: open reports, oldest first, with the
+// reported chirp as it looked before any masking.
+func (config *apiConfig) listReports(responseWriter http.ResponseWriter, req *http.Request) {
+	if _, ok := config.authenticateModerator(responseWriter, req); !ok {
+		return
+	}
+
+	query := req.URL.Query()
+
+	limit, limitError := server.ParseLimit(query, 50, 200)
+
+	if limitError != nil {
+		server.SendError(limitError.Error(), http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	var afterCreatedAt time.Time
+	var afterID string
+
+	if cursor := query.Get("cursor"); len(cursor) > 0 {
+		decodedCreatedAt, decodedID, cursorError := server.DecodeTimeCursor(cursor)
+
+		if cursorError != nil {
+			server.SendError(cursorError.Error(), http.StatusBadRequest, responseWriter)
+			return
+		}
+
+		afterCreatedAt, afterID = decodedCreatedAt, decodedID
+	}
+
+	reports, listError := config.db.ListOpenReports(req.Context(), database.ListOpenReportsParams{
+		AfterCreatedAt: afterCreatedAt,
+		AfterID:        afterID,
+		PageSize:       limit + 1,
+	})
+
+	if listError != nil {
+		server.SendInternalServerError(listError, responseWriter)
+		return
+	}
+
+	nextCursor := ""
+
+	if len(reports) > int(limit) {
+		reports = reports[:limit]
+		last := reports[len(reports)-1]
+		nextCursor = server.EncodeTimeCursor(last.CreatedAt, last.ID)
+	}
+
+	chirpIDs := []string{}
+
+	for _, report := range reports {
+		if report.ChirpID.Valid && !slices.Contains(chirpIDs, report.ChirpID.String) {
+			chirpIDs = append(chirpIDs, report.ChirpID.String)
+		}
+	}
+
+	chirps, listChirpsError := config.db.ListChirpsByIDs(req.Context(), chirpIDs)
+
+	if listChirpsError != nil {
+		server.SendInternalServerError(listChirpsError, responseWriter)
+		return
+	}
+
+	chirpResponses, buildResponseError := config.buildChirpResponses(req.Context(), audience{moderator: true}, chirps)
+
+	if buildResponseError != nil {
+		server.SendInternalServerError(buildResponseError, responseWriter)
+		return
+	}
+
+	reportedChirps := make(map[string]chirpResponse, len(chirpResponses))
+
+	for _, chirp := range chirpResponses {
+		reportedChirps[chirp.ID] = chirp
+	}
+
+	type reportsResponse struct {
+		Reports    []reportResponse `json:"reports"`
+		NextCursor string           `json:"next_cursor,omitempty"`
+	}
+
+	response := reportsResponse{
+		Reports:    make([]reportResponse, len(reports)),
+		NextCursor: nextCursor,
+	}
+
+	for i, report := range reports {
+		response.Reports[i] = newReportResponse(report)
+
+		if chirp, ok := reportedChirps[report.ChirpID.String]; report.ChirpID.Valid && ok {
+			response.Reports[i].Chirp = &chirp
+		}
+	}
+
+	server.ResponseWithJson(response, http.StatusOK, responseWriter)
+}
+
+type moderationDecisionBody struct {
+	Action      string `json:"action"`
+	Note        string `json:"note"`
+	SuspendDays *int   `json:"suspend_days"`
+}
+
+func decodeModerationDecision(responseWriter http.ResponseWriter, req *http.Request, report database.Report) (moderationDecisionBody, bool) {
+	decodedPayload, decodeError := server.DecodeBody[moderationDecisionBody](req.Body)
+
+	if decodeError != nil {
+		server.SendError("invalid request body", http.StatusBadRequest, responseWriter)
+		return moderationDecisionBody{}, false
+	}
+
+	if !slices.Contains(moderationActions, decodedPayload.Action) {
+		server.SendError("action must be one of "+strings.Join(moderationActions, ", "), http.StatusBadRequest, responseWriter)
+		return moderationDecisionBody{}, false
+	}
+
+	if (decodedPayload.Action == moderationActionHide || decodedPayload.Action == moderationActionDelete) && !report.ChirpID.Valid {
+		server.SendError("this report is not about a chirp", http.StatusBadRequest, responseWriter)
+		return moderationDecisionBody{}, false
+	}
+
+	decodedPayload.Note = strings.TrimSpace(decodedPayload.Note)
+	noteLength := utf8.RuneCountInString(decodedPayload.Note)
+
+	if noteLength == 0 || noteLength > maxModerationNoteLength {
+		server.SendError("note is required and must be at most 1000 characters", http.StatusBadRequest, responseWriter)
+		return moderationDecisionBody{}, false
+	}
+
+	if decodedPayload.SuspendDays == nil {
+		suspendDays := defaultSuspensionDays
+		decodedPayload.SuspendDays = &suspendDays
+	}
+
+	if *decodedPayload.SuspendDays < 1 || *decodedPayload.SuspendDays > maxSuspensionDays {
+		server.SendError("suspend_days must be between 1 and 365", http.StatusBadRequest, responseWriter)
+		return moderationDecisionBody{}, false
+	}
+
+	return decodedPayload, true
+}
+
+// retractChirp takes a chirp a moderator just hid or removed off the counts
+// of the chirps it refers to, the live streams and, if it had been
+// federated, remote servers.
+func retractChirp(ctx context.Context, queries *database.Queries, chirp database.Chirp) error {
+	if touchError := queries.TouchChirpReferences(ctx, chirp.ID); touchError != nil {
+		return touchError
+	}
+
+	if !chirp.PublishAt.Valid {
+		if notifyError := notifyChirpEvent(ctx, queries, chirpEventDeleted, chirp.ID, chirp.UserID); notifyError != nil {
+			return notifyError
+		}
+	}
+
+	if !federates(chirp) {
+		return nil
+	}
+
+	return federateChirp(ctx, queries, activityDelete, chirp.UserID, chirp.ID)
+}
+
+// applyModerationAction carries out decision against the reported user and
+// chirp.
+func applyModerationAction(ctx context.Context, queries *database.Queries, decision database.ModerationDecision, suspendDays int) error {
+	switch decision.Action {
+	case moderationActionHide:
+		chirp, getChirpError := queries.GetChirpByID(ctx, decision.ChirpID.String)
+
+		if getChirpError != nil {
+			return getChirpError
+		}
+
+		if hideError := queries.HideChirp(ctx, chirp.ID); hideError != nil {
+			return hideError
+		}
+
+		return retractChirp(ctx, queries, chirp)
+	case moderationActionDelete:
+		chirp, getChirpError := queries.GetChirpByID(ctx, decision.ChirpID.String)
+
+		if getChirpError != nil {
+			return getChirpError
+		}
+
+		// A removed chirp is also hidden, which keeps its author from
+		// restoring it during the retention window.
+		if removeError := queries.RemoveChirp(ctx, chirp.ID); removeError != nil {
+			return removeError
+		}
+
+		return retractChirp(ctx, queries, chirp)
+	case moderationActionWarn:
+		return notifySystem(ctx, queries, notificationModerationWarning, decision.UserID, decision.ChirpID.String)
+	case moderationActionSuspend:
+		return queries.SuspendUser(ctx, database.SuspendUserParams{
+			SuspendedUntil: sql.NullTime{Time: time.Now().UTC().AddDate(0, 0, suspendDays), Valid: true},
+			ID:             decision.UserID,
+		})
+	default:
+		return nil
+	}
+}
+
+// decideReport records a moderator's decision on a report. The decision
+// resolves every open report about the same chirp or user, and each reporter
+// is told the outcome.
+func (config *apiConfig) decideReport(responseWriter http.ResponseWriter, req *http.Request) {
+	moderatorUUID, ok := config.authenticateModerator(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	report, getReportError := config.db.GetReport(req.Context(), req.PathValue("id"))
+
+	if errors.Is(getReportError, sql.ErrNoRows) {
+		server.SendError("report not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	if getReportError != nil {
+		server.SendInternalServerError(getReportError, responseWriter)
+		return
+	}
+
+	if report.DecisionID.Valid {
+		server.SendError(errReportResolved.Error(), http.StatusConflict, responseWriter)
+		return
+	}
+
+	// Reports outlive what they are about, which may have been purged since.
+	present, existsError := config.db.ReportSubjectExists(req.Context(), database.ReportSubjectExistsParams{
+		UserID:  report.UserID,
+		ChirpID: report.ChirpID,
+	})
+
+	if existsError != nil {
+		server.SendInternalServerError(existsError, responseWriter)
+		return
+	}
+
+	if !present {
+		server.SendError("the reported user or chirp no longer exists", http.StatusGone, responseWriter)
+		return
+	}
+
+	body, ok := decodeModerationDecision(responseWriter, req, report)
+
+	if !ok {
+		return
+	}
+
+	tx, beginError := config.conn.BeginTx(req.Context(), nil)
+
+	if beginError != nil {
+		server.SendInternalServerError(beginError, responseWriter)
+		return
+	}
+
+	defer tx.Rollback()
+
+	queries := config.db.WithTx(tx)
+
+	decision, createError := queries.CreateModerationDecision(req.Context(), database.CreateModerationDecisionParams{
+		ModeratorID: moderatorUUID.String(),
+		UserID:      report.UserID,
+		ChirpID:     report.ChirpID,
+		Action:      body.Action,
+		Note:        body.Note,
+	})
+
+	if createError != nil {
+		server.SendInternalServerError(createError, responseWriter)
+		return
+	}
+
+	resolved, resolveError := queries.ResolveReports(req.Context(), database.ResolveReportsParams{
+		DecisionID: sql.NullString{String: decision.ID, Valid: true},
+		UserID:     report.UserID,
+		ChirpID:    report.ChirpID,
+	})
+
+	if resolveError != nil {
+		server.SendInternalServerError(resolveError, responseWriter)
+		return
+	}
+
+	// Another moderator decided the same reports first.
+	if len(resolved) == 0 {
+		server.SendError(errReportResolved.Error(), http.StatusConflict, responseWriter)
+		return
+	}
+
+	if applyError := applyModerationAction(req.Context(), queries, decision, *body.SuspendDays); applyError != nil {
+		server.SendInternalServerError(applyError, responseWriter)
+		return
+	}
+
+	resolvedIDs := make([]string, len(resolved))
+
+	for i, resolvedReport := range resolved {
+		resolvedIDs[i] = resolvedReport.ID
+
+		if notifyError := notifySystem(req.Context(), queries, notificationReportResolved, resolvedReport.ReporterID, resolvedReport.ChirpID.String); notifyError != nil {
+			server.SendInternalServerError(notifyError, responseWriter)
+			return
+		}
+	}
+
+	if commitError := tx.Commit(); commitError != nil {
+		server.SendInternalServerError(commitError, responseWriter)
+		return
+	}
+
+	response := moderationDecisionResponse{
+		ID:              decision.ID,
+		ModeratorID:     decision.ModeratorID,
+		UserID:          decision.UserID,
+		Action:          decision.Action,
+		Note:            decision.Note,
+		CreatedAt:       decision.CreatedAt,
+		ResolvedReports: resolvedIDs,
+	}
+
+	if decision.ChirpID.Valid {
+		chirpID := decision.ChirpID.String
+		response.ChirpID = &chirpID
+	}
+
+	server.ResponseWithJson(response, http.StatusCreated, responseWriter)
+}
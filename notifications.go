@@ -0,0 +1,312 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/octaviocarpes/go-http-servers/internal/database"
+	server "github.com/octaviocarpes/go-http-servers/server"
+)
+
+const (
+	notificationMention = "mention"
+	notificationReply   = "reply"
+	notificationLike    = "like"
+	notificationFollow  = "follow"
+
+	notificationReportResolved    = "report_resolved"
+	notificationModerationWarning = "moderation_warning"
+)
+
+var notificationTypes = []string{
+	notificationMention,
+	notificationReply,
+	notificationLike,
+	notificationFollow,
+	notificationReportResolved,
+	notificationModerationWarning,
+}
+
+// mandatoryNotificationTypes cannot be turned off; a warning has to reach
+// the user it warns.
+var mandatoryNotificationTypes = []string{
+	notificationModerationWarning,
+}
+
+// notify records a notification for recipientID unless they are the actor or
+// have opted out of that notification type.
+func notify(ctx context.Context, queries *database.Queries, notificationType, recipientID, actorID, chirpID string) error {
+	return queries.CreateNotification(ctx, database.CreateNotificationParams{
+		UserID:  recipientID,
+		ActorID: actorID,
+		Type:    notificationType,
+		ChirpID: sql.NullString{String: chirpID, Valid: len(chirpID) > 0},
+	})
+}
+
+// notifySystem records a notice from Chirpy itself, such as a moderation
+// warning. It has no actor, so it reveals no moderator and no block or mute
+// can hold it back; only the recipient's preferences apply.
+func notifySystem(ctx context.Context, queries *database.Queries, notificationType, recipientID, chirpID string) error {
+	return queries.CreateSystemNotification(ctx, database.CreateSystemNotificationParams{
+		UserID:  recipientID,
+		Type:    notificationType,
+		ChirpID: sql.NullString{String: chirpID, Valid: len(chirpID) > 0},
+	})
+}
+
+// resolveMentions maps mentioned handles to user ids, dropping handles that
+// do not belong to anyone and users on either side of a block with authorID.
+func resolveMentions(ctx context.Context, queries *database.Queries, authorID string, mentions []string) ([]string, error) {
+	rows, resolveError := queries.ResolveMentions(ctx, database.ResolveMentionsParams{
+		Mentions: mentions,
+		AuthorID: authorID,
+	})
+
+	if resolveError != nil {
+		return nil, resolveError
+	}
+
+	userIDs := make([]string, len(rows))
+
+	for i, row := range rows {
+		userIDs[i] = row.ID
+	}
+
+	return userIDs, nil
+}
+
+func (config *apiConfig) listNotifications(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	query := req.URL.Query()
+
+	limit, limitError := server.ParseLimit(query, 50, 200)
+
+	if limitError != nil {
+		server.SendError(limitError.Error(), http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	beforeCreatedAt, beforeID, cursorError := server.ParseTimeCursor(query)
+
+	if cursorError != nil {
+		server.SendError(cursorError.Error(), http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	notifications, listNotificationsError := config.db.ListNotifications(req.Context(), database.ListNotificationsParams{
+		UserID:          userUUID.String(),
+		UnreadOnly:      query.Get("unread") == "true",
+		BeforeCreatedAt: beforeCreatedAt,
+		BeforeID:        beforeID,
+		PageSize:        limit + 1,
+	})
+
+	if listNotificationsError != nil {
+		server.SendInternalServerError(listNotificationsError, responseWriter)
+		return
+	}
+
+	nextCursor := ""
+
+	if len(notifications) > int(limit) {
+		notifications = notifications[:limit]
+		last := notifications[len(notifications)-1]
+		nextCursor = server.EncodeTimeCursor(last.CreatedAt, last.ID)
+	}
+
+	unread, countUnreadError := config.db.CountUnreadNotifications(req.Context(), userUUID.String())
+
+	if countUnreadError != nil {
+		server.SendInternalServerError(countUnreadError, responseWriter)
+		return
+	}
+
+	type notificationResponse struct {
+		ID        string    `json:"id"`
+		Type      string    `json:"type"`
+		ActorID   *string   `json:"actor_id"`
+		ChirpID   *string   `json:"chirp_id"`
+		CreatedAt time.Time `json:"created_at"`
+		Read      bool      `json:"read"`
+	}
+
+	type notificationsResponse struct {
+		Notifications []notificationResponse `json:"notifications"`
+		Unread        int64                  `json:"unread"`
+		NextCursor    string                 `json:"next_cursor,omitempty"`
+	}
+
+	response := notificationsResponse{
+		Notifications: make([]notificationResponse, len(notifications)),
+		Unread:        unread,
+		NextCursor:    nextCursor,
+	}
+
+	for i, notification := range notifications {
+		response.Notifications[i] = notificationResponse{
+			ID:        notification.ID,
+			Type:      notification.Type,
+			CreatedAt: notification.CreatedAt,
+			Read:      notification.ReadAt.Valid,
+		}
+
+		if notification.ActorID.Valid {
+			response.Notifications[i].ActorID = &notification.ActorID.String
+		}
+
+		if notification.ChirpID.Valid {
+			response.Notifications[i].ChirpID = &notification.ChirpID.String
+		}
+	}
+
+	server.ResponseWithJson(response, http.StatusOK, responseWriter)
+}
+
+func (config *apiConfig) countUnreadNotifications(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	unread, countUnreadError := config.db.CountUnreadNotifications(req.Context(), userUUID.String())
+
+	if countUnreadError != nil {
+		server.SendInternalServerError(countUnreadError, responseWriter)
+		return
+	}
+
+	type unreadResponse struct {
+		Unread int64 `json:"unread"`
+	}
+
+	server.ResponseWithJson(unreadResponse{Unread: unread}, http.StatusOK, responseWriter)
+}
+
+func (config *apiConfig) markNotificationsRead(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	// An empty body or an empty ids list marks every notification as read.
+	type markReadBody struct {
+		IDs []string `json:"ids"`
+	}
+
+	decodedPayload := markReadBody{}
+
+	if req.ContentLength != 0 {
+		payload, decodeError := server.DecodeBody[markReadBody](req.Body)
+
+		if decodeError != nil {
+			server.SendError("invalid request body", http.StatusBadRequest, responseWriter)
+			return
+		}
+
+		decodedPayload = payload
+	}
+
+	ids := decodedPayload.IDs
+
+	if ids == nil {
+		ids = []string{}
+	}
+
+	_, markReadError := config.db.MarkNotificationsRead(req.Context(), database.MarkNotificationsReadParams{
+		UserID: userUUID.String(),
+		Ids:    ids,
+	})
+
+	if markReadError != nil {
+		server.SendInternalServerError(markReadError, responseWriter)
+		return
+	}
+
+	responseWriter.WriteHeader(http.StatusNoContent)
+}
+
+func (config *apiConfig) sendNotificationPreferences(ctx context.Context, userID string, responseWriter http.ResponseWriter) {
+	preferences, listPreferencesError := config.db.ListNotificationPreferences(ctx, userID)
+
+	if listPreferencesError != nil {
+		server.SendInternalServerError(listPreferencesError, responseWriter)
+		return
+	}
+
+	response := make(map[string]bool, len(notificationTypes))
+
+	for _, notificationType := range notificationTypes {
+		response[notificationType] = true
+	}
+
+	for _, preference := range preferences {
+		response[preference.Type] = preference.Enabled
+	}
+
+	server.ResponseWithJson(response, http.StatusOK, responseWriter)
+}
+
+func (config *apiConfig) getNotificationPreferences(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	config.sendNotificationPreferences(req.Context(), userUUID.String(), responseWriter)
+}
+
+func (config *apiConfig) updateNotificationPreferences(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	decodedPayload, decodeError := server.DecodeBody[map[string]bool](req.Body)
+
+	if decodeError != nil {
+		server.SendError("invalid request body", http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	for notificationType := range decodedPayload {
+		if !slices.Contains(notificationTypes, notificationType) {
+			server.SendError("unknown notification type: "+notificationType, http.StatusBadRequest, responseWriter)
+			return
+		}
+	}
+
+	for notificationType, enabled := range decodedPayload {
+		if !enabled && slices.Contains(mandatoryNotificationTypes, notificationType) {
+			server.SendError(notificationType+" notifications cannot be turned off", http.StatusBadRequest, responseWriter)
+			return
+		}
+	}
+
+	for notificationType, enabled := range decodedPayload {
+		upsertError := config.db.UpsertNotificationPreference(req.Context(), database.UpsertNotificationPreferenceParams{
+			UserID:  userUUID.String(),
+			Type:    notificationType,
+			Enabled: enabled,
+		})
+
+		if upsertError != nil {
+			server.SendInternalServerError(upsertError, responseWriter)
+			return
+		}
+	}
+
+	config.sendNotificationPreferences(req.Context(), userUUID.String(), responseWriter)
+}
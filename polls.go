@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/octaviocarpes/go-http-servers/internal/database"
+	server "github.com/octaviocarpes/go-http-servers/server"
+)
+
+const (
+	minPollOptions      = 2
+	maxPollOptions      = 4
+	maxPollOptionLength = 25
+	maxPollDuration     = 7 * 24 * time.Hour
+)
+
+var (
+	errPollOptionCount   = fmt.Errorf("a poll needs %d to %d options", minPollOptions, maxPollOptions)
+	errPollOptionInvalid = fmt.Errorf("poll options must be unique and 1 to %d characters long", maxPollOptionLength)
+	errPollClosesAt      = errors.New("a poll must close after the chirp is published and within 7 days")
+	errPollNotFound      = errors.New("poll not found")
+	errPollClosed        = errors.New("poll is closed")
+	errPollOptionUnknown = errors.New("poll option does not exist")
+	errAlreadyVoted      = errors.New("you already voted in this poll")
+)
+
+type pollInput struct {
+	Options  []string  `json:"options"`
+	ClosesAt time.Time `json:"closes_at"`
+}
+
+type pollOptionResponse struct {
+	Position int32  `json:"position"`
+	Label    string `json:"label"`
+	Votes    *int32 `json:"votes,omitempty"`
+}
+
+// pollResponse carries the tallies only once the viewer voted or the poll
+// closed, so that early results cannot sway anyone's vote.
+type pollResponse struct {
+	ClosesAt    time.Time            `json:"closes_at"`
+	Closed      bool                 `json:"closed"`
+	Options     []pollOptionResponse `json:"options"`
+	TotalVotes  *int64               `json:"total_votes,omitempty"`
+	VotedOption *int32               `json:"voted_option,omitempty"`
+}
+
+// validatePoll checks a poll for a chirp that goes public at opensAt and
+// returns it with its options trimmed.
+func validatePoll(input pollInput, opensAt time.Time) (pollInput, error) {
+	if len(input.Options) < minPollOptions || len(input.Options) > maxPollOptions {
+		return pollInput{}, errPollOptionCount
+	}
+
+	options := make([]string, len(input.Options))
+
+	for i, option := range input.Options {
+		option = strings.TrimSpace(option)
+		length := utf8.RuneCountInString(option)
+
+		if length == 0 || length > maxPollOptionLength || slices.Contains(options[:i], option) {
+			return pollInput{}, errPollOptionInvalid
+		}
+
+		options[i] = option
+	}
+
+	if !input.ClosesAt.After(opensAt) || input.ClosesAt.After(opensAt.Add(maxPollDuration)) {
+		return pollInput{}, errPollClosesAt
+	}
+
+	return pollInput{Options: options, ClosesAt: input.ClosesAt}, nil
+}
+
+// loadPolls returns the polls attached to chirpIDs, keyed by chirp id, as
+// viewerID should see them.
+func (config *apiConfig) loadPolls(ctx context.Context, viewerID string, chirpIDs []string) (map[string]*pollResponse, error) {
+	rows, listError := config.db.ListPolls(ctx, database.ListPollsParams{
+		UserID:   viewerID,
+		ChirpIds: chirpIDs,
+	})
+
+	if listError != nil {
+		return nil, listError
+	}
+
+	polls := map[string]*pollResponse{}
+	now := time.Now()
+
+	for _, row := range rows {
+		poll, ok := polls[row.ChirpID]
+
+		if !ok {
+			poll = &pollResponse{
+				ClosesAt:   row.ClosesAt,
+				Closed:     !row.ClosesAt.After(now),
+				Options:    []pollOptionResponse{},
+				TotalVotes: new(int64),
+			}
+
+			if row.VotedPosition.Valid {
+				votedOption := row.VotedPosition.Int32
+				poll.VotedOption = &votedOption
+			}
+
+			polls[row.ChirpID] = poll
+		}
+
+		votes := row.VoteCount
+		*poll.TotalVotes += int64(votes)
+		poll.Options = append(poll.Options, pollOptionResponse{
+			Position: row.Position,
+			Label:    row.Label,
+			Votes:    &votes,
+		})
+	}
+
+	for _, poll := range polls {
+		if poll.Closed || poll.VotedOption != nil {
+			continue
+		}
+
+		poll.TotalVotes = nil
+
+		for i := range poll.Options {
+			poll.Options[i].Votes = nil
+		}
+	}
+
+	return polls, nil
+}
+
+func (config *apiConfig) votePoll(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	type votePollBody struct {
+		Option *int32 `json:"option"`
+	}
+
+	decodedPayload, decodeError := server.DecodeBody[votePollBody](req.Body)
+
+	if decodeError != nil || decodedPayload.Option == nil {
+		server.SendError("option is required", http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	chirp, shareError := config.shareableChirp(req.Context(), req.PathValue("id"))
+
+	if shareError != nil {
+		sendShareableChirpError(shareError, responseWriter)
+		return
+	}
+
+	if blockedError := checkNotBlocked(req.Context(), config.db, userUUID.String(), chirp.UserID); blockedError != nil {
+		sendBlockedError(blockedError, responseWriter)
+		return
+	}
+
+	// The vote row and the tally move together in one statement, and the
+	// primary key on poll_votes makes a second vote a no-op, so concurrent
+	// requests can neither double count nor lose a vote.
+	voted, voteError := config.db.VotePoll(req.Context(), database.VotePollParams{
+		UserID:   userUUID.String(),
+		ChirpID:  chirp.ID,
+		Position: *decodedPayload.Option,
+	})
+
+	if voteError != nil {
+		server.SendInternalServerError(voteError, responseWriter)
+		return
+	}
+
+	polls, loadError := config.loadPolls(req.Context(), userUUID.String(), []string{chirp.ID})
+
+	if loadError != nil {
+		server.SendInternalServerError(loadError, responseWriter)
+		return
+	}
+
+	poll, ok := polls[chirp.ID]
+
+	if voted == 0 {
+		var rejected error
+
+		switch {
+		case !ok:
+			rejected = errPollNotFound
+		case poll.VotedOption != nil:
+			rejected = errAlreadyVoted
+		case poll.Closed:
+			rejected = errPollClosed
+		default:
+			rejected = errPollOptionUnknown
+		}
+
+		sendVoteError(rejected, responseWriter)
+		return
+	}
+
+	server.ResponseWithJson(poll, http.StatusOK, responseWriter)
+}
+
+func sendVoteError(voteError error, responseWriter http.ResponseWriter) {
+	switch {
+	case errors.Is(voteError, errPollNotFound):
+		server.SendError(voteError.Error(), http.StatusNotFound, responseWriter)
+	case errors.Is(voteError, errPollOptionUnknown):
+		server.SendError(voteError.Error(), http.StatusBadRequest, responseWriter)
+	case errors.Is(voteError, errPollClosed), errors.Is(voteError, errAlreadyVoted):
+		server.SendError(voteError.Error(), http.StatusConflict, responseWriter)
+	default:
+		server.SendInternalServerError(voteError, responseWriter)
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+	"unicode/utf8"
+
+	"github.com/octaviocarpes/go-http-servers/internal/database"
+	server "github.com/octaviocarpes/go-http-servers/server"
+	utils "github.com/octaviocarpes/go-http-servers/utils"
+)
+
+const (
+	maxDisplayNameLength = 50
+	maxBioLength         = 160
+)
+
+// profileResponse is the public view of a user. It never includes the email.
+type profileResponse struct {
+	ID             string    `json:"id"`
+	Handle         *string   `json:"handle"`
+	DisplayName    string    `json:"display_name"`
+	Bio            string    `json:"bio"`
+	AvatarURL      string    `json:"avatar_url"`
+	IsChirpyRed    bool      `json:"is_chirpy_red"`
+	CreatedAt      time.Time `json:"created_at"`
+	ChirpCount     *int64    `json:"chirp_count,omitempty"`
+	FollowerCount  *int64    `json:"follower_count,omitempty"`
+	FollowingCount *int64    `json:"following_count,omitempty"`
+}
+
+func (config *apiConfig) getUserProfile(responseWriter http.ResponseWriter, req *http.Request) {
+	profile, getProfileError := config.db.GetUserProfile(req.Context(), req.PathValue("handle"))
+
+	if errors.Is(getProfileError, sql.ErrNoRows) {
+		server.SendError("user not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	if getProfileError != nil {
+		server.SendInternalServerError(getProfileError, responseWriter)
+		return
+	}
+
+	response := profileResponse{
+		ID:             profile.ID,
+		DisplayName:    profile.DisplayName,
+		Bio:            profile.Bio,
+		AvatarURL:      profile.AvatarUrl,
+		IsChirpyRed:    profile.IsChirpyRed.Bool,
+		CreatedAt:      profile.CreatedAt,
+		ChirpCount:     &profile.ChirpCount,
+		FollowerCount:  &profile.FollowerCount,
+		FollowingCount: &profile.FollowingCount,
+	}
+
+	if profile.Handle.Valid {
+		response.Handle = &profile.Handle.String
+	}
+
+	server.ResponseWithJson(response, http.StatusOK, responseWriter)
+}
+
+func (config *apiConfig) updateUserProfile(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	user, getUserError := config.db.GetUserByID(req.Context(), userUUID.String())
+
+	if getUserError != nil {
+		server.SendError("user not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	// Omitted fields keep their current value; an empty string clears them.
+	type updateProfileBody struct {
+		Handle      *string `json:"handle"`
+		DisplayName *string `json:"display_name"`
+		Bio         *string `json:"bio"`
+		AvatarURL   *string `json:"avatar_url"`
+	}
+
+	decodedPayload, decodeError := server.DecodeBody[updateProfileBody](req.Body)
+
+	if decodeError != nil {
+		server.SendError("invalid request body", http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	handle := user.Handle
+	displayName := user.DisplayName
+	bio := user.Bio
+	avatarURL := user.AvatarUrl
+
+	if decodedPayload.Handle != nil {
+		if !utils.IsValidHandle(*decodedPayload.Handle) {
+			server.SendError("handle must be 3 to 15 letters, digits or underscores", http.StatusBadRequest, responseWriter)
+			return
+		}
+
+		handle = sql.NullString{String: *decodedPayload.Handle, Valid: true}
+	}
+
+	if decodedPayload.DisplayName != nil {
+		if utf8.RuneCountInString(*decodedPayload.DisplayName) > maxDisplayNameLength {
+			server.SendError("display_name is too long", http.StatusBadRequest, responseWriter)
+			return
+		}
+
+		displayName = *decodedPayload.DisplayName
+	}
+
+	if decodedPayload.Bio != nil {
+		if utf8.RuneCountInString(*decodedPayload.Bio) > maxBioLength {
+			server.SendError("bio is too long", http.StatusBadRequest, responseWriter)
+			return
+		}
+
+		bio = *decodedPayload.Bio
+	}
+
+	if decodedPayload.AvatarURL != nil {
+		if len(*decodedPayload.AvatarURL) > 0 && !isHTTPURL(*decodedPayload.AvatarURL) {
+			server.SendError("avatar_url must be an http or https URL", http.StatusBadRequest, responseWriter)
+			return
+		}
+
+		avatarURL = *decodedPayload.AvatarURL
+	}
+
+	updated, updateError := config.db.UpdateUserProfile(req.Context(), database.UpdateUserProfileParams{
+		Handle:      handle,
+		DisplayName: displayName,
+		Bio:         bio,
+		AvatarUrl:   avatarURL,
+		ID:          user.ID,
+	})
+
+	if isUniqueViolation(updateError) {
+		server.SendError("handle is already taken", http.StatusConflict, responseWriter)
+		return
+	}
+
+	if updateError != nil {
+		server.SendInternalServerError(updateError, responseWriter)
+		return
+	}
+
+	response := profileResponse{
+		ID:          updated.ID,
+		DisplayName: updated.DisplayName,
+		Bio:         updated.Bio,
+		AvatarURL:   updated.AvatarUrl,
+		IsChirpyRed: updated.IsChirpyRed.Bool,
+		CreatedAt:   updated.CreatedAt,
+	}
+
+	if updated.Handle.Valid {
+		response.Handle = &updated.Handle.String
+	}
+
+	server.ResponseWithJson(response, http.StatusOK, responseWriter)
+}
+
+func isHTTPURL(raw string) bool {
+	parsed, parseError := url.Parse(raw)
+
+	if parseError != nil {
+		return false
+	}
+
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && len(parsed.Host) > 0
+}
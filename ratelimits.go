@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/octaviocarpes/go-http-servers/internal/database"
+	"github.com/octaviocarpes/go-http-servers/internal/ratelimit"
+	server "github.com/octaviocarpes/go-http-servers/server"
+)
+
+// chirpRatePolicyFor is the posting quota for user's tier.
+func (config *apiConfig) chirpRatePolicyFor(user database.User) ratelimit.Policy {
+	if user.IsChirpyRed.Bool {
+		return config.chirpyRedChirpRate
+	}
+
+	return config.chirpRate
+}
+
+// limitChirps takes a token from userID's posting bucket and sets the
+// RateLimit-* headers. When the bucket is empty it answers with 429 and
+// returns false. Every request that could create a chirp counts, including
+// the ones that fail validation later.
+func (config *apiConfig) limitChirps(responseWriter http.ResponseWriter, req *http.Request, userID string) bool {
+	user, getUserError := config.db.GetUserByID(req.Context(), userID)
+
+	// Deleted since the request was authenticated.
+	if errors.Is(getUserError, sql.ErrNoRows) {
+		server.SendError("Unauthorized", http.StatusUnauthorized, responseWriter)
+		return false
+	}
+
+	if getUserError != nil {
+		server.SendInternalServerError(getUserError, responseWriter)
+		return false
+	}
+
+	policy := config.chirpRatePolicyFor(user)
+
+	bucket, takeError := config.db.TakeRateLimitToken(req.Context(), database.TakeRateLimitTokenParams{
+		Key:        "chirps:" + userID,
+		Capacity:   float64(policy.Limit),
+		RefillRate: policy.RefillRate(),
+	})
+
+	if takeError != nil {
+		server.SendInternalServerError(takeError, responseWriter)
+		return false
+	}
+
+	decision := ratelimit.Decision{Policy: policy, Allowed: bucket.Allowed, Tokens: bucket.Tokens}
+	decision.WriteHeaders(responseWriter.Header())
+
+	if !decision.Allowed {
+		server.SendError("too many chirps, try again later", http.StatusTooManyRequests, responseWriter)
+		return false
+	}
+
+	return true
+}
+
+// limitInbox takes a token from the bucket of the server that signed an
+// inbox request, keyed by the host of its key ID. It runs before the
+// signature is checked, since checking it may mean fetching the signer's
+// actor.
+func (config *apiConfig) limitInbox(responseWriter http.ResponseWriter, req *http.Request, host string) bool {
+	bucket, takeError := config.db.TakeRateLimitToken(req.Context(), database.TakeRateLimitTokenParams{
+		Key:        "inbox:" + host,
+		Capacity:   float64(config.inboxRate.Limit),
+		RefillRate: config.inboxRate.RefillRate(),
+	})
+
+	if takeError != nil {
+		server.SendInternalServerError(takeError, responseWriter)
+		return false
+	}
+
+	decision := ratelimit.Decision{Policy: config.inboxRate, Allowed: bucket.Allowed, Tokens: bucket.Tokens}
+	decision.WriteHeaders(responseWriter.Header())
+
+	if !decision.Allowed {
+		server.SendError("too many requests from "+host+", try again later", http.StatusTooManyRequests, responseWriter)
+		return false
+	}
+
+	return true
+}
+
+// purgeRateLimitBuckets drops buckets that have been idle for longer than the
+// longest window. They would be full by now, which is what a missing bucket
+// means too.
+func (config *apiConfig) purgeRateLimitBuckets(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		idle := max(config.chirpRate.Window, config.chirpyRedChirpRate.Window, config.inboxRate.Window)
+
+		if _, purgeError := config.db.PurgeRateLimitBuckets(ctx, time.Now().Add(-idle)); purgeError != nil {
+			log.Printf("failed to purge rate limit buckets: %v\n", purgeError)
+		}
+	}
+}
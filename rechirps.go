@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/octaviocarpes/go-http-servers/internal/database"
+	server "github.com/octaviocarpes/go-http-servers/server"
+)
+
+var (
+	errChirpNotFound = errors.New("chirp not found")
+	errChirpDeleted  = errors.New("chirp was deleted")
+)
+
+// shareableChirp looks up the chirp a rechirp or quote should point at.
+// Sharing a rechirp shares the chirp it points at instead.
+func (config *apiConfig) shareableChirp(ctx context.Context, id string) (database.Chirp, error) {
+	chirp, getChirpError := config.db.GetChirpByID(ctx, id)
+
+	if getChirpError != nil || chirp.PublishAt.Valid {
+		return database.Chirp{}, errChirpNotFound
+	}
+
+	if chirp.RechirpOf.Valid {
+		return config.shareableChirp(ctx, chirp.RechirpOf.String)
+	}
+
+	if chirp.DeletedAt.Valid || chirp.HiddenAt.Valid {
+		return database.Chirp{}, errChirpDeleted
+	}
+
+	return chirp, nil
+}
+
+func sendShareableChirpError(shareError error, responseWriter http.ResponseWriter) {
+	switch {
+	case errors.Is(shareError, errChirpNotFound):
+		server.SendError(shareError.Error(), http.StatusNotFound, responseWriter)
+	case errors.Is(shareError, errChirpDeleted):
+		server.SendError(shareError.Error(), http.StatusGone, responseWriter)
+	default:
+		server.SendInternalServerError(shareError, responseWriter)
+	}
+}
+
+func (config *apiConfig) rechirp(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	original, shareError := config.shareableChirp(req.Context(), req.PathValue("id"))
+
+	if shareError != nil {
+		sendShareableChirpError(shareError, responseWriter)
+		return
+	}
+
+	if blockedError := checkNotBlocked(req.Context(), config.db, userUUID.String(), original.UserID); blockedError != nil {
+		sendBlockedError(blockedError, responseWriter)
+		return
+	}
+
+	params := database.CreateRechirpParams{
+		UserID:    userUUID.String(),
+		RechirpOf: sql.NullString{String: original.ID, Valid: true},
+	}
+
+	chirp, created, createRechirpError := config.insertRechirp(req.Context(), params)
+
+	if createRechirpError != nil {
+		server.SendInternalServerError(createRechirpError, responseWriter)
+		return
+	}
+
+	status := http.StatusOK
+
+	if created {
+		status = http.StatusCreated
+	}
+
+	response, buildResponseError := config.buildChirpResponse(req.Context(), audience{viewerID: userUUID.String()}, chirp)
+
+	if buildResponseError != nil {
+		server.SendInternalServerError(buildResponseError, responseWriter)
+		return
+	}
+
+	server.ResponseWithJson(response, status, responseWriter)
+}
+
+// insertRechirp creates a rechirp and fans it out to the sharer's followers
+// in one transaction, and reports whether it was created. A user who already
+// rechirped the chirp gets the existing rechirp back.
+func (config *apiConfig) insertRechirp(ctx context.Context, params database.CreateRechirpParams) (database.Chirp, bool, error) {
+	tx, beginError := config.conn.BeginTx(ctx, nil)
+
+	if beginError != nil {
+		return database.Chirp{}, false, beginError
+	}
+
+	defer tx.Rollback()
+
+	queries := config.db.WithTx(tx)
+
+	chirp, createRechirpError := queries.CreateRechirp(ctx, params)
+
+	// The insert is a no-op when the user already rechirped this chirp.
+	if errors.Is(createRechirpError, sql.ErrNoRows) {
+		chirp, getRechirpError := queries.GetRechirp(ctx, database.GetRechirpParams(params))
+		return chirp, false, getRechirpError
+	}
+
+	if createRechirpError != nil {
+		return database.Chirp{}, false, createRechirpError
+	}
+
+	if fanOutError := queries.FanOutChirp(ctx, chirp.ID); fanOutError != nil {
+		return database.Chirp{}, false, fanOutError
+	}
+
+	if touchError := queries.TouchChirpReferences(ctx, chirp.ID); touchError != nil {
+		return database.Chirp{}, false, touchError
+	}
+
+	return chirp, true, tx.Commit()
+}
+
+func (config *apiConfig) undoRechirp(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	deleted, deleteError := config.db.DeleteRechirp(req.Context(), database.DeleteRechirpParams{
+		UserID:    userUUID.String(),
+		RechirpOf: sql.NullString{String: req.PathValue("id"), Valid: true},
+	})
+
+	if deleteError != nil {
+		server.SendInternalServerError(deleteError, responseWriter)
+		return
+	}
+
+	if deleted == 0 {
+		server.SendError("rechirp not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	responseWriter.WriteHeader(http.StatusNoContent)
+}
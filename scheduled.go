@@ -0,0 +1,346 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/octaviocarpes/go-http-servers/internal/database"
+	server "github.com/octaviocarpes/go-http-servers/server"
+)
+
+const (
+	// A scheduled chirp that fails to publish is retried after a minute,
+	// doubling up to a day, while the chirps after it go out on time.
+	scheduledRetryBase = time.Minute
+	scheduledRetryMax  = 24 * time.Hour
+)
+
+func (config *apiConfig) listScheduledChirps(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	query := req.URL.Query()
+
+	limit, limitError := server.ParseLimit(query, 50, 200)
+
+	if limitError != nil {
+		server.SendError(limitError.Error(), http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	// Scheduled chirps are listed soonest first, so the cursor moves forward
+	// in time instead of backwards like the other feeds.
+	var afterPublishAt time.Time
+	var afterID string
+
+	if cursor := query.Get("cursor"); len(cursor) > 0 {
+		decodedPublishAt, decodedID, cursorError := server.DecodeTimeCursor(cursor)
+
+		if cursorError != nil {
+			server.SendError(cursorError.Error(), http.StatusBadRequest, responseWriter)
+			return
+		}
+
+		afterPublishAt, afterID = decodedPublishAt, decodedID
+	}
+
+	chirps, listError := config.db.ListScheduledChirps(req.Context(), database.ListScheduledChirpsParams{
+		UserID:         userUUID.String(),
+		AfterPublishAt: afterPublishAt,
+		AfterID:        afterID,
+		PageSize:       limit + 1,
+	})
+
+	if listError != nil {
+		server.SendInternalServerError(listError, responseWriter)
+		return
+	}
+
+	nextCursor := ""
+
+	if len(chirps) > int(limit) {
+		chirps = chirps[:limit]
+		last := chirps[len(chirps)-1]
+		nextCursor = server.EncodeTimeCursor(last.PublishAt.Time, last.ID)
+	}
+
+	chirpResponses, buildResponseError := config.buildChirpResponses(req.Context(), audience{viewerID: userUUID.String()}, chirps)
+
+	if buildResponseError != nil {
+		server.SendInternalServerError(buildResponseError, responseWriter)
+		return
+	}
+
+	type scheduledChirpsResponse struct {
+		Chirps     []chirpResponse `json:"chirps"`
+		NextCursor string          `json:"next_cursor,omitempty"`
+	}
+
+	server.ResponseWithJson(scheduledChirpsResponse{Chirps: chirpResponses, NextCursor: nextCursor}, http.StatusOK, responseWriter)
+}
+
+func (config *apiConfig) rescheduleChirp(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	type rescheduleChirpBody struct {
+		PublishAt *time.Time `json:"publish_at"`
+	}
+
+	decodedPayload, decodeError := server.DecodeBody[rescheduleChirpBody](req.Body)
+
+	if decodeError != nil {
+		server.SendError("invalid request body", http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	if decodedPayload.PublishAt == nil || !decodedPayload.PublishAt.After(time.Now()) {
+		server.SendError("publish_at must be in the future", http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	tx, beginError := config.conn.BeginTx(req.Context(), nil)
+
+	if beginError != nil {
+		server.SendInternalServerError(beginError, responseWriter)
+		return
+	}
+
+	defer tx.Rollback()
+
+	queries := config.db.WithTx(tx)
+
+	if !config.lockScheduledChirpIfMatch(responseWriter, req, queries, userUUID.String()) {
+		return
+	}
+
+	chirp, rescheduleError := queries.RescheduleChirp(req.Context(), database.RescheduleChirpParams{
+		PublishAt: sql.NullTime{Time: decodedPayload.PublishAt.UTC(), Valid: true},
+		ID:        req.PathValue("id"),
+		UserID:    userUUID.String(),
+	})
+
+	// Already published, cancelled or someone else's chirp.
+	if errors.Is(rescheduleError, sql.ErrNoRows) {
+		server.SendError("scheduled chirp not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	if rescheduleError != nil {
+		server.SendInternalServerError(rescheduleError, responseWriter)
+		return
+	}
+
+	// The poll was checked against the old publish time; it still has to
+	// close after the new one, and within the longest poll duration of it.
+	poll, getPollError := queries.GetPoll(req.Context(), chirp.ID)
+
+	if getPollError != nil && !errors.Is(getPollError, sql.ErrNoRows) {
+		server.SendInternalServerError(getPollError, responseWriter)
+		return
+	}
+
+	if getPollError == nil && (!poll.ClosesAt.After(chirp.PublishAt.Time) || poll.ClosesAt.After(chirp.PublishAt.Time.Add(maxPollDuration))) {
+		server.SendError(errPollClosesAt.Error(), http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	// A new time is a fresh start for a chirp that failed to publish.
+	if clearError := queries.ClearScheduledChirpFailure(req.Context(), chirp.ID); clearError != nil {
+		server.SendInternalServerError(clearError, responseWriter)
+		return
+	}
+
+	if commitError := tx.Commit(); commitError != nil {
+		server.SendInternalServerError(commitError, responseWriter)
+		return
+	}
+
+	response, buildResponseError := config.buildChirpResponse(req.Context(), audience{viewerID: userUUID.String()}, chirp)
+
+	if buildResponseError != nil {
+		server.SendInternalServerError(buildResponseError, responseWriter)
+		return
+	}
+
+	server.ResponseWithJson(response, http.StatusOK, responseWriter)
+}
+
+func (config *apiConfig) cancelScheduledChirp(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	tx, beginError := config.conn.BeginTx(req.Context(), nil)
+
+	if beginError != nil {
+		server.SendInternalServerError(beginError, responseWriter)
+		return
+	}
+
+	defer tx.Rollback()
+
+	queries := config.db.WithTx(tx)
+
+	if !config.lockScheduledChirpIfMatch(responseWriter, req, queries, userUUID.String()) {
+		return
+	}
+
+	// Deleted here while chirp_media still links them; if nothing is
+	// cancelled the rollback brings them back.
+	deletedMedia, deleteMediaError := queries.DeleteChirpMedia(req.Context(), req.PathValue("id"))
+
+	if deleteMediaError != nil {
+		server.SendInternalServerError(deleteMediaError, responseWriter)
+		return
+	}
+
+	cancelled, cancelError := queries.CancelScheduledChirp(req.Context(), database.CancelScheduledChirpParams{
+		ID:     req.PathValue("id"),
+		UserID: userUUID.String(),
+	})
+
+	if cancelError != nil {
+		server.SendInternalServerError(cancelError, responseWriter)
+		return
+	}
+
+	if cancelled == 0 {
+		server.SendError("scheduled chirp not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	if commitError := tx.Commit(); commitError != nil {
+		server.SendInternalServerError(commitError, responseWriter)
+		return
+	}
+
+	for _, medium := range deletedMedia {
+		config.deleteStoredMedia(req.Context(), medium.StorageKey, medium.ThumbnailKey)
+	}
+
+	responseWriter.WriteHeader(http.StatusNoContent)
+}
+
+// lockScheduledChirpIfMatch locks the scheduled chirp in the path for the
+// rest of the transaction and checks If-Match against it. Chirps that are not
+// userID's or no longer scheduled are left for the write to report as 404,
+// like they were before.
+func (config *apiConfig) lockScheduledChirpIfMatch(responseWriter http.ResponseWriter, req *http.Request, queries *database.Queries, userID string) bool {
+	chirp, getChirpError := queries.GetChirpForUpdate(req.Context(), req.PathValue("id"))
+
+	if errors.Is(getChirpError, sql.ErrNoRows) {
+		return true
+	}
+
+	if getChirpError != nil {
+		server.SendInternalServerError(getChirpError, responseWriter)
+		return false
+	}
+
+	if chirp.UserID != userID || !chirp.PublishAt.Valid {
+		return true
+	}
+
+	return config.checkChirpIfMatch(responseWriter, req, userID, chirp)
+}
+
+// publishScheduledChirps publishes chirps whose publish_at has passed. Every
+// instance runs it; ClaimDueChirp locks rows with SKIP LOCKED, so each chirp
+// is published exactly once no matter how many publishers are polling.
+func (config *apiConfig) publishScheduledChirps(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			published, publishError := config.publishNextScheduledChirp(ctx)
+
+			if publishError != nil {
+				log.Printf("failed to publish scheduled chirp: %v\n", publishError)
+				break
+			}
+
+			if !published {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishNextScheduledChirp publishes one due chirp in its own transaction and
+// reports whether there was one. The row lock is held until commit, so a
+// crash halfway leaves the chirp scheduled for the next run. A chirp that
+// cannot be published is recorded as failed and retried later, so it does
+// not stop the chirps due after it.
+func (config *apiConfig) publishNextScheduledChirp(ctx context.Context) (bool, error) {
+	tx, beginError := config.conn.BeginTx(ctx, nil)
+
+	if beginError != nil {
+		return false, beginError
+	}
+
+	defer tx.Rollback()
+
+	queries := config.db.WithTx(tx)
+
+	due, claimError := queries.ClaimDueChirp(ctx)
+
+	if errors.Is(claimError, sql.ErrNoRows) {
+		return false, nil
+	}
+
+	if claimError != nil {
+		return false, claimError
+	}
+
+	if publishError := publishChirp(ctx, queries, due.ID); publishError != nil {
+		tx.Rollback()
+		log.Printf("failed to publish scheduled chirp %s: %v\n", due.ID, publishError)
+
+		recordError := config.db.RecordScheduledChirpFailure(ctx, database.RecordScheduledChirpFailureParams{
+			ChirpID:          due.ID,
+			LastError:        publishError.Error(),
+			BaseDelaySeconds: scheduledRetryBase.Seconds(),
+			MaxDelaySeconds:  scheduledRetryMax.Seconds(),
+		})
+
+		return recordError == nil, recordError
+	}
+
+	return true, tx.Commit()
+}
+
+// publishChirp makes a scheduled chirp public and applies everything that
+// would have happened had it been posted now.
+func publishChirp(ctx context.Context, queries *database.Queries, chirpID string) error {
+	chirp, publishError := queries.PublishChirp(ctx, chirpID)
+
+	if publishError != nil {
+		return publishError
+	}
+
+	if effectsError := applyChirpEffects(ctx, queries, chirp); effectsError != nil {
+		return effectsError
+	}
+
+	return queries.ClearScheduledChirpFailure(ctx, chirpID)
+}
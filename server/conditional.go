@@ -0,0 +1,117 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ETag is a strong entity tag for a response body. Equal bodies get equal
+// tags, so a client holding the tag has exactly the bytes it stands for.
+func ETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// JsonETag is the ETag ResponseWithConditionalJson would send for payload.
+func JsonETag(payload any) (string, error) {
+	body, encodeError := json.Marshal(payload)
+
+	if encodeError != nil {
+		return "", encodeError
+	}
+
+	return ETag(body), nil
+}
+
+// ResponseWithConditionalJson is ResponseWithJson for a 200 that carries an
+// ETag and, when lastModified is set, a Last-Modified header. A request whose
+// validators still match gets 304 Not Modified without a body.
+func ResponseWithConditionalJson(payload any, lastModified time.Time, req *http.Request, responseWriter http.ResponseWriter) {
+	body, encodeError := json.Marshal(payload)
+
+	if encodeError != nil {
+		SendInternalServerError(payload, responseWriter)
+		return
+	}
+
+	ResponseWithConditional(body, "application/json", lastModified, req, responseWriter)
+}
+
+// ResponseWithConditional is ResponseWithConditionalJson for a body that is
+// already encoded as contentType.
+func ResponseWithConditional(body []byte, contentType string, lastModified time.Time, req *http.Request, responseWriter http.ResponseWriter) {
+	etag := ETag(body)
+	responseWriter.Header().Set("ETag", etag)
+
+	if !lastModified.IsZero() {
+		responseWriter.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if NotModified(req, etag, lastModified) {
+		responseWriter.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	responseWriter.Header().Set("Content-Type", contentType)
+	responseWriter.WriteHeader(http.StatusOK)
+	responseWriter.Write(body)
+}
+
+// NotModified evaluates If-None-Match and If-Modified-Since for a GET or
+// HEAD request. As RFC 9110 requires, If-Modified-Since is ignored when
+// If-None-Match is present.
+func NotModified(req *http.Request, etag string, lastModified time.Time) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+
+	if ifNoneMatch := req.Header.Get("If-None-Match"); len(ifNoneMatch) > 0 {
+		return matchesETag(ifNoneMatch, etag, false)
+	}
+
+	ifModifiedSince, parseError := http.ParseTime(req.Header.Get("If-Modified-Since"))
+
+	if parseError != nil || lastModified.IsZero() {
+		return false
+	}
+
+	return !lastModified.Truncate(time.Second).After(ifModifiedSince)
+}
+
+// IfMatch reports whether a request may change a resource whose current ETag
+// is etag. Requests without If-Match always may.
+func IfMatch(req *http.Request, etag string) bool {
+	ifMatch := req.Header.Get("If-Match")
+	return len(ifMatch) == 0 || matchesETag(ifMatch, etag, true)
+}
+
+// matchesETag compares etag against a header listing tags. If-Match uses the
+// strong comparison, which never matches a weak W/ tag; If-None-Match uses
+// the weak one.
+func matchesETag(header, etag string, strong bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+
+		if candidate == "*" {
+			return true
+		}
+
+		if weak, found := strings.CutPrefix(candidate, "W/"); found {
+			if strong {
+				continue
+			}
+
+			candidate = weak
+		}
+
+		if candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
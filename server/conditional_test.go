@@ -0,0 +1,91 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestResponseWithConditionalJson(t *testing.T) {
+	payload := map[string]string{"body": "hello"}
+	lastModified := time.Date(2024, 5, 1, 12, 30, 15, 500, time.UTC)
+
+	first := httptest.NewRecorder()
+	ResponseWithConditionalJson(payload, lastModified, httptest.NewRequest(http.MethodGet, "/api/chirps/1", nil), first)
+
+	etag := first.Header().Get("ETag")
+
+	if first.Code != http.StatusOK || len(etag) == 0 || first.Body.Len() == 0 {
+		t.Fatalf("first response = %d %q %q", first.Code, etag, first.Body.String())
+	}
+
+	if got := first.Header().Get("Last-Modified"); got != "Wed, 01 May 2024 12:30:15 GMT" {
+		t.Errorf("Last-Modified = %q", got)
+	}
+
+	cases := map[string]struct {
+		header, value string
+		want          int
+	}{
+		"matching etag":      {"If-None-Match", etag, http.StatusNotModified},
+		"weak matching etag": {"If-None-Match", `"other", W/` + etag, http.StatusNotModified},
+		"stale etag":         {"If-None-Match", `"other"`, http.StatusOK},
+		"not modified since": {"If-Modified-Since", "Wed, 01 May 2024 12:30:15 GMT", http.StatusNotModified},
+		"modified since":     {"If-Modified-Since", "Wed, 01 May 2024 12:30:14 GMT", http.StatusOK},
+		"unparsable date":    {"If-Modified-Since", "yesterday", http.StatusOK},
+	}
+
+	for name, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/api/chirps/1", nil)
+		req.Header.Set(tc.header, tc.value)
+
+		recorder := httptest.NewRecorder()
+		ResponseWithConditionalJson(payload, lastModified, req, recorder)
+
+		if recorder.Code != tc.want {
+			t.Errorf("%s: status = %d, want %d", name, recorder.Code, tc.want)
+		}
+
+		if recorder.Code == http.StatusNotModified && recorder.Body.Len() > 0 {
+			t.Errorf("%s: 304 has a body", name)
+		}
+	}
+
+	// If-None-Match wins over If-Modified-Since.
+	req := httptest.NewRequest(http.MethodGet, "/api/chirps/1", nil)
+	req.Header.Set("If-None-Match", `"other"`)
+	req.Header.Set("If-Modified-Since", "Wed, 01 May 2024 12:30:15 GMT")
+
+	recorder := httptest.NewRecorder()
+	ResponseWithConditionalJson(payload, lastModified, req, recorder)
+
+	if recorder.Code != http.StatusOK {
+		t.Errorf("If-None-Match did not take precedence: %d", recorder.Code)
+	}
+}
+
+func TestIfMatch(t *testing.T) {
+	etag := ETag([]byte("chirp"))
+
+	cases := map[string]bool{
+		"":             true,
+		etag:           true,
+		"*":            true,
+		`"a", ` + etag: true,
+		`"a"`:          false,
+		"W/" + etag:    false,
+	}
+
+	for header, want := range cases {
+		req := httptest.NewRequest(http.MethodDelete, "/api/chirps/1", nil)
+
+		if len(header) > 0 {
+			req.Header.Set("If-Match", header)
+		}
+
+		if got := IfMatch(req, etag); got != want {
+			t.Errorf("IfMatch(%q) = %v, want %v", header, got, want)
+		}
+	}
+}
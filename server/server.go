@@ -1,10 +1,16 @@
 package server
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 )
 
 func SendInternalServerError(payload any, responseWriter http.ResponseWriter) {
@@ -39,3 +45,84 @@ func DecodeBody[T any](body io.ReadCloser) (T, error) {
 
 	return payload, nil
 }
+
+func SendError(message string, status int, responseWriter http.ResponseWriter) {
+	payload := struct {
+		Error string `json:"error"`
+	}{
+		Error: message,
+	}
+
+	ResponseWithJson(payload, status, responseWriter)
+}
+
+func EncodeCursor(position string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(position))
+}
+
+func DecodeCursor(cursor string) (string, error) {
+	position, decodeError := base64.RawURLEncoding.DecodeString(cursor)
+
+	if decodeError != nil {
+		return "", errors.New("invalid cursor")
+	}
+
+	return string(position), nil
+}
+
+func ParseLimit(query url.Values, fallback, max int32) (int32, error) {
+	value := query.Get("limit")
+
+	if len(value) == 0 {
+		return fallback, nil
+	}
+
+	limit, parseError := strconv.ParseInt(value, 10, 32)
+
+	if parseError != nil || limit < 1 || int32(limit) > max {
+		return 0, fmt.Errorf("limit must be between 1 and %d", max)
+	}
+
+	return int32(limit), nil
+}
+
+// EncodeTimeCursor builds an opaque cursor for lists ordered by a timestamp,
+// using the id to break ties between rows created at the same instant.
+func EncodeTimeCursor(timestamp time.Time, id string) string {
+	return EncodeCursor(timestamp.Format(time.RFC3339Nano) + "|" + id)
+}
+
+func DecodeTimeCursor(cursor string) (time.Time, string, error) {
+	position, decodeError := DecodeCursor(cursor)
+
+	if decodeError != nil {
+		return time.Time{}, "", decodeError
+	}
+
+	rawTimestamp, id, found := strings.Cut(position, "|")
+
+	if !found {
+		return time.Time{}, "", errors.New("invalid cursor")
+	}
+
+	timestamp, parseError := time.Parse(time.RFC3339Nano, rawTimestamp)
+
+	if parseError != nil {
+		return time.Time{}, "", errors.New("invalid cursor")
+	}
+
+	return timestamp, id, nil
+}
+
+// ParseTimeCursor reads the cursor query param of a newest-first list. Without
+// a cursor it returns a position after every row so the first page starts at
+// the top.
+func ParseTimeCursor(query url.Values) (time.Time, string, error) {
+	cursor := query.Get("cursor")
+
+	if len(cursor) == 0 {
+		return time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC), "", nil
+	}
+
+	return DecodeTimeCursor(cursor)
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	auth "github.com/octaviocarpes/go-http-servers/internal/auth"
+	"github.com/octaviocarpes/go-http-servers/internal/broadcast"
+	"github.com/octaviocarpes/go-http-servers/internal/websocket"
+	server "github.com/octaviocarpes/go-http-servers/server"
+)
+
+// Channels a socket client can subscribe to. "user:<id>" carries one user's
+// chirps and "mentions" the chirps that mention the client.
+const (
+	socketChannelGlobal     = "global"
+	socketChannelMentions   = "mentions"
+	socketChannelUserPrefix = "user:"
+
+	socketMaxChannels     = 50
+	socketMaxMessageBytes = 4096
+	socketWriteWait       = 10 * time.Second
+)
+
+type socketClientMessage struct {
+	Type    string `json:"type"`
+	Channel string `json:"channel"`
+}
+
+// socketServerMessage is everything the server sends. Type is "subscribed",
+// "unsubscribed" or "error" in reply to the client, and the event type, such
+// as "chirp.created", for events on a channel.
+type socketServerMessage struct {
+	Type    string          `json:"type"`
+	Channel string          `json:"channel,omitempty"`
+	ID      int64           `json:"id,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// socketToken is the caller's JWT. Browsers cannot set headers on a
+// WebSocket handshake, so it may also come as the access_token parameter.
+func socketToken(req *http.Request) (string, error) {
+	if token := req.URL.Query().Get("access_token"); len(token) > 0 {
+		return token, nil
+	}
+
+	return auth.GetBearerToken(req.Header)
+}
+
+func validSocketChannel(channel string) bool {
+	if channel == socketChannelGlobal || channel == socketChannelMentions {
+		return true
+	}
+
+	userID, isUserChannel := strings.CutPrefix(channel, socketChannelUserPrefix)
+
+	if !isUserChannel {
+		return false
+	}
+
+	_, parseError := uuid.Parse(userID)
+	return parseError == nil
+}
+
+// socketChannelsFor lists which of the subscribed channels event belongs on,
+// as seen by viewer.
+func socketChannelsFor(event broadcast.Event, channels map[string]bool, viewer audience) []string {
+	if !viewer.canSee(event.AuthorID) {
+		return nil
+	}
+
+	matched := []string{}
+
+	if channels[socketChannelGlobal] {
+		matched = append(matched, socketChannelGlobal)
+	}
+
+	if channels[socketChannelUserPrefix+event.AuthorID] {
+		matched = append(matched, socketChannelUserPrefix+event.AuthorID)
+	}
+
+	if channels[socketChannelMentions] && slices.Contains(event.Mentions, viewer.viewerID) {
+		matched = append(matched, socketChannelMentions)
+	}
+
+	return matched
+}
+
+func writeSocketMessage(conn *websocket.Conn, message socketServerMessage) error {
+	encoded, encodeError := json.Marshal(message)
+
+	if encodeError != nil {
+		return encodeError
+	}
+
+	return conn.WriteMessage(websocket.TextMessage, encoded, time.Now().Add(socketWriteWait))
+}
+
+// chirpSocket is a WebSocket carrying the same events as the chirp stream,
+// split into channels the client subscribes to by sending
+// {"type": "subscribe", "channel": "global"}. The server pings every
+// socketPingInterval and hangs up on clients that stop answering, or that
+// fall so far behind that the hub drops them; those should reconnect.
+func (config *apiConfig) chirpSocket(responseWriter http.ResponseWriter, req *http.Request) {
+	token, getTokenErr := socketToken(req)
+
+	if getTokenErr != nil {
+		server.SendError("Unauthorized", http.StatusUnauthorized, responseWriter)
+		return
+	}
+
+	userUUID, invalidTokenError := auth.ValidateJWT(token, config.secret)
+
+	if invalidTokenError != nil {
+		server.SendError("Unauthorized", http.StatusUnauthorized, responseWriter)
+		return
+	}
+
+	viewer, audienceError := config.audienceFor(req.Context(), userUUID.String())
+
+	if audienceError != nil {
+		server.SendInternalServerError(audienceError, responseWriter)
+		return
+	}
+
+	conn, upgradeError := websocket.Upgrade(responseWriter, req)
+
+	if upgradeError != nil {
+		return
+	}
+
+	subscription, _, _ := config.chirpEvents.Subscribe(0, false)
+	defer config.chirpEvents.Unsubscribe(subscription)
+
+	pongWait := config.socketPingInterval + socketWriteWait
+
+	conn.SetReadLimit(socketMaxMessageBytes)
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func() {
+		conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	// Reading happens on its own goroutine so that this one can own the
+	// subscriptions and interleave replies with events.
+	commands := make(chan socketClientMessage)
+	readDone := make(chan error, 1)
+	stop := make(chan struct{})
+	defer close(stop)
+
+	go func() {
+		for {
+			messageType, message, readError := conn.ReadMessage()
+
+			if readError != nil {
+				readDone <- readError
+				return
+			}
+
+			var command socketClientMessage
+
+			if messageType != websocket.TextMessage || json.Unmarshal(message, &command) != nil {
+				command = socketClientMessage{}
+			}
+
+			select {
+			case commands <- command:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(config.socketPingInterval)
+	defer ping.Stop()
+
+	channels := map[string]bool{}
+
+	for {
+		select {
+		case readError := <-readDone:
+			var closeError *websocket.CloseError
+
+			if !errors.As(readError, &closeError) {
+				conn.Close(websocket.CloseGoingAway, "")
+			}
+
+			return
+		case command := <-commands:
+			reply := socketServerMessage{Channel: command.Channel}
+
+			switch {
+			case command.Type == "subscribe" && validSocketChannel(command.Channel):
+				if !channels[command.Channel] && len(channels) >= socketMaxChannels {
+					reply.Type, reply.Error = "error", "too many subscriptions"
+					break
+				}
+
+				channels[command.Channel] = true
+				reply.Type = "subscribed"
+			case command.Type == "unsubscribe" && validSocketChannel(command.Channel):
+				delete(channels, command.Channel)
+				reply.Type = "unsubscribed"
+			case command.Type == "subscribe" || command.Type == "unsubscribe":
+				reply.Type, reply.Error = "error", "unknown channel"
+			default:
+				reply.Type, reply.Error = "error", `messages must be {"type": "subscribe" or "unsubscribe", "channel": ...}`
+			}
+
+			if writeError := writeSocketMessage(conn, reply); writeError != nil {
+				conn.Close(websocket.CloseGoingAway, "")
+				return
+			}
+		case event, ok := <-subscription.Events:
+			// Dropped for falling behind, or the hub was reset.
+			if !ok {
+				conn.Close(websocket.CloseTryAgainLater, "reconnect")
+				return
+			}
+
+			for _, channel := range socketChannelsFor(event, channels, viewer) {
+				message := socketServerMessage{Type: event.Type, Channel: channel, ID: event.ID, Data: event.Data}
+
+				if writeError := writeSocketMessage(conn, message); writeError != nil {
+					conn.Close(websocket.CloseTryAgainLater, "fell behind, reconnect")
+					return
+				}
+			}
+		case <-ping.C:
+			if pingError := conn.WriteMessage(websocket.PingMessage, nil, time.Now().Add(socketWriteWait)); pingError != nil {
+				conn.Close(websocket.CloseGoingAway, "")
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/octaviocarpes/go-http-servers/internal/database"
+	server "github.com/octaviocarpes/go-http-servers/server"
+)
+
+type tombstoneResponse struct {
+	ID              string    `json:"id"`
+	Deleted         bool      `json:"deleted"`
+	DeletedAt       time.Time `json:"deleted_at"`
+	RestorableUntil time.Time `json:"restorable_until"`
+}
+
+func (config *apiConfig) newTombstoneResponse(chirp database.Chirp) tombstoneResponse {
+	return tombstoneResponse{
+		ID:              chirp.ID,
+		Deleted:         true,
+		DeletedAt:       chirp.DeletedAt.Time,
+		RestorableUntil: chirp.DeletedAt.Time.Add(config.retention),
+	}
+}
+
+// sendChirpTombstone answers requests for a soft-deleted chirp with 410 Gone,
+// keeping enough information around for clients to render a placeholder. The
+// ETag lets the author restore it with If-Match.
+func (config *apiConfig) sendChirpTombstone(chirp database.Chirp, responseWriter http.ResponseWriter) {
+	response := config.newTombstoneResponse(chirp)
+
+	if etag, etagError := server.JsonETag(response); etagError == nil {
+		responseWriter.Header().Set("ETag", etag)
+	}
+
+	server.ResponseWithJson(response, http.StatusGone, responseWriter)
+}
+
+func (config *apiConfig) restoreChirp(responseWriter http.ResponseWriter, req *http.Request) {
+	userUUID, ok := config.authenticate(responseWriter, req)
+
+	if !ok {
+		return
+	}
+
+	chirpID := req.PathValue("id")
+
+	tx, beginError := config.conn.BeginTx(req.Context(), nil)
+
+	if beginError != nil {
+		server.SendInternalServerError(beginError, responseWriter)
+		return
+	}
+
+	defer tx.Rollback()
+
+	queries := config.db.WithTx(tx)
+
+	chirp, getChirpError := queries.GetChirpForUpdate(req.Context(), chirpID)
+
+	if getChirpError != nil {
+		server.SendError("chirp not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	if chirp.UserID != userUUID.String() {
+		user, getUserError := config.db.GetUserByID(req.Context(), userUUID.String())
+
+		if getUserError != nil || !user.IsAdmin {
+			responseWriter.WriteHeader(http.StatusForbidden)
+			return
+		}
+	}
+
+	if !chirp.DeletedAt.Valid {
+		server.SendError("chirp is not deleted", http.StatusConflict, responseWriter)
+		return
+	}
+
+	if chirp.HiddenAt.Valid && chirp.UserID == userUUID.String() {
+		server.SendError("chirp was removed by a moderator", http.StatusForbidden, responseWriter)
+		return
+	}
+
+	if time.Since(chirp.DeletedAt.Time) > config.retention {
+		server.SendError("retention window has expired", http.StatusGone, responseWriter)
+		return
+	}
+
+	if !config.checkChirpIfMatch(responseWriter, req, userUUID.String(), chirp) {
+		return
+	}
+
+	restored, restoreError := queries.RestoreChirp(req.Context(), chirpID)
+
+	if errors.Is(restoreError, sql.ErrNoRows) {
+		server.SendError("chirp is not deleted", http.StatusConflict, responseWriter)
+		return
+	}
+
+	if restoreError != nil {
+		server.SendInternalServerError(restoreError, responseWriter)
+		return
+	}
+
+	if touchError := queries.TouchChirpReferences(req.Context(), chirpID); touchError != nil {
+		server.SendInternalServerError(touchError, responseWriter)
+		return
+	}
+
+	if commitError := tx.Commit(); commitError != nil {
+		server.SendInternalServerError(commitError, responseWriter)
+		return
+	}
+
+	response, buildResponseError := config.buildChirpResponse(req.Context(), audience{viewerID: userUUID.String()}, restored)
+
+	if buildResponseError != nil {
+		server.SendInternalServerError(buildResponseError, responseWriter)
+		return
+	}
+
+	server.ResponseWithJson(response, http.StatusOK, responseWriter)
+}
+
+// purgeDeletedRecords hard-deletes chirps and users whose retention window
+// has passed, along with their media. It runs until ctx is cancelled.
+func (config *apiConfig) purgeDeletedRecords(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		cutoff := sql.NullTime{Time: time.Now().Add(-config.retention), Valid: true}
+
+		// Media rows would outlive their chirps and users, and nothing else
+		// deletes the stored objects, so both go first.
+		chirpMedia, purgeChirpMediaError := config.db.PurgeDeletedChirpMedia(ctx, cutoff)
+
+		if purgeChirpMediaError != nil {
+			log.Printf("failed to purge media of deleted chirps: %v\n", purgeChirpMediaError)
+		}
+
+		for _, medium := range chirpMedia {
+			config.deleteStoredMedia(ctx, medium.StorageKey, medium.ThumbnailKey)
+		}
+
+		userMedia, purgeUserMediaError := config.db.PurgeDeletedUserMedia(ctx, cutoff)
+
+		if purgeUserMediaError != nil {
+			log.Printf("failed to purge media of deleted users: %v\n", purgeUserMediaError)
+		}
+
+		for _, medium := range userMedia {
+			config.deleteStoredMedia(ctx, medium.StorageKey, medium.ThumbnailKey)
+		}
+
+		purgedChirps, purgeChirpsError := config.db.PurgeDeletedChirps(ctx, cutoff)
+
+		if purgeChirpsError != nil {
+			log.Printf("failed to purge deleted chirps: %v\n", purgeChirpsError)
+		}
+
+		purgedUsers, purgeUsersError := config.purgeDeletedUsers(ctx, cutoff)
+
+		if purgeUsersError != nil {
+			log.Printf("failed to purge deleted users: %v\n", purgeUsersError)
+		}
+
+		if purgedChirps > 0 || purgedUsers > 0 {
+			log.Printf("purged %d chirps and %d users\n", purgedChirps, purgedUsers)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeDeletedUsers hard-deletes users deleted before cutoff. Their likes and
+// poll votes go with them, so the counts those added are taken back in the
+// same transaction.
+func (config *apiConfig) purgeDeletedUsers(ctx context.Context, cutoff sql.NullTime) (int64, error) {
+	tx, beginError := config.conn.BeginTx(ctx, nil)
+
+	if beginError != nil {
+		return 0, beginError
+	}
+
+	defer tx.Rollback()
+
+	queries := config.db.WithTx(tx)
+
+	if releaseError := queries.ReleasePurgedUserLikes(ctx, cutoff); releaseError != nil {
+		return 0, releaseError
+	}
+
+	if releaseError := queries.ReleasePurgedUserVotes(ctx, cutoff); releaseError != nil {
+		return 0, releaseError
+	}
+
+	purged, purgeError := queries.PurgeDeletedUsers(ctx, cutoff)
+
+	if purgeError != nil {
+		return 0, purgeError
+	}
+
+	return purged, tx.Commit()
+}
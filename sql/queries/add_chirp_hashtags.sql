@@ -0,0 +1,4 @@
+-- name: AddChirpHashtags :exec
+INSERT INTO chirp_hashtags (chirp_id, tag, created_at)
+SELECT sqlc.arg('chirp_id')::text, unnest(sqlc.arg('tags')::text[]), NOW()
+ON CONFLICT (chirp_id, tag) DO NOTHING;
@@ -0,0 +1,4 @@
+-- name: AddChirpMentions :exec
+INSERT INTO chirp_mentions (chirp_id, user_id)
+SELECT sqlc.arg('chirp_id')::text, unnest(sqlc.arg('user_ids')::text[])
+ON CONFLICT (chirp_id, user_id) DO NOTHING;
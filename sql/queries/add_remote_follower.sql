@@ -0,0 +1,7 @@
+-- name: AddRemoteFollower :exec
+INSERT INTO remote_followers (user_id, actor_url, inbox_url, shared_inbox_url, follow_id, created_at)
+VALUES (sqlc.arg('user_id'), sqlc.arg('actor_url'), sqlc.arg('inbox_url'), sqlc.arg('shared_inbox_url'), sqlc.arg('follow_id'), NOW())
+ON CONFLICT (user_id, actor_url) DO UPDATE
+SET inbox_url = excluded.inbox_url,
+    shared_inbox_url = excluded.shared_inbox_url,
+    follow_id = excluded.follow_id;
@@ -0,0 +1,7 @@
+-- name: AttachChirpMedia :execrows
+INSERT INTO chirp_media (chirp_id, media_id, position)
+SELECT sqlc.arg('chirp_id')::text, media.id, attached.position
+FROM unnest(sqlc.arg('media_ids')::text[]) WITH ORDINALITY AS attached(media_id, position)
+JOIN media ON media.id = attached.media_id
+WHERE media.user_id = sqlc.arg('user_id')::text
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,11 @@
+-- name: BackfillTimeline :exec
+INSERT INTO timeline_entries (user_id, chirp_id, author_id, created_at)
+SELECT sqlc.arg('user_id')::text, chirps.id, chirps.user_id, chirps.created_at
+FROM chirps
+WHERE chirps.user_id = sqlc.arg('author_id')
+    AND chirps.deleted_at IS NULL
+    AND chirps.hidden_at IS NULL
+    AND chirps.publish_at IS NULL
+ORDER BY chirps.created_at DESC
+LIMIT sqlc.arg('page_size')::int
+ON CONFLICT (user_id, chirp_id) DO NOTHING;
@@ -0,0 +1,10 @@
+-- name: BlockUser :exec
+WITH inserted AS (
+    INSERT INTO user_blocks (blocker_id, blocked_id, created_at)
+    VALUES ($1, $2, NOW())
+    ON CONFLICT (blocker_id, blocked_id) DO NOTHING
+    RETURNING blocker_id, blocked_id
+)
+UPDATE users
+SET audience_updated_at = NOW()
+WHERE id IN (SELECT blocker_id FROM inserted UNION SELECT blocked_id FROM inserted);
@@ -0,0 +1,9 @@
+-- name: CancelScheduledChirp :execrows
+WITH cancelled AS (
+    DELETE FROM chirps
+    WHERE id = $1 AND user_id = $2 AND publish_at IS NOT NULL
+    RETURNING user_id
+)
+UPDATE users
+SET audience_updated_at = NOW()
+WHERE id IN (SELECT user_id FROM cancelled);
@@ -0,0 +1,11 @@
+-- name: ClaimActivityDeliveries :many
+UPDATE activity_deliveries
+SET next_attempt_at = NOW() + make_interval(secs => sqlc.arg('lease_seconds')::float8)
+WHERE id IN (
+    SELECT id FROM activity_deliveries
+    WHERE next_attempt_at <= NOW()
+    ORDER BY next_attempt_at
+    LIMIT sqlc.arg('batch_size')::int
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
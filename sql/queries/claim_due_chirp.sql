@@ -0,0 +1,11 @@
+-- name: ClaimDueChirp :one
+SELECT * FROM chirps
+WHERE publish_at IS NOT NULL AND publish_at <= NOW() AND deleted_at IS NULL
+    AND NOT EXISTS (
+        SELECT 1 FROM scheduled_chirp_failures
+        WHERE scheduled_chirp_failures.chirp_id = chirps.id
+            AND scheduled_chirp_failures.next_attempt_at > NOW()
+    )
+ORDER BY publish_at, id
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
@@ -0,0 +1,12 @@
+-- name: ClaimIdempotencyKey :execrows
+INSERT INTO idempotency_keys (scope, key, request_hash, created_at, expires_at)
+VALUES (sqlc.arg('scope'), sqlc.arg('key'), sqlc.arg('request_hash'), NOW(), sqlc.arg('expires_at'))
+ON CONFLICT (scope, key) DO UPDATE
+SET request_hash = excluded.request_hash,
+    status_code = NULL,
+    response_headers = '{}',
+    response_body = '',
+    created_at = NOW(),
+    expires_at = excluded.expires_at
+WHERE idempotency_keys.expires_at < NOW()
+    OR (idempotency_keys.status_code IS NULL AND idempotency_keys.created_at < sqlc.arg('stale_before')::timestamp);
@@ -0,0 +1,2 @@
+-- name: ClearHashtagTrends :exec
+DELETE FROM hashtag_trends;
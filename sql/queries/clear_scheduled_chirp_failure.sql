@@ -0,0 +1,3 @@
+-- name: ClearScheduledChirpFailure :exec
+DELETE FROM scheduled_chirp_failures
+WHERE chirp_id = sqlc.arg('chirp_id');
@@ -0,0 +1,3 @@
+-- name: CompleteActivityDelivery :exec
+DELETE FROM activity_deliveries
+WHERE id = sqlc.arg('id');
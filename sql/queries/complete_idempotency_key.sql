@@ -0,0 +1,5 @@
+-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys
+SET status_code = sqlc.arg('status_code'), response_headers = sqlc.arg('response_headers'), response_body = sqlc.arg('response_body')
+WHERE scope = sqlc.arg('scope') AND key = sqlc.arg('key')
+    AND request_hash = sqlc.arg('request_hash') AND status_code IS NULL;
@@ -0,0 +1,5 @@
+-- name: CountOutboxChirps :one
+SELECT COUNT(*)
+FROM chirps
+WHERE user_id = sqlc.arg('user_id') AND rechirp_of IS NULL
+    AND deleted_at IS NULL AND hidden_at IS NULL AND publish_at IS NULL;
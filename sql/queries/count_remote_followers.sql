@@ -0,0 +1,3 @@
+-- name: CountRemoteFollowers :one
+SELECT COUNT(*) FROM remote_followers
+WHERE user_id = sqlc.arg('user_id');
@@ -0,0 +1,11 @@
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*) FROM notifications
+WHERE user_id = $1 AND read_at IS NULL
+    AND NOT EXISTS (
+        SELECT 1 FROM user_mutes
+        WHERE user_mutes.muter_id = notifications.user_id AND user_mutes.muted_id = notifications.actor_id
+    )
+    AND NOT EXISTS (
+        SELECT 1 FROM user_blocks
+        WHERE user_blocks.blocker_id = notifications.user_id AND user_blocks.blocked_id = notifications.actor_id
+    );
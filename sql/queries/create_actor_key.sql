@@ -0,0 +1,4 @@
+-- name: CreateActorKey :exec
+INSERT INTO actor_keys (user_id, public_key_pem, private_key_pem, created_at)
+VALUES (sqlc.arg('user_id'), sqlc.arg('public_key_pem'), sqlc.arg('private_key_pem'), NOW())
+ON CONFLICT (user_id) DO NOTHING;
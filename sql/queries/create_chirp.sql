@@ -1,9 +1,12 @@
 -- name: CreateChirp :one
-INSERT INTO chirps (id, user_id, body, created_at, updated_at)
+INSERT INTO chirps (id, user_id, body, in_reply_to, quote_of, publish_at, created_at, updated_at)
 VALUES (
     gen_random_uuid(),
     $1,
     $2,
+    $3,
+    $4,
+    $5,
     NOW(),
     NOW()
 )
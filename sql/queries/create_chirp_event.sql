@@ -0,0 +1,7 @@
+-- name: CreateChirpEvent :exec
+WITH event AS (
+    INSERT INTO chirp_events (type, chirp_id, author_id, created_at)
+    VALUES (sqlc.arg('type'), sqlc.arg('chirp_id'), sqlc.arg('author_id'), NOW())
+    RETURNING id
+)
+SELECT pg_notify('chirp_events', id::text) FROM event;
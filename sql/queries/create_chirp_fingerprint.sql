@@ -0,0 +1,3 @@
+-- name: CreateChirpFingerprint :exec
+INSERT INTO chirp_fingerprints (chirp_id, user_id, hash, simhash, created_at)
+VALUES ($1, $2, $3, $4, NOW());
@@ -0,0 +1,4 @@
+-- name: CreateContentFilterRule :one
+INSERT INTO content_filter_rules (id, pattern, action, created_at, updated_at)
+VALUES (gen_random_uuid(), $1, $2, NOW(), NOW())
+RETURNING *;
@@ -0,0 +1,16 @@
+-- name: CreateDraft :one
+INSERT INTO drafts (id, user_id, body, in_reply_to, quote_of, media_ids, publish_at, poll_options, poll_closes_at, created_at, updated_at)
+VALUES (
+    gen_random_uuid(),
+    sqlc.arg('user_id'),
+    sqlc.arg('body'),
+    sqlc.narg('in_reply_to'),
+    sqlc.narg('quote_of'),
+    sqlc.arg('media_ids')::text[],
+    sqlc.narg('publish_at'),
+    sqlc.narg('poll_options')::text[],
+    sqlc.narg('poll_closes_at'),
+    NOW(),
+    NOW()
+)
+RETURNING *;
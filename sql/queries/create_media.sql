@@ -0,0 +1,4 @@
+-- name: CreateMedia :one
+INSERT INTO media (id, user_id, content_type, byte_size, width, height, storage_key, thumbnail_key, thumbnail_content_type, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+RETURNING *;
@@ -0,0 +1,4 @@
+-- name: CreateModerationDecision :one
+INSERT INTO moderation_decisions (id, moderator_id, user_id, chirp_id, action, note, created_at)
+VALUES (gen_random_uuid(), sqlc.arg('moderator_id'), sqlc.arg('user_id'), sqlc.narg('chirp_id'), sqlc.arg('action'), sqlc.arg('note'), NOW())
+RETURNING *;
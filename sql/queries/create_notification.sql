@@ -0,0 +1,19 @@
+-- name: CreateNotification :exec
+INSERT INTO notifications (id, user_id, actor_id, type, chirp_id, created_at)
+SELECT gen_random_uuid(), sqlc.arg('user_id')::text, sqlc.arg('actor_id')::text, sqlc.arg('type')::text, sqlc.narg('chirp_id')::text, NOW()
+WHERE sqlc.arg('user_id')::text <> sqlc.arg('actor_id')::text
+    AND NOT EXISTS (
+        SELECT 1 FROM notification_preferences
+        WHERE notification_preferences.user_id = sqlc.arg('user_id')::text
+            AND notification_preferences.type = sqlc.arg('type')::text
+            AND NOT notification_preferences.enabled
+    )
+    AND NOT EXISTS (
+        SELECT 1 FROM user_blocks
+        WHERE (user_blocks.blocker_id = sqlc.arg('user_id')::text AND user_blocks.blocked_id = sqlc.arg('actor_id')::text)
+            OR (user_blocks.blocker_id = sqlc.arg('actor_id')::text AND user_blocks.blocked_id = sqlc.arg('user_id')::text)
+    )
+    AND NOT EXISTS (
+        SELECT 1 FROM user_mutes
+        WHERE user_mutes.muter_id = sqlc.arg('user_id')::text AND user_mutes.muted_id = sqlc.arg('actor_id')::text
+    );
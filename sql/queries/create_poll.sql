@@ -0,0 +1,9 @@
+-- name: CreatePoll :exec
+WITH poll AS (
+    INSERT INTO polls (chirp_id, closes_at)
+    VALUES (sqlc.arg('chirp_id'), sqlc.arg('closes_at'))
+    RETURNING chirp_id
+)
+INSERT INTO poll_options (chirp_id, position, label)
+SELECT poll.chirp_id, options.ordinality - 1, options.label
+FROM poll, unnest(sqlc.arg('options')::text[]) WITH ORDINALITY AS options(label, ordinality);
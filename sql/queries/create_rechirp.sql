@@ -0,0 +1,12 @@
+-- name: CreateRechirp :one
+INSERT INTO chirps (id, user_id, body, rechirp_of, created_at, updated_at)
+VALUES (
+    gen_random_uuid(),
+    $1,
+    '',
+    $2,
+    NOW(),
+    NOW()
+)
+ON CONFLICT (user_id, rechirp_of) WHERE rechirp_of IS NOT NULL AND deleted_at IS NULL DO NOTHING
+RETURNING *;
@@ -0,0 +1,4 @@
+-- name: CreateReport :one
+INSERT INTO reports (id, reporter_id, user_id, chirp_id, reason, details, created_at)
+VALUES (gen_random_uuid(), sqlc.arg('reporter_id'), sqlc.arg('user_id'), sqlc.narg('chirp_id'), sqlc.arg('reason'), sqlc.arg('details'), NOW())
+RETURNING *;
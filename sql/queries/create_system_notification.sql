@@ -0,0 +1,10 @@
+-- name: CreateSystemNotification :exec
+INSERT INTO notifications (id, user_id, actor_id, type, chirp_id, created_at)
+SELECT gen_random_uuid(), sqlc.arg('user_id')::text, NULL, sqlc.arg('type')::text, sqlc.narg('chirp_id')::text, NOW()
+WHERE EXISTS (SELECT 1 FROM users WHERE users.id = sqlc.arg('user_id')::text)
+    AND NOT EXISTS (
+        SELECT 1 FROM notification_preferences
+        WHERE notification_preferences.user_id = sqlc.arg('user_id')::text
+            AND notification_preferences.type = sqlc.arg('type')::text
+            AND NOT notification_preferences.enabled
+    );
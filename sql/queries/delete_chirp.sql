@@ -1,3 +1,4 @@
 -- name: DeleteChirp :exec
-DELETE FROM chirps
-WHERE id = $1 AND user_id = $2;
+UPDATE chirps
+SET deleted_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL;
@@ -0,0 +1,6 @@
+-- name: DeleteChirpMedia :many
+DELETE FROM media
+USING chirp_media
+WHERE chirp_media.media_id = media.id
+    AND chirp_media.chirp_id = sqlc.arg('chirp_id')
+RETURNING media.storage_key, media.thumbnail_key;
@@ -0,0 +1,3 @@
+-- name: DeleteContentFilterRule :execrows
+DELETE FROM content_filter_rules
+WHERE id = $1;
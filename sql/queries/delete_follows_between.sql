@@ -0,0 +1,4 @@
+-- name: DeleteFollowsBetween :exec
+DELETE FROM follows
+WHERE (follower_id = sqlc.arg('user_id') AND followee_id = sqlc.arg('other_id'))
+    OR (follower_id = sqlc.arg('other_id') AND followee_id = sqlc.arg('user_id'));
@@ -0,0 +1,10 @@
+-- name: DeleteRechirp :execrows
+WITH deleted AS (
+    UPDATE chirps
+    SET deleted_at = NOW(), updated_at = NOW()
+    WHERE user_id = $1 AND rechirp_of = $2 AND deleted_at IS NULL
+    RETURNING rechirp_of
+)
+UPDATE chirps
+SET updated_at = NOW()
+WHERE id IN (SELECT rechirp_of FROM deleted);
@@ -0,0 +1,3 @@
+-- name: EnqueueActivityDelivery :exec
+INSERT INTO activity_deliveries (id, user_id, inbox_url, activity, object_id, object_actor, next_attempt_at, created_at)
+VALUES (gen_random_uuid(), sqlc.arg('user_id'), sqlc.arg('inbox_url'), sqlc.arg('activity'), sqlc.arg('object_id'), sqlc.arg('object_actor'), NOW(), NOW());
@@ -0,0 +1,8 @@
+-- name: EnqueueFollowerDeliveries :exec
+INSERT INTO activity_deliveries (id, user_id, inbox_url, activity, object_id, next_attempt_at, created_at)
+SELECT gen_random_uuid(), sqlc.arg('user_id'), inboxes.inbox_url, sqlc.arg('activity'), sqlc.arg('object_id'), NOW(), NOW()
+FROM (
+    SELECT DISTINCT COALESCE(NULLIF(shared_inbox_url, ''), inbox_url) AS inbox_url
+    FROM remote_followers
+    WHERE user_id = sqlc.arg('user_id')
+) AS inboxes;
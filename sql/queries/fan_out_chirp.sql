@@ -0,0 +1,8 @@
+-- name: FanOutChirp :exec
+INSERT INTO timeline_entries (user_id, chirp_id, author_id, created_at)
+SELECT follows.follower_id, chirps.id, chirps.user_id, chirps.created_at
+FROM chirps
+JOIN follows ON follows.followee_id = chirps.user_id
+WHERE chirps.id = $1
+ON CONFLICT (user_id, chirp_id) DO UPDATE
+SET created_at = excluded.created_at;
@@ -0,0 +1,4 @@
+-- name: FlagChirp :exec
+INSERT INTO chirp_flags (chirp_id, words, reasons, created_at)
+VALUES (sqlc.arg('chirp_id'), sqlc.arg('words')::text[], sqlc.arg('reasons')::text[], NOW())
+ON CONFLICT (chirp_id) DO NOTHING;
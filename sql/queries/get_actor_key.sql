@@ -0,0 +1,3 @@
+-- name: GetActorKey :one
+SELECT * FROM actor_keys
+WHERE user_id = sqlc.arg('user_id');
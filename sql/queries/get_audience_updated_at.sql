@@ -0,0 +1,3 @@
+-- name: GetAudienceUpdatedAt :one
+SELECT audience_updated_at FROM users
+WHERE id = sqlc.arg('id');
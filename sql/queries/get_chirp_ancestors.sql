@@ -0,0 +1,13 @@
+-- name: GetChirpAncestors :many
+WITH RECURSIVE ancestors AS (
+    SELECT parent.*, 1 AS distance
+    FROM chirps parent
+    WHERE parent.id = (SELECT c.in_reply_to FROM chirps c WHERE c.id = sqlc.arg('chirp_id')::text)
+    UNION ALL
+    SELECT parent.*, a.distance + 1
+    FROM chirps parent
+    JOIN ancestors a ON parent.id = a.in_reply_to
+)
+SELECT id, body, user_id, created_at, updated_at, deleted_at, in_reply_to, rechirp_of, quote_of, like_count, publish_at, hidden_at
+FROM ancestors
+ORDER BY distance DESC;
@@ -0,0 +1,3 @@
+-- name: GetChirpEventBounds :one
+SELECT COALESCE(MIN(id), 0)::bigint AS oldest_id, COALESCE(MAX(id), 0)::bigint AS latest_id
+FROM chirp_events;
@@ -0,0 +1,4 @@
+-- name: GetChirpsLastModified :one
+SELECT MAX(updated_at)::timestamp AS last_modified
+FROM chirps
+WHERE user_id = COALESCE(sqlc.narg('author_id'), user_id);
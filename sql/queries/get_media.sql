@@ -0,0 +1,3 @@
+-- name: GetMedia :one
+SELECT * FROM media
+WHERE id = $1;
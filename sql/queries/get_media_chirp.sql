@@ -0,0 +1,5 @@
+-- name: GetMediaChirp :one
+SELECT sqlc.embed(chirps)
+FROM chirp_media
+JOIN chirps ON chirps.id = chirp_media.chirp_id
+WHERE chirp_media.media_id = sqlc.arg('media_id');
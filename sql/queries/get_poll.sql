@@ -0,0 +1,3 @@
+-- name: GetPoll :one
+SELECT * FROM polls
+WHERE chirp_id = sqlc.arg('chirp_id');
@@ -0,0 +1,3 @@
+-- name: GetRechirp :one
+SELECT * FROM chirps
+WHERE user_id = $1 AND rechirp_of = $2 AND deleted_at IS NULL;
@@ -0,0 +1,3 @@
+-- name: GetReport :one
+SELECT * FROM reports
+WHERE id = $1;
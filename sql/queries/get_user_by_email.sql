@@ -1,3 +1,3 @@
 -- name: GetUserByEmail :one
 SELECT * FROM users
-WHERE email = $1;
+WHERE email = $1 AND deleted_at IS NULL;
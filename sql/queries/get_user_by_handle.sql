@@ -0,0 +1,3 @@
+-- name: GetUserByHandle :one
+SELECT * FROM users
+WHERE lower(handle) = lower(sqlc.arg('handle')) AND deleted_at IS NULL;
@@ -1,3 +1,3 @@
 -- name: GetUserByID :one
 SELECT * FROM users
-WHERE id = $1;
+WHERE id = $1 AND deleted_at IS NULL;
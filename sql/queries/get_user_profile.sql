@@ -0,0 +1,16 @@
+-- name: GetUserProfile :one
+SELECT users.id,
+    users.handle,
+    users.display_name,
+    users.bio,
+    users.avatar_url,
+    users.is_chirpy_red,
+    users.created_at,
+    (
+        SELECT COUNT(*) FROM chirps
+        WHERE chirps.user_id = users.id AND chirps.deleted_at IS NULL AND chirps.hidden_at IS NULL AND chirps.publish_at IS NULL
+    ) AS chirp_count,
+    (SELECT COUNT(*) FROM follows WHERE follows.followee_id = users.id) AS follower_count,
+    (SELECT COUNT(*) FROM follows WHERE follows.follower_id = users.id) AS following_count
+FROM users
+WHERE lower(users.handle) = lower(sqlc.arg('handle')) AND users.deleted_at IS NULL;
@@ -0,0 +1,4 @@
+-- name: HideChirp :exec
+UPDATE chirps
+SET hidden_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND hidden_at IS NULL;
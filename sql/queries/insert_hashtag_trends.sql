@@ -0,0 +1,12 @@
+-- name: InsertHashtagTrends :exec
+INSERT INTO hashtag_trends (tag, score, uses, refreshed_at)
+SELECT chirp_hashtags.tag,
+    SUM(EXP(-LN(2) * EXTRACT(EPOCH FROM (NOW() - chirp_hashtags.created_at)) / sqlc.arg('half_life_seconds')::float8)) AS score,
+    COUNT(*) AS uses,
+    NOW()
+FROM chirp_hashtags
+JOIN chirps ON chirps.id = chirp_hashtags.chirp_id
+WHERE chirp_hashtags.created_at > NOW() - make_interval(secs => sqlc.arg('window_seconds')::float8)
+    AND chirps.deleted_at IS NULL
+    AND chirps.hidden_at IS NULL
+GROUP BY chirp_hashtags.tag;
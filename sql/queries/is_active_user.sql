@@ -0,0 +1,5 @@
+-- name: IsActiveUser :one
+SELECT EXISTS (
+    SELECT 1 FROM users
+    WHERE id = $1 AND deleted_at IS NULL
+);
@@ -0,0 +1,10 @@
+-- name: LikeChirp :execrows
+WITH inserted AS (
+    INSERT INTO chirp_likes (chirp_id, user_id, created_at)
+    VALUES ($1, $2, NOW())
+    ON CONFLICT (chirp_id, user_id) DO NOTHING
+    RETURNING chirp_id
+)
+UPDATE chirps
+SET like_count = like_count + 1, updated_at = NOW()
+WHERE id IN (SELECT chirp_id FROM inserted);
@@ -0,0 +1,8 @@
+-- name: ListAuthorFingerprints :many
+SELECT chirp_fingerprints.user_id, chirp_fingerprints.hash, chirp_fingerprints.simhash,
+    (chirps.deleted_at IS NOT NULL OR chirps.hidden_at IS NOT NULL)::boolean AS removed
+FROM chirp_fingerprints
+JOIN chirps ON chirps.id = chirp_fingerprints.chirp_id
+WHERE chirp_fingerprints.user_id = sqlc.arg('user_id') AND chirp_fingerprints.created_at > sqlc.arg('since')::timestamp
+ORDER BY chirp_fingerprints.created_at DESC
+LIMIT sqlc.arg('page_size')::int;
@@ -0,0 +1,8 @@
+-- name: ListBlocks :many
+SELECT users.id, users.handle, users.display_name, user_blocks.created_at
+FROM user_blocks
+JOIN users ON users.id = user_blocks.blocked_id
+WHERE user_blocks.blocker_id = sqlc.arg('user_id')
+    AND (user_blocks.created_at, user_blocks.blocked_id) < (sqlc.arg('before_created_at')::timestamp, sqlc.arg('before_user_id')::text)
+ORDER BY user_blocks.created_at DESC, user_blocks.blocked_id DESC
+LIMIT sqlc.arg('page_size')::int;
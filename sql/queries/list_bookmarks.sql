@@ -0,0 +1,8 @@
+-- name: ListBookmarks :many
+SELECT sqlc.embed(chirps), bookmarks.created_at AS bookmarked_at
+FROM bookmarks
+JOIN chirps ON chirps.id = bookmarks.chirp_id
+WHERE bookmarks.user_id = sqlc.arg('user_id')
+    AND (bookmarks.created_at, bookmarks.chirp_id) < (sqlc.arg('before_created_at')::timestamp, sqlc.arg('before_chirp_id')::text)
+ORDER BY bookmarks.created_at DESC, bookmarks.chirp_id DESC
+LIMIT sqlc.arg('page_size')::int;
@@ -0,0 +1,20 @@
+-- name: ListChirpDescendants :many
+WITH RECURSIVE descendants AS (
+    SELECT c.*,
+        1 AS depth,
+        (to_char(c.created_at, 'YYYYMMDDHH24MISSUS') || c.id)::text AS path
+    FROM chirps c
+    WHERE c.in_reply_to = sqlc.arg('root_id')::text AND c.publish_at IS NULL
+    UNION ALL
+    SELECT c.*,
+        d.depth + 1,
+        (d.path || '/' || to_char(c.created_at, 'YYYYMMDDHH24MISSUS') || c.id)::text
+    FROM chirps c
+    JOIN descendants d ON c.in_reply_to = d.id
+    WHERE d.depth < sqlc.arg('max_depth')::int AND c.publish_at IS NULL
+)
+SELECT id, body, user_id, created_at, updated_at, deleted_at, in_reply_to, rechirp_of, quote_of, like_count, publish_at, hidden_at, depth, path
+FROM descendants
+WHERE path > sqlc.arg('after_path')::text
+ORDER BY path
+LIMIT sqlc.arg('page_size')::int;
@@ -0,0 +1,5 @@
+-- name: ListChirpEventsAfter :many
+SELECT * FROM chirp_events
+WHERE id > sqlc.arg('after_id')
+ORDER BY id
+LIMIT sqlc.arg('page_size')::int;
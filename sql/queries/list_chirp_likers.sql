@@ -0,0 +1,7 @@
+-- name: ListChirpLikers :many
+SELECT user_id, created_at
+FROM chirp_likes
+WHERE chirp_id = sqlc.arg('chirp_id')
+    AND (created_at, user_id) < (sqlc.arg('before_created_at')::timestamp, sqlc.arg('before_user_id')::text)
+ORDER BY created_at DESC, user_id DESC
+LIMIT sqlc.arg('page_size')::int;
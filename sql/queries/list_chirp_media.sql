@@ -0,0 +1,6 @@
+-- name: ListChirpMedia :many
+SELECT chirp_media.chirp_id, sqlc.embed(media)
+FROM chirp_media
+JOIN media ON media.id = chirp_media.media_id
+WHERE chirp_media.chirp_id = ANY(sqlc.arg('chirp_ids')::text[])
+ORDER BY chirp_media.chirp_id, chirp_media.position;
@@ -0,0 +1,3 @@
+-- name: ListChirpMentions :many
+SELECT user_id FROM chirp_mentions
+WHERE chirp_id = sqlc.arg('chirp_id');
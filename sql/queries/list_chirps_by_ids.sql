@@ -0,0 +1,3 @@
+-- name: ListChirpsByIDs :many
+SELECT * FROM chirps
+WHERE id = ANY(sqlc.arg('ids')::text[]);
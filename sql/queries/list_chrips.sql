@@ -1,7 +1,9 @@
 -- name: ListChirps :many
 SELECT *
 FROM chirps
-WHERE user_id = COALESCE(sqlc.narg('author_id'), user_id)
+WHERE user_id = COALESCE(sqlc.narg('author_id'), user_id) AND deleted_at IS NULL AND hidden_at IS NULL
+    AND NOT (user_id = ANY(sqlc.arg('hidden_author_ids')::text[]))
+    AND (publish_at IS NULL OR user_id = sqlc.arg('viewer_id')::text)
 ORDER BY
     CASE WHEN $1 THEN created_at END ASC,
     CASE WHEN $1 = FALSE THEN created_at END DESC;
@@ -0,0 +1,3 @@
+-- name: ListContentFilterRules :many
+SELECT * FROM content_filter_rules
+ORDER BY pattern;
@@ -0,0 +1,6 @@
+-- name: ListDrafts :many
+SELECT * FROM drafts
+WHERE user_id = sqlc.arg('user_id')
+    AND (updated_at, id) < (sqlc.arg('before_updated_at')::timestamp, sqlc.arg('before_id')::text)
+ORDER BY updated_at DESC, id DESC
+LIMIT sqlc.arg('page_size')::int;
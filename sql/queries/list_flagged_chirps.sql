@@ -0,0 +1,7 @@
+-- name: ListFlaggedChirps :many
+SELECT sqlc.embed(chirps), chirp_flags.words, chirp_flags.reasons, chirp_flags.created_at AS flagged_at
+FROM chirp_flags
+JOIN chirps ON chirps.id = chirp_flags.chirp_id
+WHERE (chirp_flags.created_at, chirp_flags.chirp_id) < (sqlc.arg('before_created_at')::timestamp, sqlc.arg('before_chirp_id')::text)
+ORDER BY chirp_flags.created_at DESC, chirp_flags.chirp_id DESC
+LIMIT sqlc.arg('page_size')::int;
@@ -0,0 +1,9 @@
+-- name: ListFollowers :many
+SELECT users.id, users.handle, users.display_name, follows.created_at AS followed_at
+FROM follows
+JOIN users ON users.id = follows.follower_id
+WHERE follows.followee_id = sqlc.arg('user_id')
+    AND users.deleted_at IS NULL
+    AND (follows.created_at, follows.follower_id) < (sqlc.arg('before_created_at')::timestamp, sqlc.arg('before_user_id')::text)
+ORDER BY follows.created_at DESC, follows.follower_id DESC
+LIMIT sqlc.arg('page_size')::int;
@@ -0,0 +1,11 @@
+-- name: ListHashtagChirps :many
+SELECT sqlc.embed(chirps)
+FROM chirp_hashtags
+JOIN chirps ON chirps.id = chirp_hashtags.chirp_id
+WHERE chirp_hashtags.tag = sqlc.arg('tag')
+    AND chirps.deleted_at IS NULL
+    AND chirps.hidden_at IS NULL
+    AND NOT (chirps.user_id = ANY(sqlc.arg('hidden_author_ids')::text[]))
+    AND (chirp_hashtags.created_at, chirp_hashtags.chirp_id) < (sqlc.arg('before_created_at')::timestamp, sqlc.arg('before_chirp_id')::text)
+ORDER BY chirp_hashtags.created_at DESC, chirp_hashtags.chirp_id DESC
+LIMIT sqlc.arg('page_size')::int;
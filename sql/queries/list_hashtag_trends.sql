@@ -0,0 +1,4 @@
+-- name: ListHashtagTrends :many
+SELECT * FROM hashtag_trends
+ORDER BY score DESC, tag
+LIMIT $1;
@@ -0,0 +1,6 @@
+-- name: ListHiddenAuthors :many
+SELECT blocked_id AS user_id, TRUE AS blocked FROM user_blocks WHERE blocker_id = $1
+UNION
+SELECT blocker_id, TRUE FROM user_blocks WHERE blocked_id = $1
+UNION
+SELECT muted_id, FALSE FROM user_mutes WHERE muter_id = $1;
@@ -0,0 +1,7 @@
+-- name: ListLatestChirps :many
+SELECT *
+FROM chirps
+WHERE user_id = COALESCE(sqlc.narg('author_id'), user_id)
+    AND deleted_at IS NULL AND hidden_at IS NULL AND publish_at IS NULL
+ORDER BY created_at DESC
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,5 @@
+-- name: ListMediaForAttach :many
+SELECT media.id, media.user_id, chirp_media.chirp_id IS NOT NULL AS attached
+FROM media
+LEFT JOIN chirp_media ON chirp_media.media_id = media.id
+WHERE media.id = ANY(sqlc.arg('ids')::text[]);
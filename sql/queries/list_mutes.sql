@@ -0,0 +1,8 @@
+-- name: ListMutes :many
+SELECT users.id, users.handle, users.display_name, user_mutes.created_at
+FROM user_mutes
+JOIN users ON users.id = user_mutes.muted_id
+WHERE user_mutes.muter_id = sqlc.arg('user_id')
+    AND (user_mutes.created_at, user_mutes.muted_id) < (sqlc.arg('before_created_at')::timestamp, sqlc.arg('before_user_id')::text)
+ORDER BY user_mutes.created_at DESC, user_mutes.muted_id DESC
+LIMIT sqlc.arg('page_size')::int;
@@ -0,0 +1,15 @@
+-- name: ListNotifications :many
+SELECT * FROM notifications
+WHERE user_id = sqlc.arg('user_id')
+    AND (NOT sqlc.arg('unread_only')::bool OR read_at IS NULL)
+    AND NOT EXISTS (
+        SELECT 1 FROM user_mutes
+        WHERE user_mutes.muter_id = notifications.user_id AND user_mutes.muted_id = notifications.actor_id
+    )
+    AND NOT EXISTS (
+        SELECT 1 FROM user_blocks
+        WHERE user_blocks.blocker_id = notifications.user_id AND user_blocks.blocked_id = notifications.actor_id
+    )
+    AND (created_at, id) < (sqlc.arg('before_created_at')::timestamp, sqlc.arg('before_id')::text)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('page_size')::int;
@@ -0,0 +1,8 @@
+-- name: ListOpenReports :many
+SELECT * FROM reports
+WHERE decision_id IS NULL
+    AND EXISTS (SELECT 1 FROM users WHERE users.id = reports.user_id)
+    AND (chirp_id IS NULL OR EXISTS (SELECT 1 FROM chirps WHERE chirps.id = reports.chirp_id))
+    AND (created_at, id) > (sqlc.arg('after_created_at')::timestamp, sqlc.arg('after_id')::text)
+ORDER BY created_at, id
+LIMIT sqlc.arg('page_size')::int;
@@ -0,0 +1,7 @@
+-- name: ListOutboxChirps :many
+SELECT *
+FROM chirps
+WHERE user_id = sqlc.arg('user_id') AND rechirp_of IS NULL
+    AND deleted_at IS NULL AND hidden_at IS NULL AND publish_at IS NULL
+ORDER BY created_at DESC
+LIMIT sqlc.arg('limit');
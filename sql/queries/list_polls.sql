@@ -0,0 +1,8 @@
+-- name: ListPolls :many
+SELECT polls.chirp_id, polls.closes_at, poll_options.position, poll_options.label, poll_options.vote_count,
+    poll_votes.position AS voted_position
+FROM polls
+JOIN poll_options ON poll_options.chirp_id = polls.chirp_id
+LEFT JOIN poll_votes ON poll_votes.chirp_id = polls.chirp_id AND poll_votes.user_id = sqlc.arg('user_id')::text
+WHERE polls.chirp_id = ANY(sqlc.arg('chirp_ids')::text[])
+ORDER BY polls.chirp_id, poll_options.position;
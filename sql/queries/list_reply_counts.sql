@@ -0,0 +1,5 @@
+-- name: ListReplyCounts :many
+SELECT in_reply_to::text AS chirp_id, COUNT(*) AS reply_count
+FROM chirps
+WHERE in_reply_to = ANY(sqlc.arg('chirp_ids')::text[]) AND deleted_at IS NULL AND hidden_at IS NULL AND publish_at IS NULL
+GROUP BY in_reply_to;
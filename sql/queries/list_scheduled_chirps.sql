@@ -0,0 +1,8 @@
+-- name: ListScheduledChirps :many
+SELECT * FROM chirps
+WHERE user_id = sqlc.arg('user_id')
+    AND publish_at IS NOT NULL
+    AND deleted_at IS NULL
+    AND (publish_at, id) > (sqlc.arg('after_publish_at')::timestamp, sqlc.arg('after_id')::text)
+ORDER BY publish_at, id
+LIMIT sqlc.arg('page_size')::int;
@@ -0,0 +1,8 @@
+-- name: ListShareCounts :many
+SELECT COALESCE(rechirp_of, quote_of)::text AS chirp_id,
+    COUNT(*) FILTER (WHERE rechirp_of IS NOT NULL) AS rechirp_count,
+    COUNT(*) FILTER (WHERE quote_of IS NOT NULL) AS quote_count
+FROM chirps
+WHERE deleted_at IS NULL AND hidden_at IS NULL AND publish_at IS NULL
+    AND (rechirp_of = ANY(sqlc.arg('chirp_ids')::text[]) OR quote_of = ANY(sqlc.arg('chirp_ids')::text[]))
+GROUP BY COALESCE(rechirp_of, quote_of);
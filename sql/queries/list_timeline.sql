@@ -0,0 +1,12 @@
+-- name: ListTimeline :many
+SELECT sqlc.embed(chirps)
+FROM timeline_entries
+JOIN chirps ON chirps.id = timeline_entries.chirp_id
+WHERE timeline_entries.user_id = sqlc.arg('user_id')
+    AND chirps.deleted_at IS NULL
+    AND chirps.hidden_at IS NULL
+    AND chirps.publish_at IS NULL
+    AND NOT (timeline_entries.author_id = ANY(sqlc.arg('hidden_author_ids')::text[]))
+    AND (timeline_entries.created_at, timeline_entries.chirp_id) < (sqlc.arg('before_created_at')::timestamp, sqlc.arg('before_chirp_id')::text)
+ORDER BY timeline_entries.created_at DESC, timeline_entries.chirp_id DESC
+LIMIT sqlc.arg('page_size')::int;
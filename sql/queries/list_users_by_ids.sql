@@ -0,0 +1,3 @@
+-- name: ListUsersByIDs :many
+SELECT * FROM users
+WHERE id = ANY(sqlc.arg('ids')::text[]);
@@ -0,0 +1,2 @@
+-- name: LockChirpEvents :exec
+SELECT pg_advisory_xact_lock(hashtext('chirp_events'));
@@ -0,0 +1,6 @@
+-- name: MarkNotificationsRead :execrows
+UPDATE notifications
+SET read_at = NOW()
+WHERE user_id = sqlc.arg('user_id')
+    AND read_at IS NULL
+    AND (cardinality(sqlc.arg('ids')::text[]) = 0 OR id = ANY(sqlc.arg('ids')::text[]));
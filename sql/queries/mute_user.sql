@@ -0,0 +1,10 @@
+-- name: MuteUser :exec
+WITH inserted AS (
+    INSERT INTO user_mutes (muter_id, muted_id, created_at)
+    VALUES ($1, $2, NOW())
+    ON CONFLICT (muter_id, muted_id) DO NOTHING
+    RETURNING muter_id
+)
+UPDATE users
+SET audience_updated_at = NOW()
+WHERE id IN (SELECT muter_id FROM inserted);
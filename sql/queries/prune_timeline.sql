@@ -0,0 +1,3 @@
+-- name: PruneTimeline :exec
+DELETE FROM timeline_entries
+WHERE user_id = $1 AND author_id = $2;
@@ -0,0 +1,5 @@
+-- name: PublishChirp :one
+UPDATE chirps
+SET publish_at = NULL, created_at = NOW(), updated_at = NOW()
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,6 @@
+-- name: PurgeChirpEvents :execrows
+DELETE FROM chirp_events
+WHERE id <= (
+    SELECT MAX(id) FROM chirp_events
+    WHERE created_at < sqlc.arg('created_at')
+);
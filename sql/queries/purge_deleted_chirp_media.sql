@@ -0,0 +1,7 @@
+-- name: PurgeDeletedChirpMedia :many
+DELETE FROM media
+USING chirp_media, chirps
+WHERE chirp_media.media_id = media.id
+    AND chirps.id = chirp_media.chirp_id
+    AND chirps.deleted_at IS NOT NULL AND chirps.deleted_at < sqlc.arg('deleted_at')
+RETURNING media.storage_key, media.thumbnail_key;
@@ -0,0 +1,3 @@
+-- name: PurgeDeletedChirps :execrows
+DELETE FROM chirps
+WHERE deleted_at IS NOT NULL AND deleted_at < $1;
@@ -0,0 +1,6 @@
+-- name: PurgeDeletedUserMedia :many
+DELETE FROM media
+USING users
+WHERE users.id = media.user_id
+    AND users.deleted_at IS NOT NULL AND users.deleted_at < sqlc.arg('deleted_at')
+RETURNING media.storage_key, media.thumbnail_key;
@@ -0,0 +1,3 @@
+-- name: PurgeIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE expires_at < $1;
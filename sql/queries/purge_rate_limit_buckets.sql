@@ -0,0 +1,3 @@
+-- name: PurgeRateLimitBuckets :execrows
+DELETE FROM rate_limit_buckets
+WHERE updated_at < $1;
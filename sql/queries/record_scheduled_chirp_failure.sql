@@ -0,0 +1,10 @@
+-- name: RecordScheduledChirpFailure :exec
+INSERT INTO scheduled_chirp_failures (chirp_id, attempts, last_error, next_attempt_at)
+VALUES (sqlc.arg('chirp_id'), 1, sqlc.arg('last_error'), NOW() + make_interval(secs => sqlc.arg('base_delay_seconds')::float8))
+ON CONFLICT (chirp_id) DO UPDATE
+SET attempts = scheduled_chirp_failures.attempts + 1,
+    last_error = excluded.last_error,
+    next_attempt_at = NOW() + make_interval(secs => LEAST(
+        sqlc.arg('base_delay_seconds')::float8 * power(2, LEAST(scheduled_chirp_failures.attempts, 20)),
+        sqlc.arg('max_delay_seconds')::float8
+    ));
@@ -0,0 +1,3 @@
+-- name: ReleaseIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE scope = $1 AND key = $2 AND request_hash = $3 AND status_code IS NULL;
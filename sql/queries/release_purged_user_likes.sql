@@ -0,0 +1,11 @@
+-- name: ReleasePurgedUserLikes :exec
+UPDATE chirps
+SET like_count = chirps.like_count - likes.count, updated_at = NOW()
+FROM (
+    SELECT chirp_likes.chirp_id, COUNT(*)::int AS count
+    FROM chirp_likes
+    JOIN users ON users.id = chirp_likes.user_id
+    WHERE users.deleted_at IS NOT NULL AND users.deleted_at < sqlc.arg('deleted_at')
+    GROUP BY chirp_likes.chirp_id
+) AS likes
+WHERE chirps.id = likes.chirp_id;
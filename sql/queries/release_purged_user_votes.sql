@@ -0,0 +1,18 @@
+-- name: ReleasePurgedUserVotes :exec
+WITH votes AS (
+    SELECT poll_votes.chirp_id, poll_votes.position, COUNT(*)::int AS count
+    FROM poll_votes
+    JOIN users ON users.id = poll_votes.user_id
+    WHERE users.deleted_at IS NOT NULL AND users.deleted_at < sqlc.arg('deleted_at')
+    GROUP BY poll_votes.chirp_id, poll_votes.position
+),
+touched AS (
+    UPDATE chirps
+    SET updated_at = NOW()
+    WHERE id IN (SELECT chirp_id FROM votes)
+)
+UPDATE poll_options
+SET vote_count = poll_options.vote_count - votes.count
+FROM votes
+WHERE poll_options.chirp_id = votes.chirp_id
+    AND poll_options.position = votes.position;
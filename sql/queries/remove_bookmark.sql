@@ -0,0 +1,3 @@
+-- name: RemoveBookmark :exec
+DELETE FROM bookmarks
+WHERE user_id = $1 AND chirp_id = $2;
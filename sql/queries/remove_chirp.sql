@@ -0,0 +1,4 @@
+-- name: RemoveChirp :exec
+UPDATE chirps
+SET deleted_at = COALESCE(deleted_at, NOW()), hidden_at = COALESCE(hidden_at, NOW()), updated_at = NOW()
+WHERE id = $1;
@@ -0,0 +1,3 @@
+-- name: RemoveRemoteFollower :execrows
+DELETE FROM remote_followers
+WHERE user_id = sqlc.arg('user_id') AND actor_url = sqlc.arg('actor_url') AND follow_id = sqlc.arg('follow_id');
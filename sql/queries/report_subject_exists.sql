@@ -0,0 +1,3 @@
+-- name: ReportSubjectExists :one
+SELECT EXISTS (SELECT 1 FROM users WHERE id = sqlc.arg('user_id'))
+    AND (sqlc.narg('chirp_id')::text IS NULL OR EXISTS (SELECT 1 FROM chirps WHERE id = sqlc.narg('chirp_id'))) AS present;
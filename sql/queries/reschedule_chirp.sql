@@ -0,0 +1,6 @@
+-- name: RescheduleChirp :one
+UPDATE chirps
+SET publish_at = sqlc.arg('publish_at'), updated_at = NOW()
+WHERE id = sqlc.arg('id') AND user_id = sqlc.arg('user_id')
+    AND publish_at IS NOT NULL AND deleted_at IS NULL
+RETURNING *;
@@ -0,0 +1,10 @@
+-- name: ResolveMentions :many
+SELECT id, lower(handle)::text AS mention
+FROM users
+WHERE deleted_at IS NULL
+    AND lower(handle) = ANY(sqlc.arg('mentions')::text[])
+    AND NOT EXISTS (
+        SELECT 1 FROM user_blocks
+        WHERE (user_blocks.blocker_id = users.id AND user_blocks.blocked_id = sqlc.arg('author_id')::text)
+            OR (user_blocks.blocker_id = sqlc.arg('author_id')::text AND user_blocks.blocked_id = users.id)
+    );
@@ -0,0 +1,7 @@
+-- name: ResolveReports :many
+UPDATE reports
+SET decision_id = sqlc.arg('decision_id'), resolved_at = NOW()
+WHERE decision_id IS NULL
+    AND user_id = sqlc.arg('user_id')
+    AND chirp_id IS NOT DISTINCT FROM sqlc.narg('chirp_id')
+RETURNING *;
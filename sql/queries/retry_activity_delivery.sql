@@ -0,0 +1,6 @@
+-- name: RetryActivityDelivery :exec
+UPDATE activity_deliveries
+SET attempts = sqlc.arg('attempts'),
+    next_attempt_at = NOW() + make_interval(secs => sqlc.arg('delay_seconds')::float8),
+    last_error = sqlc.arg('last_error')
+WHERE id = sqlc.arg('id');
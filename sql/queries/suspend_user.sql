@@ -0,0 +1,4 @@
+-- name: SuspendUser :exec
+UPDATE users
+SET suspended_until = GREATEST(suspended_until, sqlc.arg('suspended_until')), updated_at = NOW()
+WHERE id = sqlc.arg('id');
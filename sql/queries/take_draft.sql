@@ -0,0 +1,4 @@
+-- name: TakeDraft :one
+DELETE FROM drafts
+WHERE id = $1 AND user_id = $2
+RETURNING *;
@@ -0,0 +1,9 @@
+-- name: TakeRateLimitToken :one
+INSERT INTO rate_limit_buckets AS buckets (key, tokens, allowed, updated_at)
+VALUES (sqlc.arg('key'), sqlc.arg('capacity')::float8 - 1, true, NOW())
+ON CONFLICT (key) DO UPDATE
+SET tokens = LEAST(sqlc.arg('capacity')::float8, buckets.tokens + EXTRACT(EPOCH FROM NOW() - buckets.updated_at)::float8 * sqlc.arg('refill_rate')::float8)
+        - CASE WHEN LEAST(sqlc.arg('capacity')::float8, buckets.tokens + EXTRACT(EPOCH FROM NOW() - buckets.updated_at)::float8 * sqlc.arg('refill_rate')::float8) >= 1 THEN 1 ELSE 0 END,
+    allowed = LEAST(sqlc.arg('capacity')::float8, buckets.tokens + EXTRACT(EPOCH FROM NOW() - buckets.updated_at)::float8 * sqlc.arg('refill_rate')::float8) >= 1,
+    updated_at = NOW()
+RETURNING tokens, allowed;
@@ -0,0 +1,8 @@
+-- name: TouchChirpReferences :exec
+UPDATE chirps
+SET updated_at = NOW()
+WHERE id IN (
+    SELECT unnest(ARRAY[chirp.in_reply_to, chirp.rechirp_of, chirp.quote_of])
+    FROM chirps AS chirp
+    WHERE chirp.id = sqlc.arg('id')
+);
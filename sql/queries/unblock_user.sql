@@ -0,0 +1,9 @@
+-- name: UnblockUser :exec
+WITH deleted AS (
+    DELETE FROM user_blocks
+    WHERE blocker_id = $1 AND blocked_id = $2
+    RETURNING blocker_id, blocked_id
+)
+UPDATE users
+SET audience_updated_at = NOW()
+WHERE id IN (SELECT blocker_id FROM deleted UNION SELECT blocked_id FROM deleted);
@@ -0,0 +1,9 @@
+-- name: UnlikeChirp :execrows
+WITH deleted AS (
+    DELETE FROM chirp_likes
+    WHERE chirp_id = $1 AND user_id = $2
+    RETURNING chirp_id
+)
+UPDATE chirps
+SET like_count = like_count - 1, updated_at = NOW()
+WHERE id IN (SELECT chirp_id FROM deleted);
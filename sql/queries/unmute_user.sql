@@ -0,0 +1,9 @@
+-- name: UnmuteUser :exec
+WITH deleted AS (
+    DELETE FROM user_mutes
+    WHERE muter_id = $1 AND muted_id = $2
+    RETURNING muter_id
+)
+UPDATE users
+SET audience_updated_at = NOW()
+WHERE id IN (SELECT muter_id FROM deleted);
@@ -0,0 +1,5 @@
+-- name: UpdateContentFilterRule :one
+UPDATE content_filter_rules
+SET pattern = $1, action = $2, updated_at = NOW()
+WHERE id = $3
+RETURNING *;
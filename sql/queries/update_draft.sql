@@ -0,0 +1,12 @@
+-- name: UpdateDraft :one
+UPDATE drafts
+SET body = sqlc.arg('body'),
+    in_reply_to = sqlc.narg('in_reply_to'),
+    quote_of = sqlc.narg('quote_of'),
+    media_ids = sqlc.arg('media_ids')::text[],
+    publish_at = sqlc.narg('publish_at'),
+    poll_options = sqlc.narg('poll_options')::text[],
+    poll_closes_at = sqlc.narg('poll_closes_at'),
+    updated_at = NOW()
+WHERE id = sqlc.arg('id') AND user_id = sqlc.arg('user_id')
+RETURNING *;
@@ -0,0 +1,5 @@
+-- name: UpdateUserProfile :one
+UPDATE users
+SET handle = $1, display_name = $2, bio = $3, avatar_url = $4, updated_at = NOW()
+WHERE id = $5
+RETURNING *;
@@ -0,0 +1,5 @@
+-- name: UpsertNotificationPreference :exec
+INSERT INTO notification_preferences (user_id, type, enabled, updated_at)
+VALUES ($1, $2, $3, NOW())
+ON CONFLICT (user_id, type) DO UPDATE
+SET enabled = EXCLUDED.enabled, updated_at = NOW();
@@ -0,0 +1,22 @@
+-- name: VotePoll :execrows
+WITH inserted AS (
+    INSERT INTO poll_votes (chirp_id, user_id, position, created_at)
+    SELECT poll_options.chirp_id, sqlc.arg('user_id'), poll_options.position, NOW()
+    FROM polls
+    JOIN poll_options ON poll_options.chirp_id = polls.chirp_id
+    WHERE polls.chirp_id = sqlc.arg('chirp_id')
+        AND poll_options.position = sqlc.arg('position')
+        AND polls.closes_at > NOW()
+    ON CONFLICT (chirp_id, user_id) DO NOTHING
+    RETURNING chirp_id, position
+),
+touched AS (
+    UPDATE chirps
+    SET updated_at = NOW()
+    WHERE id IN (SELECT chirp_id FROM inserted)
+)
+UPDATE poll_options
+SET vote_count = vote_count + 1
+FROM inserted
+WHERE poll_options.chirp_id = inserted.chirp_id
+    AND poll_options.position = inserted.position;
@@ -0,0 +1,33 @@
+-- +goose Up
+ALTER TABLE users
+ADD COLUMN deleted_at TIMESTAMP;
+
+ALTER TABLE users
+ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE users
+DROP CONSTRAINT users_email_key;
+
+CREATE UNIQUE INDEX users_active_email_idx ON users (email) WHERE deleted_at IS NULL;
+
+ALTER TABLE chirps
+ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX chirps_deleted_at_idx ON chirps (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX chirps_deleted_at_idx;
+
+ALTER TABLE chirps
+DROP COLUMN deleted_at;
+
+DROP INDEX users_active_email_idx;
+
+ALTER TABLE users
+ADD CONSTRAINT users_email_key UNIQUE (email);
+
+ALTER TABLE users
+DROP COLUMN is_admin;
+
+ALTER TABLE users
+DROP COLUMN deleted_at;
@@ -0,0 +1,19 @@
+-- +goose Up
+ALTER TABLE chirps
+ADD COLUMN in_reply_to TEXT;
+
+ALTER TABLE chirps
+ADD CONSTRAINT fk_chirp_reply
+FOREIGN KEY (in_reply_to)
+REFERENCES chirps(id) ON DELETE SET NULL;
+
+CREATE INDEX chirps_in_reply_to_idx ON chirps (in_reply_to);
+
+-- +goose Down
+DROP INDEX chirps_in_reply_to_idx;
+
+ALTER TABLE chirps
+DROP CONSTRAINT fk_chirp_reply;
+
+ALTER TABLE chirps
+DROP COLUMN in_reply_to;
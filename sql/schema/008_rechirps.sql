@@ -0,0 +1,42 @@
+-- +goose Up
+ALTER TABLE chirps
+ADD COLUMN rechirp_of TEXT;
+
+ALTER TABLE chirps
+ADD COLUMN quote_of TEXT;
+
+ALTER TABLE chirps
+ADD CONSTRAINT fk_chirp_rechirp
+FOREIGN KEY (rechirp_of)
+REFERENCES chirps(id) ON DELETE CASCADE;
+
+ALTER TABLE chirps
+ADD CONSTRAINT fk_chirp_quote
+FOREIGN KEY (quote_of)
+REFERENCES chirps(id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX chirps_active_rechirp_idx ON chirps (user_id, rechirp_of)
+WHERE rechirp_of IS NOT NULL AND deleted_at IS NULL;
+
+CREATE INDEX chirps_rechirp_of_idx ON chirps (rechirp_of);
+
+CREATE INDEX chirps_quote_of_idx ON chirps (quote_of);
+
+-- +goose Down
+DROP INDEX chirps_quote_of_idx;
+
+DROP INDEX chirps_rechirp_of_idx;
+
+DROP INDEX chirps_active_rechirp_idx;
+
+ALTER TABLE chirps
+DROP CONSTRAINT fk_chirp_quote;
+
+ALTER TABLE chirps
+DROP CONSTRAINT fk_chirp_rechirp;
+
+ALTER TABLE chirps
+DROP COLUMN quote_of;
+
+ALTER TABLE chirps
+DROP COLUMN rechirp_of;
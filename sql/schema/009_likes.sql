@@ -0,0 +1,27 @@
+-- +goose Up
+CREATE TABLE chirp_likes(
+    chirp_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (chirp_id, user_id),
+
+    CONSTRAINT fk_chirp_like_chirp
+    FOREIGN KEY (chirp_id)
+    REFERENCES chirps(id) ON DELETE CASCADE,
+
+    CONSTRAINT fk_chirp_like_user
+    FOREIGN KEY (user_id)
+    REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX chirp_likes_chirp_created_idx ON chirp_likes (chirp_id, created_at DESC, user_id DESC);
+
+ALTER TABLE chirps
+ADD COLUMN like_count INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE chirps
+DROP COLUMN like_count;
+
+DROP TABLE chirp_likes;
@@ -0,0 +1,28 @@
+-- +goose Up
+CREATE TABLE chirp_hashtags(
+    chirp_id TEXT NOT NULL,
+    tag TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (chirp_id, tag),
+
+    CONSTRAINT fk_chirp_hashtag_chirp
+    FOREIGN KEY (chirp_id)
+    REFERENCES chirps(id) ON DELETE CASCADE
+);
+
+CREATE INDEX chirp_hashtags_tag_created_idx ON chirp_hashtags (tag, created_at DESC, chirp_id DESC);
+
+CREATE INDEX chirp_hashtags_created_idx ON chirp_hashtags (created_at);
+
+CREATE TABLE hashtag_trends(
+    tag TEXT PRIMARY KEY,
+    score DOUBLE PRECISION NOT NULL,
+    uses BIGINT NOT NULL,
+    refreshed_at TIMESTAMP NOT NULL
+);
+
+-- +goose Down
+DROP TABLE hashtag_trends;
+
+DROP TABLE chirp_hashtags;
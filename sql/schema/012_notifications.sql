@@ -0,0 +1,63 @@
+-- +goose Up
+CREATE TABLE chirp_mentions(
+    chirp_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+
+    PRIMARY KEY (chirp_id, user_id),
+
+    CONSTRAINT fk_chirp_mention_chirp
+    FOREIGN KEY (chirp_id)
+    REFERENCES chirps(id) ON DELETE CASCADE,
+
+    CONSTRAINT fk_chirp_mention_user
+    FOREIGN KEY (user_id)
+    REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX chirp_mentions_user_idx ON chirp_mentions (user_id);
+
+CREATE TABLE notifications(
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    actor_id TEXT NOT NULL,
+    type TEXT NOT NULL,
+    chirp_id TEXT,
+    created_at TIMESTAMP NOT NULL,
+    read_at TIMESTAMP,
+
+    CONSTRAINT fk_notification_user
+    FOREIGN KEY (user_id)
+    REFERENCES users(id) ON DELETE CASCADE,
+
+    CONSTRAINT fk_notification_actor
+    FOREIGN KEY (actor_id)
+    REFERENCES users(id) ON DELETE CASCADE,
+
+    CONSTRAINT fk_notification_chirp
+    FOREIGN KEY (chirp_id)
+    REFERENCES chirps(id) ON DELETE CASCADE
+);
+
+CREATE INDEX notifications_user_created_idx ON notifications (user_id, created_at DESC, id DESC);
+
+CREATE INDEX notifications_user_unread_idx ON notifications (user_id) WHERE read_at IS NULL;
+
+CREATE TABLE notification_preferences(
+    user_id TEXT NOT NULL,
+    type TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (user_id, type),
+
+    CONSTRAINT fk_notification_preference_user
+    FOREIGN KEY (user_id)
+    REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE notification_preferences;
+
+DROP TABLE notifications;
+
+DROP TABLE chirp_mentions;
@@ -0,0 +1,29 @@
+-- +goose Up
+ALTER TABLE users
+ADD COLUMN handle TEXT;
+
+ALTER TABLE users
+ADD COLUMN display_name TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE users
+ADD COLUMN bio TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE users
+ADD COLUMN avatar_url TEXT NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX users_active_handle_idx ON users (lower(handle)) WHERE deleted_at IS NULL;
+
+-- +goose Down
+DROP INDEX users_active_handle_idx;
+
+ALTER TABLE users
+DROP COLUMN avatar_url;
+
+ALTER TABLE users
+DROP COLUMN bio;
+
+ALTER TABLE users
+DROP COLUMN display_name;
+
+ALTER TABLE users
+DROP COLUMN handle;
@@ -0,0 +1,47 @@
+-- +goose Up
+CREATE TABLE follows(
+    follower_id TEXT NOT NULL,
+    followee_id TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (follower_id, followee_id),
+
+    CONSTRAINT fk_follow_follower
+    FOREIGN KEY (follower_id)
+    REFERENCES users(id) ON DELETE CASCADE,
+
+    CONSTRAINT fk_follow_followee
+    FOREIGN KEY (followee_id)
+    REFERENCES users(id) ON DELETE CASCADE,
+
+    CONSTRAINT follow_not_self CHECK (follower_id <> followee_id)
+);
+
+CREATE INDEX follows_follower_created_idx ON follows (follower_id, created_at DESC, followee_id DESC);
+CREATE INDEX follows_followee_created_idx ON follows (followee_id, created_at DESC, follower_id DESC);
+
+-- Home timelines are materialized when a chirp is written, so reading one is
+-- a single index range scan no matter how many accounts the user follows.
+CREATE TABLE timeline_entries(
+    user_id TEXT NOT NULL,
+    chirp_id TEXT NOT NULL,
+    author_id TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (user_id, chirp_id),
+
+    CONSTRAINT fk_timeline_user
+    FOREIGN KEY (user_id)
+    REFERENCES users(id) ON DELETE CASCADE,
+
+    CONSTRAINT fk_timeline_chirp
+    FOREIGN KEY (chirp_id)
+    REFERENCES chirps(id) ON DELETE CASCADE
+);
+
+CREATE INDEX timeline_entries_user_created_idx ON timeline_entries (user_id, created_at DESC, chirp_id DESC);
+CREATE INDEX timeline_entries_user_author_idx ON timeline_entries (user_id, author_id);
+
+-- +goose Down
+DROP TABLE timeline_entries;
+DROP TABLE follows;
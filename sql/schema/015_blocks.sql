@@ -0,0 +1,45 @@
+-- +goose Up
+CREATE TABLE user_blocks(
+    blocker_id TEXT NOT NULL,
+    blocked_id TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (blocker_id, blocked_id),
+
+    CONSTRAINT fk_block_blocker
+    FOREIGN KEY (blocker_id)
+    REFERENCES users(id) ON DELETE CASCADE,
+
+    CONSTRAINT fk_block_blocked
+    FOREIGN KEY (blocked_id)
+    REFERENCES users(id) ON DELETE CASCADE,
+
+    CONSTRAINT block_not_self CHECK (blocker_id <> blocked_id)
+);
+
+CREATE INDEX user_blocks_blocked_idx ON user_blocks (blocked_id);
+CREATE INDEX user_blocks_blocker_created_idx ON user_blocks (blocker_id, created_at DESC, blocked_id DESC);
+
+CREATE TABLE user_mutes(
+    muter_id TEXT NOT NULL,
+    muted_id TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (muter_id, muted_id),
+
+    CONSTRAINT fk_mute_muter
+    FOREIGN KEY (muter_id)
+    REFERENCES users(id) ON DELETE CASCADE,
+
+    CONSTRAINT fk_mute_muted
+    FOREIGN KEY (muted_id)
+    REFERENCES users(id) ON DELETE CASCADE,
+
+    CONSTRAINT mute_not_self CHECK (muter_id <> muted_id)
+);
+
+CREATE INDEX user_mutes_muter_created_idx ON user_mutes (muter_id, created_at DESC, muted_id DESC);
+
+-- +goose Down
+DROP TABLE user_mutes;
+DROP TABLE user_blocks;
@@ -0,0 +1,37 @@
+-- +goose Up
+CREATE TABLE media(
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    byte_size BIGINT NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    storage_key TEXT NOT NULL,
+    thumbnail_key TEXT NOT NULL,
+    thumbnail_content_type TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_media_user
+    FOREIGN KEY (user_id)
+    REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE chirp_media(
+    chirp_id TEXT NOT NULL,
+    media_id TEXT NOT NULL UNIQUE,
+    position INTEGER NOT NULL,
+
+    PRIMARY KEY (chirp_id, position),
+
+    CONSTRAINT fk_chirp_media_chirp
+    FOREIGN KEY (chirp_id)
+    REFERENCES chirps(id) ON DELETE CASCADE,
+
+    CONSTRAINT fk_chirp_media_media
+    FOREIGN KEY (media_id)
+    REFERENCES media(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE chirp_media;
+DROP TABLE media;
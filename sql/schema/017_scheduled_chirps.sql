@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE chirps
+ADD COLUMN publish_at TIMESTAMP;
+
+-- Only scheduled chirps have publish_at set, so the publisher's scan stays
+-- small no matter how many chirps exist.
+CREATE INDEX chirps_publish_at_idx ON chirps (publish_at) WHERE publish_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX chirps_publish_at_idx;
+
+ALTER TABLE chirps
+DROP COLUMN publish_at;
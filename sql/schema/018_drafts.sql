@@ -0,0 +1,24 @@
+-- +goose Up
+-- Drafts are not validated when saved; everything is checked when they are
+-- published, so the reply and quote targets are plain columns without
+-- foreign keys.
+CREATE TABLE drafts(
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    body TEXT NOT NULL,
+    in_reply_to TEXT,
+    quote_of TEXT,
+    media_ids TEXT[] NOT NULL DEFAULT '{}',
+    publish_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_drafts_user
+    FOREIGN KEY (user_id)
+    REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX drafts_user_id_updated_at_idx ON drafts (user_id, updated_at DESC, id DESC);
+
+-- +goose Down
+DROP TABLE drafts;
@@ -0,0 +1,53 @@
+-- +goose Up
+CREATE TABLE polls(
+    chirp_id TEXT PRIMARY KEY,
+    closes_at TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_poll_chirp
+    FOREIGN KEY (chirp_id)
+    REFERENCES chirps(id) ON DELETE CASCADE
+);
+
+CREATE TABLE poll_options(
+    chirp_id TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    label TEXT NOT NULL,
+    vote_count INTEGER NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (chirp_id, position),
+
+    CONSTRAINT fk_poll_option_poll
+    FOREIGN KEY (chirp_id)
+    REFERENCES polls(chirp_id) ON DELETE CASCADE
+);
+
+-- The primary key is what enforces one vote per user.
+CREATE TABLE poll_votes(
+    chirp_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (chirp_id, user_id),
+
+    CONSTRAINT fk_poll_vote_option
+    FOREIGN KEY (chirp_id, position)
+    REFERENCES poll_options(chirp_id, position) ON DELETE CASCADE,
+
+    CONSTRAINT fk_poll_vote_user
+    FOREIGN KEY (user_id)
+    REFERENCES users(id) ON DELETE CASCADE
+);
+
+ALTER TABLE drafts
+ADD COLUMN poll_options TEXT[],
+ADD COLUMN poll_closes_at TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE drafts
+DROP COLUMN poll_closes_at,
+DROP COLUMN poll_options;
+
+DROP TABLE poll_votes;
+DROP TABLE poll_options;
+DROP TABLE polls;
@@ -0,0 +1,31 @@
+-- +goose Up
+CREATE TABLE content_filter_rules(
+    id TEXT PRIMARY KEY,
+    pattern TEXT NOT NULL UNIQUE,
+    action TEXT NOT NULL CHECK (action IN ('mask', 'reject', 'flag')),
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+-- The words that used to be hardcoded in utils.IsProfaneWord.
+INSERT INTO content_filter_rules (id, pattern, action, created_at, updated_at)
+VALUES
+    (gen_random_uuid(), 'kerfuffle', 'mask', NOW(), NOW()),
+    (gen_random_uuid(), 'sharbert', 'mask', NOW(), NOW()),
+    (gen_random_uuid(), 'fornax', 'mask', NOW(), NOW());
+
+CREATE TABLE chirp_flags(
+    chirp_id TEXT PRIMARY KEY,
+    words TEXT[] NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_chirp_flag_chirp
+    FOREIGN KEY (chirp_id)
+    REFERENCES chirps(id) ON DELETE CASCADE
+);
+
+CREATE INDEX chirp_flags_created_idx ON chirp_flags (created_at DESC, chirp_id DESC);
+
+-- +goose Down
+DROP TABLE chirp_flags;
+DROP TABLE content_filter_rules;
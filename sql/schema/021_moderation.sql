@@ -0,0 +1,81 @@
+-- +goose Up
+ALTER TABLE users
+ADD COLUMN is_moderator BOOLEAN NOT NULL DEFAULT false,
+ADD COLUMN suspended_until TIMESTAMP;
+
+-- Hidden chirps stay in the database for appeals but are masked everywhere
+-- and cannot be restored by their author.
+ALTER TABLE chirps
+ADD COLUMN hidden_at TIMESTAMP;
+
+CREATE TABLE moderation_decisions(
+    id TEXT PRIMARY KEY,
+    moderator_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    chirp_id TEXT,
+    action TEXT NOT NULL CHECK (action IN ('dismiss', 'hide', 'delete', 'warn', 'suspend')),
+    note TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_moderation_decision_moderator
+    FOREIGN KEY (moderator_id)
+    REFERENCES users(id) ON DELETE CASCADE,
+
+    CONSTRAINT fk_moderation_decision_user
+    FOREIGN KEY (user_id)
+    REFERENCES users(id) ON DELETE CASCADE,
+
+    CONSTRAINT fk_moderation_decision_chirp
+    FOREIGN KEY (chirp_id)
+    REFERENCES chirps(id) ON DELETE CASCADE
+);
+
+-- A report is about a user, and about one of their chirps when chirp_id is
+-- set. It stays in the queue until a decision resolves it.
+CREATE TABLE reports(
+    id TEXT PRIMARY KEY,
+    reporter_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    chirp_id TEXT,
+    reason TEXT NOT NULL,
+    details TEXT NOT NULL,
+    decision_id TEXT,
+    created_at TIMESTAMP NOT NULL,
+    resolved_at TIMESTAMP,
+
+    CONSTRAINT fk_report_reporter
+    FOREIGN KEY (reporter_id)
+    REFERENCES users(id) ON DELETE CASCADE,
+
+    CONSTRAINT fk_report_user
+    FOREIGN KEY (user_id)
+    REFERENCES users(id) ON DELETE CASCADE,
+
+    CONSTRAINT fk_report_chirp
+    FOREIGN KEY (chirp_id)
+    REFERENCES chirps(id) ON DELETE CASCADE,
+
+    CONSTRAINT fk_report_decision
+    FOREIGN KEY (decision_id)
+    REFERENCES moderation_decisions(id) ON DELETE SET NULL
+);
+
+CREATE INDEX reports_open_idx ON reports (created_at, id) WHERE decision_id IS NULL;
+
+-- One open report per reporter and subject; reporting again is a no-op.
+CREATE UNIQUE INDEX reports_open_chirp_unique ON reports (reporter_id, chirp_id)
+WHERE decision_id IS NULL AND chirp_id IS NOT NULL;
+
+CREATE UNIQUE INDEX reports_open_user_unique ON reports (reporter_id, user_id)
+WHERE decision_id IS NULL AND chirp_id IS NULL;
+
+-- +goose Down
+DROP TABLE reports;
+DROP TABLE moderation_decisions;
+
+ALTER TABLE chirps
+DROP COLUMN hidden_at;
+
+ALTER TABLE users
+DROP COLUMN suspended_until,
+DROP COLUMN is_moderator;
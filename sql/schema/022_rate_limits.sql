@@ -0,0 +1,13 @@
+-- +goose Up
+-- Token buckets shared by every instance. A bucket holds the tokens left
+-- after its last request; refills are computed from updated_at when the
+-- next request arrives, so idle buckets need no background work.
+CREATE TABLE rate_limit_buckets(
+    key TEXT PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    allowed BOOLEAN NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+-- +goose Down
+DROP TABLE rate_limit_buckets;
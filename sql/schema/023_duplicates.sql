@@ -0,0 +1,32 @@
+-- +goose Up
+CREATE TABLE chirp_fingerprints(
+    chirp_id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    hash TEXT NOT NULL,
+    simhash BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_chirp_fingerprint_chirp
+    FOREIGN KEY (chirp_id)
+    REFERENCES chirps(id) ON DELETE CASCADE,
+
+    CONSTRAINT fk_chirp_fingerprint_user
+    FOREIGN KEY (user_id)
+    REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX chirp_fingerprints_user_created_idx ON chirp_fingerprints (user_id, created_at DESC);
+
+CREATE INDEX chirp_fingerprints_created_idx ON chirp_fingerprints (created_at DESC);
+
+-- Flags now come from more than the content filter, so each one says why.
+ALTER TABLE chirp_flags
+ADD COLUMN reasons TEXT[] NOT NULL DEFAULT '{}';
+
+UPDATE chirp_flags SET reasons = '{content_filter}';
+
+-- +goose Down
+ALTER TABLE chirp_flags
+DROP COLUMN reasons;
+
+DROP TABLE chirp_fingerprints;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- The first response to a request sent with an Idempotency-Key header.
+-- status_code stays NULL while that first request is still running.
+CREATE TABLE idempotency_keys(
+    scope TEXT NOT NULL,
+    key TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    status_code INTEGER,
+    content_type TEXT NOT NULL DEFAULT '',
+    response_body BYTEA NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX idempotency_keys_expires_idx ON idempotency_keys (expires_at);
+
+-- +goose Down
+DROP TABLE idempotency_keys;
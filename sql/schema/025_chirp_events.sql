@@ -0,0 +1,7 @@
+-- +goose Up
+-- Event ids for the chirp stream. Every instance sees the same ids, so a
+-- client can resume on any of them.
+CREATE SEQUENCE chirp_event_ids;
+
+-- +goose Down
+DROP SEQUENCE chirp_event_ids;
@@ -0,0 +1,58 @@
+-- +goose Up
+-- The key pair a local user's ActivityPub requests are signed with. It is
+-- made the first time the user's actor is fetched or delivers something.
+CREATE TABLE actor_keys(
+    user_id TEXT PRIMARY KEY,
+    public_key_pem TEXT NOT NULL,
+    private_key_pem TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_actor_key_user
+    FOREIGN KEY (user_id)
+    REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Fediverse accounts following a local user. follow_id is the Follow
+-- activity they sent, which Accept and Undo refer to.
+CREATE TABLE remote_followers(
+    user_id TEXT NOT NULL,
+    actor_url TEXT NOT NULL,
+    inbox_url TEXT NOT NULL,
+    shared_inbox_url TEXT NOT NULL,
+    follow_id TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (user_id, actor_url),
+
+    CONSTRAINT fk_remote_follower_user
+    FOREIGN KEY (user_id)
+    REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Activities waiting to be delivered to a remote inbox. They are rendered
+-- when sent: object_id is the chirp for Create and Delete, and the remote
+-- Follow for Accept, sent by object_actor. A delivery being attempted has
+-- next_attempt_at pushed into the future so no other worker takes it.
+CREATE TABLE activity_deliveries(
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    inbox_url TEXT NOT NULL,
+    activity TEXT NOT NULL CHECK (activity IN ('Create', 'Delete', 'Accept')),
+    object_id TEXT NOT NULL,
+    object_actor TEXT NOT NULL DEFAULT '',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_activity_delivery_user
+    FOREIGN KEY (user_id)
+    REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX activity_deliveries_due_idx ON activity_deliveries (next_attempt_at);
+
+-- +goose Down
+DROP TABLE activity_deliveries;
+DROP TABLE remote_followers;
+DROP TABLE actor_keys;
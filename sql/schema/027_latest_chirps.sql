@@ -0,0 +1,13 @@
+-- +goose Up
+-- Feeds read the newest published chirps, overall or by one author, and
+-- stop after a page.
+CREATE INDEX chirps_latest_idx ON chirps (created_at DESC)
+WHERE deleted_at IS NULL AND hidden_at IS NULL AND publish_at IS NULL;
+
+CREATE INDEX chirps_latest_by_user_idx ON chirps (user_id, created_at DESC)
+WHERE deleted_at IS NULL AND hidden_at IS NULL AND publish_at IS NULL;
+
+-- +goose Down
+DROP INDEX chirps_latest_by_user_idx;
+
+DROP INDEX chirps_latest_idx;
@@ -0,0 +1,34 @@
+-- +goose Up
+-- Decisions and reports are the moderation audit trail, so they keep the ids
+-- of the users and chirps they were about after those are purged, instead
+-- of being deleted along with them.
+ALTER TABLE moderation_decisions
+DROP CONSTRAINT fk_moderation_decision_moderator,
+DROP CONSTRAINT fk_moderation_decision_user,
+DROP CONSTRAINT fk_moderation_decision_chirp;
+
+ALTER TABLE reports
+DROP CONSTRAINT fk_report_reporter,
+DROP CONSTRAINT fk_report_user,
+DROP CONSTRAINT fk_report_chirp;
+
+-- +goose Down
+DELETE FROM reports
+WHERE reporter_id NOT IN (SELECT id FROM users)
+    OR user_id NOT IN (SELECT id FROM users)
+    OR chirp_id NOT IN (SELECT id FROM chirps);
+
+DELETE FROM moderation_decisions
+WHERE moderator_id NOT IN (SELECT id FROM users)
+    OR user_id NOT IN (SELECT id FROM users)
+    OR chirp_id NOT IN (SELECT id FROM chirps);
+
+ALTER TABLE reports
+ADD CONSTRAINT fk_report_reporter FOREIGN KEY (reporter_id) REFERENCES users(id) ON DELETE CASCADE,
+ADD CONSTRAINT fk_report_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+ADD CONSTRAINT fk_report_chirp FOREIGN KEY (chirp_id) REFERENCES chirps(id) ON DELETE CASCADE;
+
+ALTER TABLE moderation_decisions
+ADD CONSTRAINT fk_moderation_decision_moderator FOREIGN KEY (moderator_id) REFERENCES users(id) ON DELETE CASCADE,
+ADD CONSTRAINT fk_moderation_decision_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+ADD CONSTRAINT fk_moderation_decision_chirp FOREIGN KEY (chirp_id) REFERENCES chirps(id) ON DELETE CASCADE;
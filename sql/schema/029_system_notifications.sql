@@ -0,0 +1,11 @@
+-- +goose Up
+-- System notices, such as moderation warnings, come from no user.
+ALTER TABLE notifications
+ALTER COLUMN actor_id DROP NOT NULL;
+
+-- +goose Down
+DELETE FROM notifications
+WHERE actor_id IS NULL;
+
+ALTER TABLE notifications
+ALTER COLUMN actor_id SET NOT NULL;
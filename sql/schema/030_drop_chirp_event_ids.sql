@@ -0,0 +1,8 @@
+-- +goose Up
+-- Ids drawn in the writer's transaction reached listeners in commit order,
+-- not in id order, so resuming after one could skip events. Each instance's
+-- hub now numbers events as they arrive.
+DROP SEQUENCE chirp_event_ids;
+
+-- +goose Down
+CREATE SEQUENCE chirp_event_ids;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Replays lost every header but Content-Type, including ETag and the
+-- RateLimit-* headers. Keep the headers a replay needs instead.
+ALTER TABLE idempotency_keys ADD COLUMN response_headers JSONB NOT NULL DEFAULT '{}';
+UPDATE idempotency_keys SET response_headers = jsonb_build_object('Content-Type', jsonb_build_array(content_type))
+WHERE content_type <> '';
+ALTER TABLE idempotency_keys DROP COLUMN content_type;
+
+-- +goose Down
+ALTER TABLE idempotency_keys ADD COLUMN content_type TEXT NOT NULL DEFAULT '';
+UPDATE idempotency_keys SET content_type = COALESCE(response_headers->'Content-Type'->>0, '');
+ALTER TABLE idempotency_keys DROP COLUMN response_headers;
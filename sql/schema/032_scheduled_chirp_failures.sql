@@ -0,0 +1,17 @@
+-- +goose Up
+-- Scheduled chirps that failed to publish, such as a reply whose parent was
+-- purged before it went out. The publisher skips them until next_attempt_at
+-- so they cannot hold up the chirps scheduled after them.
+CREATE TABLE scheduled_chirp_failures(
+    chirp_id TEXT PRIMARY KEY,
+    attempts INTEGER NOT NULL,
+    last_error TEXT NOT NULL,
+    next_attempt_at TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_scheduled_chirp_failure_chirp
+    FOREIGN KEY (chirp_id)
+    REFERENCES chirps(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE scheduled_chirp_failures;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- A chirp's updated_at now moves whenever what it shows changes, likes,
+-- votes and the replies and shares counted on it included, so reads can send
+-- it as Last-Modified. Lists take the latest one among their author's chirps.
+CREATE INDEX chirps_updated_idx ON chirps (updated_at);
+CREATE INDEX chirps_user_updated_idx ON chirps (user_id, updated_at);
+
+-- When something other than a chirp changed what a user is shown: a block or
+-- mute between them and someone else, or a scheduled chirp they cancelled.
+ALTER TABLE users ADD COLUMN audience_updated_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN audience_updated_at;
+DROP INDEX chirps_user_updated_idx;
+DROP INDEX chirps_updated_idx;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Events for the chirp stream, kept for a while so a client can resume on
+-- any instance. Writers take an advisory lock before drawing an id and hold
+-- it until they commit, so ids become visible in order and a reader that has
+-- seen one id will never later find a smaller one.
+CREATE TABLE chirp_events(
+    id BIGSERIAL PRIMARY KEY,
+    type TEXT NOT NULL,
+    chirp_id TEXT NOT NULL,
+    author_id TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX chirp_events_created_idx ON chirp_events (created_at);
+
+-- +goose Down
+DROP TABLE chirp_events;
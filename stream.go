@@ -0,0 +1,367 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/octaviocarpes/go-http-servers/internal/broadcast"
+	"github.com/octaviocarpes/go-http-servers/internal/database"
+	server "github.com/octaviocarpes/go-http-servers/server"
+)
+
+const (
+	chirpEventsChannel = "chirp_events"
+	chirpEventCreated  = "chirp.created"
+	chirpEventDeleted  = "chirp.deleted"
+
+	// streamSubscriberBuffer is how many events a stream client may fall
+	// behind before it is disconnected and has to resume.
+	streamSubscriberBuffer = 64
+	streamRetryMillis      = 3000
+
+	// chirpEventPageSize is how many stored events the listener reads at a
+	// time when catching up.
+	chirpEventPageSize = 500
+)
+
+// notifyChirpEvent stores a stream event about a chirp and wakes every
+// instance's listener. Both happen when queries' transaction commits, so
+// subscribers never hear about a chirp that was rolled back. The lock it
+// takes is held until then, which is what keeps event ids in commit order.
+func notifyChirpEvent(ctx context.Context, queries *database.Queries, eventType, chirpID, authorID string) error {
+	if lockError := queries.LockChirpEvents(ctx); lockError != nil {
+		return lockError
+	}
+
+	return queries.CreateChirpEvent(ctx, database.CreateChirpEventParams{
+		Type:     eventType,
+		ChirpID:  chirpID,
+		AuthorID: authorID,
+	})
+}
+
+// listenForChirpEvents feeds the stream hub from the stored events until ctx
+// is cancelled. Notifications only say that there is something new; the
+// events themselves are read in id order after the last one published, so
+// any missed while the connection was down are picked up on the next read.
+func (config *apiConfig) listenForChirpEvents(ctx context.Context, dbURL string) {
+	bounds, boundsError := config.db.GetChirpEventBounds(ctx)
+
+	if boundsError != nil {
+		log.Printf("failed to read chirp events: %v\n", boundsError)
+		return
+	}
+
+	lastID := bounds.LatestID
+
+	listener := pq.NewListener(dbURL, time.Second, time.Minute, func(event pq.ListenerEventType, listenerError error) {
+		if listenerError != nil {
+			log.Printf("chirp event listener: %v\n", listenerError)
+		}
+	})
+	defer listener.Close()
+
+	if listenError := listener.Listen(chirpEventsChannel); listenError != nil {
+		log.Printf("failed to listen for chirp events: %v\n", listenError)
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-listener.Notify:
+			// A nil notification marks a reconnect, after which there may
+			// be events nobody was told about; reading catches up either way.
+		case <-time.After(90 * time.Second):
+			go listener.Ping()
+		}
+
+		lastID = config.publishChirpEvents(ctx, lastID)
+	}
+}
+
+// publishChirpEvents publishes the stored events after lastID to the hub and
+// returns the id of the last one published. It stops at the first event that
+// cannot be rendered, so the next call retries it instead of skipping it.
+func (config *apiConfig) publishChirpEvents(ctx context.Context, lastID int64) int64 {
+	for {
+		stored, listError := config.db.ListChirpEventsAfter(ctx, database.ListChirpEventsAfterParams{
+			AfterID:  lastID,
+			PageSize: chirpEventPageSize,
+		})
+
+		if listError != nil {
+			log.Printf("failed to read chirp events: %v\n", listError)
+			return lastID
+		}
+
+		for _, row := range stored {
+			event, ok, eventError := config.chirpEvent(ctx, row)
+
+			if eventError != nil {
+				log.Printf("failed to publish chirp event: %v\n", eventError)
+				return lastID
+			}
+
+			if ok {
+				config.chirpEvents.Publish(event)
+			}
+
+			lastID = row.ID
+		}
+
+		if len(stored) < chirpEventPageSize {
+			return lastID
+		}
+	}
+}
+
+// chirpEvent turns a stored event into a stream event. Created chirps are
+// rendered once here, for an anonymous viewer, rather than once per
+// subscriber. ok is false for a chirp that has since been purged.
+func (config *apiConfig) chirpEvent(ctx context.Context, row database.ChirpEvent) (event broadcast.Event, ok bool, err error) {
+	var data any = struct {
+		ID string `json:"id"`
+	}{ID: row.ChirpID}
+
+	if row.Type == chirpEventCreated {
+		chirp, getChirpError := config.db.GetChirpByID(ctx, row.ChirpID)
+
+		if errors.Is(getChirpError, sql.ErrNoRows) {
+			return broadcast.Event{}, false, nil
+		}
+
+		if getChirpError != nil {
+			return broadcast.Event{}, false, getChirpError
+		}
+
+		response, buildResponseError := config.buildChirpResponse(ctx, audience{}, chirp)
+
+		if buildResponseError != nil {
+			return broadcast.Event{}, false, buildResponseError
+		}
+
+		data = response
+	}
+
+	encoded, encodeError := json.Marshal(data)
+
+	if encodeError != nil {
+		return broadcast.Event{}, false, encodeError
+	}
+
+	mentions, listMentionsError := config.db.ListChirpMentions(ctx, row.ChirpID)
+
+	if listMentionsError != nil {
+		return broadcast.Event{}, false, listMentionsError
+	}
+
+	return broadcast.Event{
+		ID:       row.ID,
+		Type:     row.Type,
+		AuthorID: row.AuthorID,
+		Mentions: mentions,
+		Data:     encoded,
+	}, true, nil
+}
+
+// storedChirpEvents returns the events after lastEventID from the database,
+// for a client resuming from an event this instance's hub no longer has or
+// never saw. complete is false when older events have been purged since, or
+// there are more than the stream would replay.
+func (config *apiConfig) storedChirpEvents(ctx context.Context, lastEventID int64) (events []broadcast.Event, complete bool, err error) {
+	bounds, boundsError := config.db.GetChirpEventBounds(ctx)
+
+	if boundsError != nil {
+		return nil, false, boundsError
+	}
+
+	if bounds.OldestID == 0 || lastEventID < bounds.OldestID {
+		return []broadcast.Event{}, false, nil
+	}
+
+	stored, listError := config.db.ListChirpEventsAfter(ctx, database.ListChirpEventsAfterParams{
+		AfterID:  lastEventID,
+		PageSize: int32(config.streamReplaySize + 1),
+	})
+
+	if listError != nil {
+		return nil, false, listError
+	}
+
+	if len(stored) > config.streamReplaySize {
+		return []broadcast.Event{}, false, nil
+	}
+
+	events = make([]broadcast.Event, 0, len(stored))
+
+	for _, row := range stored {
+		event, ok, eventError := config.chirpEvent(ctx, row)
+
+		if eventError != nil {
+			return nil, false, eventError
+		}
+
+		if ok {
+			events = append(events, event)
+		}
+	}
+
+	return events, true, nil
+}
+
+// purgeChirpEvents drops stored events older than the retention window. Only
+// a run of the oldest ids is deleted, so every event after the oldest one
+// left is still there to replay.
+func (config *apiConfig) purgeChirpEvents(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, purgeError := config.db.PurgeChirpEvents(ctx, time.Now().Add(-config.chirpEventRetention)); purgeError != nil {
+			log.Printf("failed to purge chirp events: %v\n", purgeError)
+		}
+	}
+}
+
+func writeStreamEvent(responseWriter http.ResponseWriter, event broadcast.Event) error {
+	_, writeError := fmt.Fprintf(responseWriter, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Data)
+	return writeError
+}
+
+// streamChirps is a Server-Sent Events stream of chirps being created and
+// deleted. Clients that reconnect with Last-Event-ID, to this instance or
+// another, get what they missed from the replay buffer or else the stored
+// events; when those no longer reach back that far they get a "reset" event
+// and should reload with GET /api/chirps.
+func (config *apiConfig) streamChirps(responseWriter http.ResponseWriter, req *http.Request) {
+	authorID := req.URL.Query().Get("author_id")
+
+	viewer, audienceError := config.requestAudience(req)
+
+	if audienceError != nil {
+		server.SendInternalServerError(audienceError, responseWriter)
+		return
+	}
+
+	var lastEventID int64
+	resuming := false
+
+	if header := req.Header.Get("Last-Event-ID"); len(header) > 0 {
+		parsed, parseError := strconv.ParseInt(header, 10, 64)
+
+		if parseError != nil {
+			server.SendError("Last-Event-ID must be an event id", http.StatusBadRequest, responseWriter)
+			return
+		}
+
+		lastEventID, resuming = parsed, true
+	}
+
+	wanted := func(event broadcast.Event) bool {
+		return (len(authorID) == 0 || event.AuthorID == authorID) && viewer.canSee(event.AuthorID)
+	}
+
+	controller := http.NewResponseController(responseWriter)
+
+	// The stream stays open for as long as the client wants it.
+	controller.SetWriteDeadline(time.Time{})
+
+	subscription, replay, complete := config.chirpEvents.Subscribe(lastEventID, resuming)
+	defer config.chirpEvents.Unsubscribe(subscription)
+
+	if !complete {
+		stored, storedComplete, storedError := config.storedChirpEvents(req.Context(), lastEventID)
+
+		if storedError != nil {
+			server.SendInternalServerError(storedError, responseWriter)
+			return
+		}
+
+		replay, complete = stored, storedComplete
+	}
+
+	// Events read from the database may also be on their way through the
+	// subscription; anything up to the last one sent is skipped. After a
+	// reset the client starts over, so nothing is.
+	var sentID int64
+
+	if complete {
+		sentID = lastEventID
+	}
+
+	responseWriter.Header().Set("Content-Type", "text/event-stream")
+	responseWriter.Header().Set("Cache-Control", "no-cache")
+	responseWriter.Header().Set("X-Accel-Buffering", "no")
+	responseWriter.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(responseWriter, "retry: %d\n\n", streamRetryMillis)
+
+	if !complete {
+		fmt.Fprint(responseWriter, "event: reset\ndata: {}\n\n")
+	}
+
+	for _, event := range replay {
+		sentID = event.ID
+
+		if !wanted(event) {
+			continue
+		}
+
+		if writeError := writeStreamEvent(responseWriter, event); writeError != nil {
+			return
+		}
+	}
+
+	if flushError := controller.Flush(); flushError != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(config.streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case event, ok := <-subscription.Events:
+			// Dropped for falling behind.
+			if !ok {
+				return
+			}
+
+			if event.ID <= sentID || !wanted(event) {
+				continue
+			}
+
+			sentID = event.ID
+
+			if writeError := writeStreamEvent(responseWriter, event); writeError != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, writeError := fmt.Fprint(responseWriter, ": heartbeat\n\n"); writeError != nil {
+				return
+			}
+		}
+
+		if flushError := controller.Flush(); flushError != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/octaviocarpes/go-http-servers/internal/database"
+	server "github.com/octaviocarpes/go-http-servers/server"
+)
+
+const maxThreadDepth = 32
+
+func (config *apiConfig) getChirpThread(responseWriter http.ResponseWriter, req *http.Request) {
+	id := req.PathValue("id")
+	query := req.URL.Query()
+
+	limit, limitError := server.ParseLimit(query, 50, 200)
+
+	if limitError != nil {
+		server.SendError(limitError.Error(), http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	afterPath := ""
+
+	if cursor := query.Get("cursor"); len(cursor) > 0 {
+		decodedCursor, cursorError := server.DecodeCursor(cursor)
+
+		if cursorError != nil {
+			server.SendError(cursorError.Error(), http.StatusBadRequest, responseWriter)
+			return
+		}
+
+		afterPath = decodedCursor
+	}
+
+	chirp, getChirpError := config.db.GetChirpByID(req.Context(), id)
+
+	if getChirpError != nil {
+		server.SendError("chirp not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	viewer, audienceError := config.requestAudience(req)
+
+	if audienceError != nil {
+		server.SendInternalServerError(audienceError, responseWriter)
+		return
+	}
+
+	if !viewer.canSeeChirp(chirp) {
+		server.SendError("chirp not found", http.StatusNotFound, responseWriter)
+		return
+	}
+
+	ancestors, ancestorsError := config.db.GetChirpAncestors(req.Context(), id)
+
+	if ancestorsError != nil {
+		server.SendInternalServerError(ancestorsError, responseWriter)
+		return
+	}
+
+	// Ask for one extra row so we know whether another page exists.
+	descendants, descendantsError := config.db.ListChirpDescendants(req.Context(), database.ListChirpDescendantsParams{
+		RootID:    id,
+		MaxDepth:  maxThreadDepth,
+		AfterPath: afterPath,
+		PageSize:  limit + 1,
+	})
+
+	if descendantsError != nil {
+		server.SendInternalServerError(descendantsError, responseWriter)
+		return
+	}
+
+	nextCursor := ""
+
+	if len(descendants) > int(limit) {
+		descendants = descendants[:limit]
+		nextCursor = server.EncodeCursor(descendants[len(descendants)-1].Path)
+	}
+
+	chirps := make([]database.Chirp, 0, 1+len(ancestors)+len(descendants))
+	chirps = append(chirps, chirp)
+
+	for _, ancestor := range ancestors {
+		chirps = append(chirps, database.Chirp(ancestor))
+	}
+
+	for _, descendant := range descendants {
+		chirps = append(chirps, database.Chirp{
+			ID:        descendant.ID,
+			Body:      descendant.Body,
+			UserID:    descendant.UserID,
+			CreatedAt: descendant.CreatedAt,
+			UpdatedAt: descendant.UpdatedAt,
+			DeletedAt: descendant.DeletedAt,
+			InReplyTo: descendant.InReplyTo,
+			RechirpOf: descendant.RechirpOf,
+			QuoteOf:   descendant.QuoteOf,
+			LikeCount: descendant.LikeCount,
+			PublishAt: descendant.PublishAt,
+			HiddenAt:  descendant.HiddenAt,
+		})
+	}
+
+	responses, buildResponseError := config.buildChirpResponses(req.Context(), viewer, chirps)
+
+	if buildResponseError != nil {
+		server.SendInternalServerError(buildResponseError, responseWriter)
+		return
+	}
+
+	type threadReplyResponse struct {
+		chirpResponse
+		Depth int32 `json:"depth"`
+	}
+
+	type threadResponse struct {
+		Chirp      chirpResponse         `json:"chirp"`
+		Ancestors  []chirpResponse       `json:"ancestors"`
+		Replies    []threadReplyResponse `json:"replies"`
+		NextCursor string                `json:"next_cursor,omitempty"`
+	}
+
+	response := threadResponse{
+		Chirp:      responses[0],
+		Ancestors:  responses[1 : 1+len(ancestors)],
+		Replies:    make([]threadReplyResponse, len(descendants)),
+		NextCursor: nextCursor,
+	}
+
+	for i, descendant := range descendants {
+		response.Replies[i] = threadReplyResponse{
+			chirpResponse: responses[1+len(ancestors)+i],
+			Depth:         descendant.Depth,
+		}
+	}
+
+	server.ResponseWithJson(response, http.StatusOK, responseWriter)
+}
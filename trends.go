@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/octaviocarpes/go-http-servers/internal/database"
+	server "github.com/octaviocarpes/go-http-servers/server"
+)
+
+func (config *apiConfig) listHashtagChirps(responseWriter http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	tag := strings.ToLower(strings.TrimPrefix(req.PathValue("tag"), "#"))
+
+	limit, limitError := server.ParseLimit(query, 50, 200)
+
+	if limitError != nil {
+		server.SendError(limitError.Error(), http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	beforeCreatedAt, beforeChirpID, cursorError := server.ParseTimeCursor(query)
+
+	if cursorError != nil {
+		server.SendError(cursorError.Error(), http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	viewer, audienceError := config.requestAudience(req)
+
+	if audienceError != nil {
+		server.SendInternalServerError(audienceError, responseWriter)
+		return
+	}
+
+	rows, listChirpsError := config.db.ListHashtagChirps(req.Context(), database.ListHashtagChirpsParams{
+		Tag:             tag,
+		HiddenAuthorIds: viewer.feedHidden(),
+		BeforeCreatedAt: beforeCreatedAt,
+		BeforeChirpID:   beforeChirpID,
+		PageSize:        limit + 1,
+	})
+
+	if listChirpsError != nil {
+		server.SendInternalServerError(listChirpsError, responseWriter)
+		return
+	}
+
+	nextCursor := ""
+
+	if len(rows) > int(limit) {
+		rows = rows[:limit]
+		last := rows[len(rows)-1].Chirp
+		nextCursor = server.EncodeTimeCursor(last.CreatedAt, last.ID)
+	}
+
+	chirps := make([]database.Chirp, len(rows))
+
+	for i, row := range rows {
+		chirps[i] = row.Chirp
+	}
+
+	chirpResponses, buildResponseError := config.buildChirpResponses(req.Context(), viewer, chirps)
+
+	if buildResponseError != nil {
+		server.SendInternalServerError(buildResponseError, responseWriter)
+		return
+	}
+
+	type hashtagChirpsResponse struct {
+		Tag        string          `json:"tag"`
+		Chirps     []chirpResponse `json:"chirps"`
+		NextCursor string          `json:"next_cursor,omitempty"`
+	}
+
+	response := hashtagChirpsResponse{
+		Tag:        tag,
+		Chirps:     chirpResponses,
+		NextCursor: nextCursor,
+	}
+
+	server.ResponseWithJson(response, http.StatusOK, responseWriter)
+}
+
+func (config *apiConfig) listTrends(responseWriter http.ResponseWriter, req *http.Request) {
+	limit, limitError := server.ParseLimit(req.URL.Query(), 10, 50)
+
+	if limitError != nil {
+		server.SendError(limitError.Error(), http.StatusBadRequest, responseWriter)
+		return
+	}
+
+	trends, listTrendsError := config.db.ListHashtagTrends(req.Context(), limit)
+
+	if listTrendsError != nil {
+		server.SendInternalServerError(listTrendsError, responseWriter)
+		return
+	}
+
+	type trendResponse struct {
+		Tag   string  `json:"tag"`
+		Score float64 `json:"score"`
+		Uses  int64   `json:"uses"`
+	}
+
+	type trendsResponse struct {
+		Trends      []trendResponse `json:"trends"`
+		RefreshedAt *time.Time      `json:"refreshed_at"`
+	}
+
+	response := trendsResponse{
+		Trends: make([]trendResponse, len(trends)),
+	}
+
+	for i, trend := range trends {
+		response.Trends[i] = trendResponse{
+			Tag:   trend.Tag,
+			Score: trend.Score,
+			Uses:  trend.Uses,
+		}
+		response.RefreshedAt = &trend.RefreshedAt
+	}
+
+	server.ResponseWithJson(response, http.StatusOK, responseWriter)
+}
+
+// refreshTrends recomputes the trending hashtags on every tick. Each use of a
+// tag inside the window scores 1, halving every trendsHalfLife, so recent
+// bursts outrank tags that were popular hours ago.
+func (config *apiConfig) refreshTrends(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if refreshError := config.refreshTrendsOnce(ctx); refreshError != nil {
+			log.Printf("failed to refresh trends: %v\n", refreshError)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (config *apiConfig) refreshTrendsOnce(ctx context.Context) error {
+	tx, beginError := config.conn.BeginTx(ctx, nil)
+
+	if beginError != nil {
+		return beginError
+	}
+
+	defer tx.Rollback()
+
+	queries := config.db.WithTx(tx)
+
+	if clearError := queries.ClearHashtagTrends(ctx); clearError != nil {
+		return clearError
+	}
+
+	insertError := queries.InsertHashtagTrends(ctx, database.InsertHashtagTrendsParams{
+		HalfLifeSeconds: config.trendsHalfLife.Seconds(),
+		WindowSeconds:   config.trendsWindow.Seconds(),
+	})
+
+	if insertError != nil {
+		return insertError
+	}
+
+	return tx.Commit()
+}
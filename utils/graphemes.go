@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+type graphemeClass int
+
+const (
+	classOther graphemeClass = iota
+	classCR
+	classLF
+	classControl
+	classExtend
+	classZWJ
+	classRegionalIndicator
+	classSpacingMark
+	classL
+	classV
+	classT
+	classLV
+	classLVT
+	classPictographic
+)
+
+// CountGraphemes returns the number of user-perceived characters in s, so an
+// emoji with a skin tone, a flag or a letter with combining accents counts as
+// one. It follows the extended grapheme cluster rules of Unicode UAX #29,
+// without the rarely used Prepend class and the Indic conjunct rule.
+func CountGraphemes(s string) int {
+	count := 0
+	previous := classOther
+	// Consecutive regional indicators right before the current rune.
+	regionalIndicators := 0
+	// Inside "Extended_Pictographic Extend*", and whether a ZWJ followed it.
+	inPictographic, afterPictographicZWJ := false, false
+
+	for i, r := range s {
+		current := classify(r)
+
+		if i == 0 || graphemeBoundary(previous, current, regionalIndicators, afterPictographicZWJ) {
+			count++
+		}
+
+		afterPictographicZWJ = current == classZWJ && inPictographic
+
+		switch current {
+		case classPictographic:
+			inPictographic = true
+		case classExtend:
+		default:
+			inPictographic = false
+		}
+
+		if current == classRegionalIndicator {
+			regionalIndicators++
+		} else {
+			regionalIndicators = 0
+		}
+
+		previous = current
+	}
+
+	return count
+}
+
+func graphemeBoundary(previous, current graphemeClass, regionalIndicators int, afterPictographicZWJ bool) bool {
+	switch {
+	case previous == classCR && current == classLF:
+		return false
+	case previous == classCR || previous == classLF || previous == classControl:
+		return true
+	case current == classCR || current == classLF || current == classControl:
+		return true
+	case previous == classL && (current == classL || current == classV || current == classLV || current == classLVT):
+		return false
+	case (previous == classLV || previous == classV) && (current == classV || current == classT):
+		return false
+	case (previous == classLVT || previous == classT) && current == classT:
+		return false
+	case current == classExtend || current == classZWJ || current == classSpacingMark:
+		return false
+	case afterPictographicZWJ && current == classPictographic:
+		return false
+	case previous == classRegionalIndicator && current == classRegionalIndicator:
+		return regionalIndicators%2 == 0
+	}
+
+	return true
+}
+
+func classify(r rune) graphemeClass {
+	switch {
+	case r == '\r':
+		return classCR
+	case r == '\n':
+		return classLF
+	case r == 0x200D:
+		return classZWJ
+	case r == 0x200C, r >= 0x1F3FB && r <= 0x1F3FF, r >= 0xE0020 && r <= 0xE007F, r == 0xFF9E, r == 0xFF9F:
+		return classExtend
+	case unicode.In(r, unicode.Mn, unicode.Me):
+		return classExtend
+	case unicode.Is(unicode.Mc, r):
+		return classSpacingMark
+	case unicode.In(r, unicode.Cc, unicode.Zl, unicode.Zp, unicode.Cf), r == utf8.RuneError:
+		return classControl
+	case r >= 0x1F1E6 && r <= 0x1F1FF:
+		return classRegionalIndicator
+	case r >= 0x1100 && r <= 0x115F, r >= 0xA960 && r <= 0xA97C:
+		return classL
+	case r >= 0x1160 && r <= 0x11A7, r >= 0xD7B0 && r <= 0xD7C6:
+		return classV
+	case r >= 0x11A8 && r <= 0x11FF, r >= 0xD7CB && r <= 0xD7FB:
+		return classT
+	case r >= 0xAC00 && r <= 0xD7A3:
+		if (r-0xAC00)%28 == 0 {
+			return classLV
+		}
+
+		return classLVT
+	case isPictographic(r):
+		return classPictographic
+	}
+
+	return classOther
+}
+
+// pictographicRanges approximates the Extended_Pictographic property with the
+// blocks that hold emoji.
+var pictographicRanges = [][2]rune{
+	{0x00A9, 0x00A9}, {0x00AE, 0x00AE}, {0x203C, 0x203C}, {0x2049, 0x2049},
+	{0x2122, 0x2122}, {0x2139, 0x2139}, {0x2194, 0x21AA}, {0x231A, 0x23FF},
+	{0x24C2, 0x24C2}, {0x25AA, 0x25FE}, {0x2600, 0x27BF}, {0x2934, 0x2935},
+	{0x2B05, 0x2B55}, {0x3030, 0x3030}, {0x303D, 0x303D}, {0x3297, 0x3299},
+	{0x1F000, 0x1F1E5}, {0x1F200, 0x1F3FA}, {0x1F400, 0x1FAFF}, {0x1FC00, 0x1FFFD},
+}
+
+func isPictographic(r rune) bool {
+	for _, bounds := range pictographicRanges {
+		if r >= bounds[0] && r <= bounds[1] {
+			return true
+		}
+	}
+
+	return false
+}
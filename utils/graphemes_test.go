@@ -0,0 +1,31 @@
+package utils
+
+import "testing"
+
+func TestCountGraphemes(t *testing.T) {
+	cases := map[string]int{
+		"":                                 0,
+		"chirp":                            5,
+		"café":                             4,
+		"café":                            4,
+		"\U0001F44D\U0001F3FD":             1,
+		"\U0001F468‍\U0001F469‍\U0001F467": 1,
+		"\U0001F1E7\U0001F1F7\U0001F1FA\U0001F1F8": 2,
+		"\U0001F1E7\U0001F1F7\U0001F1FA":           2,
+		"1️⃣":                                      1,
+		"각":                                      1,
+		"각":                                        1,
+		"नि":                                       1,
+		"a\r\nb":                                   3,
+		"日本語":                                      3,
+		"❤️":                                       1,
+		"\U0001F3F3️‍\U0001F308 flag":              6,
+		"\U0001F3F4\U000E0067\U000E0062\U000E0073\U000E0063\U000E0074\U000E007F": 1,
+	}
+
+	for input, want := range cases {
+		if got := CountGraphemes(input); got != want {
+			t.Errorf("CountGraphemes(%q) = %d, want %d", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+package utils
+
+import "regexp"
+
+var handlePattern = regexp.MustCompile(`^[A-Za-z0-9_]{3,15}$`)
+
+// IsValidHandle reports whether handle can be used as a username: 3 to 15
+// ASCII letters, digits or underscores.
+func IsValidHandle(handle string) bool {
+	return handlePattern.MatchString(handle)
+}
@@ -0,0 +1,21 @@
+package utils
+
+import "testing"
+
+func TestIsValidHandle(t *testing.T) {
+	cases := map[string]bool{
+		"chirpy":           true,
+		"Chirpy_Bird_42":   true,
+		"ab":               false,
+		"this_is_too_long": false,
+		"with space":       false,
+		"dash-ed":          false,
+		"émile":            false,
+	}
+
+	for handle, want := range cases {
+		if got := IsValidHandle(handle); got != want {
+			t.Fatalf("IsValidHandle(%q) = %v, want %v\n", handle, got, want)
+		}
+	}
+}
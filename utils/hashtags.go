@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const maxHashtagLength = 100
+
+// ExtractHashtags returns the distinct #hashtags in body, lowercased, in the
+// order they first appear. A tag must start at a word boundary and contain at
+// least one letter, so "#1" and "a#b" are not tags.
+func ExtractHashtags(body string) []string {
+	return extractTagged(body, '#')
+}
+
+func extractTagged(body string, marker rune) []string {
+	tags := []string{}
+	seen := map[string]bool{}
+	previous := ' '
+
+	for i, r := range body {
+		if r != marker || isTagRune(previous) || previous == marker {
+			previous = r
+			continue
+		}
+
+		previous = r
+		start := i + utf8.RuneLen(r)
+		end := start
+		hasLetter := false
+
+		for end < len(body) {
+			next, size := utf8.DecodeRuneInString(body[end:])
+
+			if !isTagRune(next) {
+				break
+			}
+
+			hasLetter = hasLetter || unicode.IsLetter(next)
+			end += size
+		}
+
+		tag := strings.ToLower(body[start:end])
+
+		if !hasLetter || utf8.RuneCountInString(tag) > maxHashtagLength || seen[tag] {
+			continue
+		}
+
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+
+	return tags
+}
+
+func isTagRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractHashtags(t *testing.T) {
+	cases := []struct {
+		body string
+		want []string
+	}{
+		{"no tags here", []string{}},
+		{"#Go is fun", []string{"go"}},
+		{"I love #golang, #GoLang and #rust!", []string{"golang", "rust"}},
+		{"#1 is not a tag but #web3 is", []string{"web3"}},
+		{"email#notatag ##double", []string{}},
+		{"#café #日本", []string{"café", "日本"}},
+		{"#snake_case.", []string{"snake_case"}},
+	}
+
+	for _, c := range cases {
+		got := ExtractHashtags(c.body)
+
+		if !reflect.DeepEqual(got, c.want) {
+			t.Fatalf("ExtractHashtags(%q) = %v, want %v\n", c.body, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package utils
+
+import "strings"
+
+// URLWeight is how many characters a link costs in a chirp, however long it
+// is, so shortened and full URLs are treated alike.
+const URLWeight = 23
+
+// ChirpLength measures body the way the chirp limit counts it: grapheme
+// clusters, with every http or https URL counted as URLWeight.
+func ChirpLength(body string) int {
+	length := 0
+
+	for {
+		start := urlStart(body)
+
+		if start < 0 {
+			return length + CountGraphemes(body)
+		}
+
+		end := start + strings.IndexFunc(body[start:], isURLTerminator)
+
+		if end < start {
+			end = len(body)
+		}
+
+		length += CountGraphemes(body[:start]) + URLWeight
+		body = body[end:]
+	}
+}
+
+// urlStart finds the first http:// or https:// that begins a word.
+func urlStart(body string) int {
+	offset := 0
+
+	for {
+		index := strings.Index(body[offset:], "http")
+
+		if index < 0 {
+			return -1
+		}
+
+		index += offset
+		rest := body[index:]
+		atWordStart := index == 0 || isURLTerminator(rune(body[index-1]))
+
+		for _, scheme := range []string{"http://", "https://"} {
+			if atWordStart && strings.HasPrefix(rest, scheme) && len(rest) > len(scheme) && !isURLTerminator(rune(rest[len(scheme)])) {
+				return index
+			}
+		}
+
+		offset = index + len("http")
+	}
+}
+
+func isURLTerminator(r rune) bool {
+	return r == ' ' || r == '\n' || r == '\t' || r == '\r'
+}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChirpLength(t *testing.T) {
+	longURL := "https://example.com/" + strings.Repeat("a", 100)
+
+	cases := map[string]int{
+		"hello": 5,
+		"\U0001F44D\U0001F3FD\U0001F44D\U0001F3FD\U0001F44D\U0001F3FD": 3,
+		longURL:                         URLWeight,
+		"see " + longURL:                4 + URLWeight,
+		"see " + longURL + " now":       4 + URLWeight + 4,
+		"http://a http://b":             URLWeight + 1 + URLWeight,
+		"http:// is not a link":         21,
+		"nothttps://example.com":        22,
+		"ftp://example.com":             17,
+		"links: https://a.io\nhttp://b": 7 + URLWeight + 1 + URLWeight,
+	}
+
+	for input, want := range cases {
+		if got := ChirpLength(input); got != want {
+			t.Errorf("ChirpLength(%q) = %d, want %d", input, got, want)
+		}
+	}
+}
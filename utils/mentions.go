@@ -0,0 +1,7 @@
+package utils
+
+// ExtractMentions returns the distinct @mentions in body, lowercased and
+// without the leading @, in the order they first appear.
+func ExtractMentions(body string) []string {
+	return extractTagged(body, '@')
+}
@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractMentions(t *testing.T) {
+	cases := []struct {
+		body string
+		want []string
+	}{
+		{"hello there", []string{}},
+		{"hey @Alice and @bob, meet @alice", []string{"alice", "bob"}},
+		{"mail me at someone@example.com", []string{}},
+		{"(@carol) #tag", []string{"carol"}},
+	}
+
+	for _, c := range cases {
+		got := ExtractMentions(c.body)
+
+		if !reflect.DeepEqual(got, c.want) {
+			t.Fatalf("ExtractMentions(%q) = %v, want %v\n", c.body, got, c.want)
+		}
+	}
+}